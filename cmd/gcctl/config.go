@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	envBaseURL   = "GC_BASE_URL"
+	envAPIKey    = "GC_API_KEY"
+	envBackendID = "GC_BACKEND_ID"
+	envProfile   = "GCCTL_PROFILE"
+	envConfig    = "GCCTL_CONFIG"
+
+	defaultProfile = "default"
+)
+
+// settings are the resolved connection settings.
+type settings struct {
+	BaseURL   string `yaml:"base-url"`
+	APIKey    string `yaml:"api-key"`
+	BackendID string `yaml:"backend-id"`
+}
+
+// configFile is the gcctl config file:
+//
+//	current-profile: prod
+//	profiles:
+//	  prod:
+//	    base-url: https://api.groundcover.com
+//	    api-key: ...
+//	    backend-id: ...
+type configFile struct {
+	CurrentProfile string               `yaml:"current-profile"`
+	Profiles       map[string]*settings `yaml:"profiles"`
+}
+
+type globalFlags struct {
+	settings
+	profile    string
+	configPath string
+	output     string
+	timeout    time.Duration
+}
+
+func (a *app) registerGlobalFlags(fs *flag.FlagSet) *globalFlags {
+	flags := &globalFlags{output: outputTable}
+	fs.StringVar(&flags.BaseURL, "base-url", "", "groundcover API base URL (env "+envBaseURL+")")
+	fs.StringVar(&flags.APIKey, "api-key", "", "API key (env "+envAPIKey+")")
+	fs.StringVar(&flags.BackendID, "backend-id", "", "backend ID (env "+envBackendID+")")
+	fs.StringVar(&flags.profile, "profile", "", "config profile (env "+envProfile+")")
+	fs.StringVar(&flags.configPath, "config", "", "config file (env "+envConfig+", default $XDG_CONFIG_HOME/gcctl/config.yaml)")
+	fs.DurationVar(&flags.timeout, "timeout", defaultTimeout, "overall command timeout")
+	registerOutputFlags(fs, &flags.output)
+	return flags
+}
+
+// resolveSettings merges flags, environment variables and the config
+// profile, in that order of precedence.
+func resolveSettings(flags *globalFlags, getenv func(string) string) (settings, error) {
+	profile, err := loadProfile(flags, getenv)
+	if err != nil {
+		return settings{}, err
+	}
+
+	resolved := settings{
+		BaseURL:   firstNonEmpty(flags.BaseURL, getenv(envBaseURL), profile.BaseURL),
+		APIKey:    firstNonEmpty(flags.APIKey, getenv(envAPIKey), profile.APIKey),
+		BackendID: firstNonEmpty(flags.BackendID, getenv(envBackendID), profile.BackendID),
+	}
+
+	switch {
+	case resolved.BaseURL == "":
+		return settings{}, usagef("no base URL: set --base-url, %s or a profile", envBaseURL)
+	case resolved.APIKey == "":
+		return settings{}, usagef("no API key: set --api-key, %s or a profile", envAPIKey)
+	case resolved.BackendID == "":
+		return settings{}, usagef("no backend ID: set --backend-id, %s or a profile", envBackendID)
+	}
+	return resolved, nil
+}
+
+// loadProfile returns the selected profile of the config file. A missing
+// config file is only an error when a profile was requested explicitly.
+func loadProfile(flags *globalFlags, getenv func(string) string) (settings, error) {
+	path := firstNonEmpty(flags.configPath, getenv(envConfig))
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return settings{}, nil
+		}
+		path = filepath.Join(dir, "gcctl", "config.yaml")
+	}
+
+	requested := firstNonEmpty(flags.profile, getenv(envProfile))
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && requested == "" {
+		return settings{}, nil
+	}
+	if err != nil {
+		return settings{}, fmt.Errorf("error reading config: %w", err)
+	}
+
+	config := &configFile{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return settings{}, fmt.Errorf("error parsing config %s: %w", path, err)
+	}
+
+	name := firstNonEmpty(requested, config.CurrentProfile, defaultProfile)
+	profile, ok := config.Profiles[name]
+	if !ok {
+		if requested == "" {
+			return settings{}, nil
+		}
+		return settings{}, usagef("profile %q not found in %s", name, path)
+	}
+	if profile == nil {
+		return settings{}, fmt.Errorf("error parsing config %s: profile %q has no settings", path, name)
+	}
+	return *profile, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
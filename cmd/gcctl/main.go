@@ -0,0 +1,245 @@
+// Command gcctl manages groundcover resources from the command line.
+//
+//	gcctl [global flags] <resource> <command> [flags] [args]
+//
+// Credentials come from flags, the GC_BASE_URL, GC_API_KEY and GC_BACKEND_ID
+// environment variables, or a profile in the gcctl config file, in that order.
+// Run "gcctl help" for the list of resources.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/apierrors"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/groundcover"
+)
+
+// Exit codes.
+const (
+	exitOK       = 0
+	exitError    = 1 // API and other runtime errors
+	exitUsage    = 2 // invalid flags or arguments
+	exitAPIError = 3 // the API rejected the request
+)
+
+const defaultTimeout = 60 * time.Second
+
+// usageError marks errors caused by invalid invocations.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// app holds what every command needs.
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	settings settings
+	output   string
+	client   *groundcover.Client
+}
+
+// command is a verb of a resource, e.g. "monitors list".
+type command struct {
+	usage string // arguments and flags, e.g. "<id> -f FILE"
+	help  string
+	flags func(fs *flag.FlagSet) // registers command flags, may be nil
+	run   func(ctx context.Context, a *app, fs *flag.FlagSet) error
+}
+
+// resource groups the commands of a resource type.
+type resource struct {
+	help     string
+	commands map[string]*command
+}
+
+var resources = map[string]*resource{}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := &app{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	os.Exit(a.run(ctx, os.Args[1:]))
+}
+
+// run executes gcctl with args and returns the process exit code.
+func (a *app) run(ctx context.Context, args []string) int {
+	err := a.dispatch(ctx, args)
+	if err == nil {
+		return exitOK
+	}
+
+	fmt.Fprintf(a.stderr, "Error: %v\n", err)
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return exitUsage
+	}
+	if _, ok := apierrors.As(err); ok {
+		return exitAPIError
+	}
+	return exitError
+}
+
+func (a *app) dispatch(ctx context.Context, args []string) error {
+	global := flag.NewFlagSet("gcctl", flag.ContinueOnError)
+	global.SetOutput(io.Discard)
+	flags := a.registerGlobalFlags(global)
+
+	if err := global.Parse(args); err != nil {
+		return usagef("%v", err)
+	}
+
+	rest := global.Args()
+	if len(rest) == 0 || rest[0] == "help" {
+		a.printUsage(rest)
+		return nil
+	}
+
+	res, ok := resources[rest[0]]
+	if !ok {
+		return usagef("unknown resource %q, run \"gcctl help\" for the list of resources", rest[0])
+	}
+	if len(rest) < 2 {
+		a.printResourceUsage(rest[0], res)
+		return usagef("missing command for %s", rest[0])
+	}
+
+	cmd, ok := res.commands[rest[1]]
+	if !ok {
+		a.printResourceUsage(rest[0], res)
+		return usagef("unknown command %q for %s", rest[1], rest[0])
+	}
+
+	fs := flag.NewFlagSet("gcctl "+rest[0]+" "+rest[1], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	registerOutputFlags(fs, &flags.output)
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+	if err := fs.Parse(interspersed(fs, rest[2:])); err != nil {
+		return usagef("%v\nusage: gcctl %s %s %s", err, rest[0], rest[1], cmd.usage)
+	}
+
+	a.output = flags.output
+	if err := validateOutput(a.output); err != nil {
+		return err
+	}
+
+	settings, err := resolveSettings(flags, a.getenv)
+	if err != nil {
+		return err
+	}
+	a.settings = settings
+
+	if a.client, err = groundcover.New(settings.APIKey, settings.BackendID, settings.BaseURL); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, flags.timeout)
+	defer cancel()
+	return cmd.run(ctx, a, fs)
+}
+
+// interspersed moves positional arguments after the flags, so that
+// "monitors get ID -o json" parses like "monitors get -o json ID".
+func interspersed(fs *flag.FlagSet, args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+
+		flags = append(flags, arg)
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := fs.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+	return append(flags, positional...)
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func (a *app) printUsage(args []string) {
+	if len(args) > 1 {
+		if res, ok := resources[args[1]]; ok {
+			a.printResourceUsage(args[1], res)
+			return
+		}
+	}
+
+	fmt.Fprintln(a.stdout, "Usage: gcctl [global flags] <resource> <command> [flags] [args]")
+	fmt.Fprintln(a.stdout)
+	fmt.Fprintln(a.stdout, "Resources:")
+	for _, name := range sortedKeys(resources) {
+		fmt.Fprintf(a.stdout, "  %-18s %s\n", name, resources[name].help)
+	}
+	fmt.Fprintln(a.stdout)
+	fmt.Fprintln(a.stdout, "Global flags:")
+	global := flag.NewFlagSet("gcctl", flag.ContinueOnError)
+	a.registerGlobalFlags(global)
+	global.SetOutput(a.stdout)
+	global.PrintDefaults()
+}
+
+func (a *app) printResourceUsage(name string, res *resource) {
+	fmt.Fprintf(a.stdout, "Usage: gcctl %s <command> [flags] [args]\n\nCommands:\n", name)
+	for _, verb := range sortedKeys(res.commands) {
+		cmd := res.commands[verb]
+		fmt.Fprintf(a.stdout, "  %-12s %s\n", verb, cmd.help)
+		if cmd.usage != "" {
+			fmt.Fprintf(a.stdout, "  %-12s   gcctl %s %s %s\n", "", name, verb, cmd.usage)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// requireArgs checks the number of positional arguments.
+func requireArgs(fs *flag.FlagSet, names ...string) ([]string, error) {
+	if fs.NArg() != len(names) {
+		return nil, usagef("expected arguments: %s", strings.Join(names, " "))
+	}
+	return fs.Args(), nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+func runGCCtl(t *testing.T, env map[string]string, args ...string) result {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	a := &app{
+		stdin:  strings.NewReader(""),
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(key string) string { return env[key] },
+	}
+	code := a.run(context.Background(), args)
+	return result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+func newTestEnv(t *testing.T, handler http.HandlerFunc) map[string]string {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return map[string]string{
+		envBaseURL:   server.URL,
+		envAPIKey:    "token",
+		envBackendID: "backend-1",
+		envConfig:    filepath.Join(t.TempDir(), "missing.yaml"),
+	}
+}
+
+func TestPoliciesListOutputs(t *testing.T) {
+	env := newTestEnv(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"uuid":"p-1","name":"admins","description":"full access","revisionNumber":2,"entityCount":3}]`))
+	})
+
+	res := runGCCtl(t, env, "policies", "list")
+	if res.code != exitOK {
+		t.Fatalf("expected exit 0, got %d: %s", res.code, res.stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(res.stdout), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[1], "admins") {
+		t.Errorf("unexpected table:\n%s", res.stdout)
+	}
+
+	res = runGCCtl(t, env, "policies", "list", "-o", "json")
+	var policies []map[string]interface{}
+	if err := json.Unmarshal([]byte(res.stdout), &policies); err != nil || policies[0]["name"] != "admins" {
+		t.Errorf("unexpected JSON output %q: %v", res.stdout, err)
+	}
+
+	res = runGCCtl(t, env, "--output", "yaml", "policies", "list")
+	if !strings.HasPrefix(res.stdout, "- ") || !strings.Contains(res.stdout, "  name: admins\n") {
+		t.Errorf("unexpected YAML output:\n%s", res.stdout)
+	}
+}
+
+func TestMonitorsCreateFromFile(t *testing.T) {
+	var body string
+	env := newTestEnv(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"monitorId":"m-1"}`))
+	})
+
+	path := filepath.Join(t.TempDir(), "monitor.yaml")
+	os.WriteFile(path, []byte("title: High CPU\nseverity: S2\n"), 0o600)
+
+	res := runGCCtl(t, env, "monitors", "create", "-f", path)
+	if res.code != exitOK {
+		t.Fatalf("expected exit 0, got %d: %s", res.code, res.stderr)
+	}
+	if strings.TrimSpace(res.stdout) != "monitor m-1 created" {
+		t.Errorf("unexpected output %q", res.stdout)
+	}
+	if !strings.Contains(body, "title: High CPU") {
+		t.Errorf("expected the monitor to be sent as YAML, got %q", body)
+	}
+}
+
+func TestAPIErrorExitCode(t *testing.T) {
+	env := newTestEnv(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"silence not found"}`))
+	})
+
+	res := runGCCtl(t, env, "silences", "get", "s-1")
+	if res.code != exitAPIError {
+		t.Errorf("expected exit %d, got %d", exitAPIError, res.code)
+	}
+	if !strings.Contains(res.stderr, "silence not found") {
+		t.Errorf("expected the API message on stderr, got %q", res.stderr)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	env := newTestEnv(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+	})
+
+	for _, args := range [][]string{
+		{"unknown"},
+		{"monitors", "explode"},
+		{"monitors", "get"},
+		{"monitors", "list", "-o", "xml"},
+		{"metrics", "query"},
+	} {
+		if res := runGCCtl(t, env, args...); res.code != exitUsage {
+			t.Errorf("%v: expected exit %d, got %d (%s)", args, exitUsage, res.code, res.stderr)
+		}
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	var backendID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backendID = r.Header.Get("X-Backend-Id")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(config, []byte(`current-profile: staging
+profiles:
+  staging:
+    base-url: `+server.URL+`
+    api-key: staging-key
+    backend-id: staging-backend
+  prod:
+    base-url: `+server.URL+`
+    api-key: prod-key
+    backend-id: prod-backend
+`), 0o600)
+
+	env := map[string]string{envConfig: config}
+
+	if res := runGCCtl(t, env, "policies", "list"); res.code != exitOK || backendID != "staging-backend" {
+		t.Errorf("current profile: exit %d, backend %q, %s", res.code, backendID, res.stderr)
+	}
+	if res := runGCCtl(t, env, "--profile", "prod", "policies", "list"); res.code != exitOK || backendID != "prod-backend" {
+		t.Errorf("--profile: exit %d, backend %q, %s", res.code, backendID, res.stderr)
+	}
+
+	env[envBackendID] = "env-backend"
+	if res := runGCCtl(t, env, "policies", "list"); res.code != exitOK || backendID != "env-backend" {
+		t.Errorf("env override: exit %d, backend %q, %s", res.code, backendID, res.stderr)
+	}
+
+	if res := runGCCtl(t, env, "--profile", "missing", "policies", "list"); res.code != exitUsage {
+		t.Errorf("missing profile: expected exit %d, got %d", exitUsage, res.code)
+	}
+
+	empty := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(empty, []byte("profiles:\n  prod:\n"), 0o600)
+	res := runGCCtl(t, map[string]string{envConfig: empty}, "--profile", "prod", "policies", "list")
+	if res.code != exitError || !strings.Contains(res.stderr, `profile "prod" has no settings`) {
+		t.Errorf("empty profile: exit %d, %s", res.code, res.stderr)
+	}
+}
+
+func TestMetricsTable(t *testing.T) {
+	result := map[string]interface{}{
+		"status": "success",
+		"data": map[string]interface{}{
+			"resultType": "vector",
+			"result": []interface{}{
+				map[string]interface{}{
+					"metric": map[string]interface{}{"__name__": "up", "job": "api"},
+					"value":  []interface{}{float64(1700000000), "1"},
+				},
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	metricsTable(result).write(&out)
+	if !strings.Contains(out.String(), `up{job="api"}   2023-11-14T22:13:20Z   1`) {
+		t.Errorf("unexpected table:\n%s", out.String())
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/transport"
+)
+
+// Output formats.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+func registerOutputFlags(fs *flag.FlagSet, output *string) {
+	usage := "output format: table, json or yaml"
+	fs.StringVar(output, "output", *output, usage)
+	fs.StringVar(output, "o", *output, usage)
+}
+
+func validateOutput(output string) error {
+	switch output {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	default:
+		return usagef("invalid output format %q: expected table, json or yaml", output)
+	}
+}
+
+// table is the tabular rendering of a result.
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+func (t *table) add(cells ...interface{}) {
+	row := make([]string, len(cells))
+	for i, cell := range cells {
+		row[i] = formatCell(cell)
+	}
+	t.rows = append(t.rows, row)
+}
+
+func (t *table) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case float64:
+		return fmt.Sprintf("%.4g", v)
+	case fmt.Stringer:
+		return v.String()
+	case []interface{}, map[string]interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// print renders value in the selected format. toTable builds the table
+// rendering; when nil, tables fall back to a generic rendering of the JSON value.
+func (a *app) print(value interface{}, toTable func() *table) error {
+	switch a.output {
+	case outputJSON:
+		encoder := json.NewEncoder(a.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case outputYAML:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if data, err = transport.JSONToYAML(data); err != nil {
+			return err
+		}
+		_, err = a.stdout.Write(data)
+		return err
+	default:
+		if toTable == nil {
+			return genericTable(value).write(a.stdout)
+		}
+		return toTable().write(a.stdout)
+	}
+}
+
+// printMessage prints a confirmation for commands without a result.
+func (a *app) printMessage(format string, args ...interface{}) error {
+	if a.output != outputTable {
+		return a.print(map[string]string{"result": fmt.Sprintf(format, args...)}, nil)
+	}
+	_, err := fmt.Fprintf(a.stdout, format+"\n", args...)
+	return err
+}
+
+// genericTable renders untyped JSON values, e.g. search results: a list of
+// objects becomes one row per object with a column per key.
+func genericTable(value interface{}) *table {
+	data, _ := json.Marshal(value)
+	var doc interface{}
+	json.Unmarshal(data, &doc)
+
+	rows, ok := doc.([]interface{})
+	if !ok {
+		if object, isObject := doc.(map[string]interface{}); isObject {
+			t := &table{headers: []string{"KEY", "VALUE"}}
+			for _, key := range sortedKeys(object) {
+				t.add(key, object[key])
+			}
+			return t
+		}
+		rows = []interface{}{doc}
+	}
+
+	columns := map[string]bool{}
+	for _, row := range rows {
+		if object, isObject := row.(map[string]interface{}); isObject {
+			for key := range object {
+				columns[key] = true
+			}
+		}
+	}
+	if len(columns) == 0 {
+		t := &table{headers: []string{"VALUE"}}
+		for _, row := range rows {
+			t.add(row)
+		}
+		return t
+	}
+
+	keys := sortedKeys(columns)
+	t := &table{}
+	for _, key := range keys {
+		t.headers = append(t.headers, strings.ToUpper(key))
+	}
+	for _, row := range rows {
+		object, _ := row.(map[string]interface{})
+		cells := make([]interface{}, len(keys))
+		for i, key := range keys {
+			cells[i] = object[key]
+		}
+		t.add(cells...)
+	}
+	return t
+}
+
+// readBody decodes a YAML or JSON file ("-" for stdin) into v.
+func (a *app) readBody(path string, v interface{}) error {
+	data, err := a.readFile(path)
+	if err != nil {
+		return err
+	}
+	jsonBody, err := transport.YAMLToJSON(data)
+	if err != nil {
+		return fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	if err := json.Unmarshal(jsonBody, v); err != nil {
+		return fmt.Errorf("error decoding %s: %w", path, err)
+	}
+	return nil
+}
+
+func (a *app) readFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, usagef("missing -f FILE")
+	}
+	if path == "-" {
+		return io.ReadAll(a.stdin)
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+const defaultSince = time.Hour
+
+// timeRangeFlags registers --since, --start and --end.
+func timeRangeFlags(fs *flag.FlagSet) {
+	fs.Duration("since", defaultSince, "look back this far from now, ignored with --start")
+	fs.String("start", "", "start time, RFC 3339")
+	fs.String("end", "", "end time, RFC 3339 (default now)")
+}
+
+// timeRange resolves the time range flags.
+func timeRange(fs *flag.FlagSet) (start, end strfmt.DateTime, err error) {
+	endTime := time.Now().UTC()
+	if value := fs.Lookup("end").Value.String(); value != "" {
+		if endTime, err = time.Parse(time.RFC3339, value); err != nil {
+			return start, end, usagef("invalid --end: %v", err)
+		}
+	}
+
+	since := fs.Lookup("since").Value.(flag.Getter).Get().(time.Duration)
+	startTime := endTime.Add(-since)
+	if value := fs.Lookup("start").Value.String(); value != "" {
+		if startTime, err = time.Parse(time.RFC3339, value); err != nil {
+			return start, end, usagef("invalid --start: %v", err)
+		}
+	}
+
+	if !startTime.Before(endTime) {
+		return start, end, usagef("start time %s is not before end time %s", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
+	}
+	return strfmt.DateTime(startTime), strfmt.DateTime(endTime), nil
+}
+
+func searchFlags(fs *flag.FlagSet) {
+	fs.String("query", "", "search query")
+	timeRangeFlags(fs)
+}
+
+func init() {
+	resources["logs"] = &resource{
+		help: "Search logs",
+		commands: map[string]*command{
+			"search": {
+				usage: "--query QUERY [--since DURATION | --start TIME --end TIME]",
+				help:  "Search logs",
+				flags: searchFlags,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					start, end, err := timeRange(fs)
+					if err != nil {
+						return err
+					}
+					result, err := a.client.Logs.Search(ctx, &models.LogsSearchRequest{
+						Start: &start,
+						End:   &end,
+						Query: fs.Lookup("query").Value.String(),
+					})
+					if err != nil {
+						return err
+					}
+					return a.print(result, nil)
+				},
+			},
+		},
+	}
+
+	resources["traces"] = &resource{
+		help: "Search traces",
+		commands: map[string]*command{
+			"search": {
+				usage: "--query QUERY [--since DURATION | --start TIME --end TIME]",
+				help:  "Search traces",
+				flags: searchFlags,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					start, end, err := timeRange(fs)
+					if err != nil {
+						return err
+					}
+					result, err := a.client.Traces.Search(ctx, &models.TracesSearchRequest{
+						Start: &start,
+						End:   &end,
+						Query: fs.Lookup("query").Value.String(),
+					})
+					if err != nil {
+						return err
+					}
+					return a.print(result, nil)
+				},
+			},
+		},
+	}
+
+	resources["events"] = &resource{
+		help: "Search events",
+		commands: map[string]*command{
+			"search": {
+				usage: "--query QUERY [--since DURATION | --start TIME --end TIME]",
+				help:  "Search events",
+				flags: searchFlags,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					start, end, err := timeRange(fs)
+					if err != nil {
+						return err
+					}
+					result, err := a.client.Events.Search(ctx, &models.EventsSearchRequest{
+						Start: &start,
+						End:   &end,
+						Query: fs.Lookup("query").Value.String(),
+					})
+					if err != nil {
+						return err
+					}
+					return a.print(result, nil)
+				},
+			},
+		},
+	}
+
+	resources["metrics"] = &resource{
+		help: "Query metrics",
+		commands: map[string]*command{
+			"query": {
+				usage: "--promql QUERY [--type instant|range] [--step STEP] [--since DURATION | --start TIME --end TIME]",
+				help:  "Run a PromQL query",
+				flags: func(fs *flag.FlagSet) {
+					fs.String("promql", "", "PromQL query")
+					fs.String("type", models.QueryRequestQueryTypeInstant, "query type: instant or range")
+					fs.String("step", "", "resolution of range queries, e.g. 30s")
+					timeRangeFlags(fs)
+				},
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					promql := fs.Lookup("promql").Value.String()
+					if promql == "" {
+						return usagef("missing --promql")
+					}
+					queryType := fs.Lookup("type").Value.String()
+					if queryType != models.QueryRequestQueryTypeInstant && queryType != models.QueryRequestQueryTypeRange {
+						return usagef("invalid --type %q: expected instant or range", queryType)
+					}
+					start, end, err := timeRange(fs)
+					if err != nil {
+						return err
+					}
+
+					result, err := a.client.Metrics.Query(ctx, &models.QueryRequest{
+						Start:     start,
+						End:       end,
+						Promql:    promql,
+						QueryType: queryType,
+						Step:      fs.Lookup("step").Value.String(),
+					})
+					if err != nil {
+						return err
+					}
+					return a.print(result, func() *table { return metricsTable(result) })
+				},
+			},
+		},
+	}
+
+	resources["k8s"] = &resource{
+		help: "List Kubernetes workloads and clusters",
+		commands: map[string]*command{
+			"workloads": {
+				usage: "[--limit N] [--skip N] [--sort-by FIELD] [--order asc|desc]",
+				help:  "List workloads",
+				flags: func(fs *flag.FlagSet) {
+					fs.Uint("limit", 100, "maximum number of workloads")
+					fs.Uint("skip", 0, "number of workloads to skip")
+					fs.String("sort-by", "", "sort field, e.g. rps")
+					fs.String("order", "", "sort order: asc or desc")
+				},
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					resp, err := a.client.K8s.Workloads(ctx, &models.WorkloadsListRequest{
+						Conditions: []*models.Condition{},
+						Sources:    []*models.Condition{},
+						Limit:      uint32(fs.Lookup("limit").Value.(flag.Getter).Get().(uint)),
+						Skip:       uint32(fs.Lookup("skip").Value.(flag.Getter).Get().(uint)),
+						SortBy:     fs.Lookup("sort-by").Value.String(),
+						Order:      fs.Lookup("order").Value.String(),
+					})
+					if err != nil {
+						return err
+					}
+					return a.print(resp, func() *table {
+						t := &table{headers: []string{"CLUSTER", "NAMESPACE", "WORKLOAD", "KIND", "PODS", "READY", "CPU", "MEMORY", "RPS", "ERROR RATE"}}
+						for _, w := range resp.Workloads {
+							t.add(w.Cluster, w.Namespace, w.Workload, w.Kind, w.PodsCount, w.Ready, w.CPUUsage, w.MemoryUsage, w.RPS, w.ErrorRate)
+						}
+						return t
+					})
+				},
+			},
+			"clusters": {
+				help: "List clusters",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					resp, err := a.client.K8s.Clusters(ctx, &models.ClustersListRequest{Sources: []*models.Condition{}})
+					if err != nil {
+						return err
+					}
+					return a.print(resp, func() *table {
+						t := &table{headers: []string{"NAME", "ENV", "PROVIDER", "VERSION", "NODES", "CPU USAGE %", "MEMORY USAGE %", "ISSUES"}}
+						for _, c := range resp.Clusters {
+							t.add(c.Name, c.Env, c.CloudProvider, c.KubernetesVersion, c.NodesCount,
+								c.CPUUsageAllocatablePercent, c.MemoryUsageAllocatablePercent, c.IssueCount)
+						}
+						return t
+					})
+				},
+			},
+		},
+	}
+}
+
+// metricsTable renders a Prometheus query response with one row per sample.
+func metricsTable(result interface{}) *table {
+	response, _ := result.(map[string]interface{})
+	data, _ := response["data"].(map[string]interface{})
+	series, ok := data["result"].([]interface{})
+	if !ok {
+		return genericTable(result)
+	}
+
+	t := &table{headers: []string{"METRIC", "TIMESTAMP", "VALUE"}}
+	for _, item := range series {
+		s, _ := item.(map[string]interface{})
+		labels := formatLabels(s["metric"])
+
+		samples, _ := s["values"].([]interface{})
+		if value, ok := s["value"]; ok {
+			samples = append(samples, value)
+		}
+		for _, sample := range samples {
+			pair, _ := sample.([]interface{})
+			if len(pair) != 2 {
+				continue
+			}
+			timestamp := pair[0]
+			if seconds, ok := pair[0].(float64); ok {
+				timestamp = time.Unix(0, int64(seconds*float64(time.Second))).UTC().Format(time.RFC3339)
+			}
+			t.add(labels, timestamp, pair[1])
+		}
+	}
+	return t
+}
+
+// formatLabels renders a metric's labels as name{key="value",...}.
+func formatLabels(metric interface{}) string {
+	labels, _ := metric.(map[string]interface{})
+	name, _ := labels["__name__"].(string)
+
+	var pairs string
+	for _, key := range sortedKeys(labels) {
+		if key == "__name__" {
+			continue
+		}
+		if pairs != "" {
+			pairs += ","
+		}
+		pairs += fmt.Sprintf("%s=%q", key, fmt.Sprint(labels[key]))
+	}
+	if pairs == "" && name != "" {
+		return name
+	}
+	return name + "{" + pairs + "}"
+}
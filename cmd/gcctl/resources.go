@@ -0,0 +1,581 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/groundcover"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// fileFlag registers the -f flag of commands that read a resource definition.
+func fileFlag(fs *flag.FlagSet) {
+	fs.String("f", "", "YAML or JSON definition file, - for stdin")
+}
+
+func fileArg(fs *flag.FlagSet) string {
+	return fs.Lookup("f").Value.String()
+}
+
+func boolArg(fs *flag.FlagSet, name string) bool {
+	return fs.Lookup(name).Value.String() == "true"
+}
+
+func init() {
+	resources["monitors"] = &resource{
+		help: "Manage monitors",
+		commands: map[string]*command{
+			"list": {
+				help: "List monitors",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					items, err := a.client.Monitors.List(ctx)
+					if err != nil {
+						return err
+					}
+					return a.print(items, func() *table {
+						t := &table{headers: []string{"ID", "TITLE", "TYPE"}}
+						for _, item := range items {
+							t.add(item.UUID, item.Title, item.Type)
+						}
+						return t
+					})
+				},
+			},
+			"get": {
+				usage: "<id>",
+				help:  "Show a monitor",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					args, err := requireArgs(fs, "<id>")
+					if err != nil {
+						return err
+					}
+					monitor, err := a.client.Monitors.Get(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return a.print(monitor, func() *table {
+						t := &table{headers: []string{"TITLE", "SEVERITY", "CATEGORY", "TEAM", "PAUSED"}}
+						t.add(monitor.Title, monitor.Severity, monitor.Category, monitor.Team, monitor.IsPaused)
+						return t
+					})
+				},
+			},
+			"create": {
+				usage: "-f FILE",
+				help:  "Create a monitor",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					monitor := &models.CreateMonitorRequest{}
+					if err := a.readBody(fileArg(fs), monitor); err != nil {
+						return err
+					}
+					id, err := a.client.Monitors.Create(ctx, monitor)
+					if err != nil {
+						return err
+					}
+					return a.printMessage("monitor %s created", id)
+				},
+			},
+			"update": {
+				usage: "<id> -f FILE",
+				help:  "Replace a monitor",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					args, err := requireArgs(fs, "<id>")
+					if err != nil {
+						return err
+					}
+					monitor := &models.UpdateMonitorRequest{}
+					if err := a.readBody(fileArg(fs), monitor); err != nil {
+						return err
+					}
+					if err := a.client.Monitors.Update(ctx, args[0], monitor); err != nil {
+						return err
+					}
+					return a.printMessage("monitor %s updated", args[0])
+				},
+			},
+			"delete": deleteCommand("monitor", func(ctx context.Context, a *app, id string) error {
+				return a.client.Monitors.Delete(ctx, id)
+			}),
+		},
+	}
+
+	resources["silences"] = &resource{
+		help: "Manage alert silences",
+		commands: map[string]*command{
+			"list": {
+				usage: "[--active]",
+				help:  "List silences",
+				flags: func(fs *flag.FlagSet) {
+					fs.Bool("active", false, "only active silences")
+				},
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					silences, err := a.client.Silences.List(ctx, groundcover.SilenceListOptions{ActiveOnly: boolArg(fs, "active")})
+					if err != nil {
+						return err
+					}
+					return a.print(silences, func() *table { return silencesTable(silences...) })
+				},
+			},
+			"get": {
+				usage: "<id>",
+				help:  "Show a silence",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					args, err := requireArgs(fs, "<id>")
+					if err != nil {
+						return err
+					}
+					silence, err := a.client.Silences.Get(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return a.print(silence, func() *table { return silencesTable(silence) })
+				},
+			},
+			"create": {
+				usage: "-f FILE",
+				help:  "Create a silence",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					spec := &models.CreateSilenceRequest{}
+					if err := a.readBody(fileArg(fs), spec); err != nil {
+						return err
+					}
+					silence, err := a.client.Silences.Create(ctx, spec)
+					if err != nil {
+						return err
+					}
+					return a.print(silence, func() *table { return silencesTable(silence) })
+				},
+			},
+			"delete": deleteCommand("silence", func(ctx context.Context, a *app, id string) error {
+				return a.client.Silences.Delete(ctx, id)
+			}),
+		},
+	}
+
+	resources["policies"] = &resource{
+		help: "Manage RBAC policies",
+		commands: map[string]*command{
+			"list": {
+				help: "List policies",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					policies, err := a.client.Policies.List(ctx)
+					if err != nil {
+						return err
+					}
+					return a.print(policies, func() *table {
+						t := &table{headers: []string{"ID", "NAME", "DESCRIPTION", "REVISION", "ENTITIES"}}
+						for _, policy := range policies {
+							t.add(policy.UUID, policy.Name, policy.Description, policy.RevisionNumber, policy.EntityCount)
+						}
+						return t
+					})
+				},
+			},
+			"get": {
+				usage: "<id>",
+				help:  "Show a policy",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					args, err := requireArgs(fs, "<id>")
+					if err != nil {
+						return err
+					}
+					policy, err := a.client.Policies.Get(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return a.print(policy, func() *table { return policiesTable(policy) })
+				},
+			},
+			"create": {
+				usage: "-f FILE",
+				help:  "Create a policy",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					spec := &models.CreatePolicyRequest{}
+					if err := a.readBody(fileArg(fs), spec); err != nil {
+						return err
+					}
+					policy, err := a.client.Policies.Create(ctx, spec)
+					if err != nil {
+						return err
+					}
+					return a.print(policy, func() *table { return policiesTable(policy) })
+				},
+			},
+			"update": {
+				usage: "<id> -f FILE",
+				help:  "Update a policy",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					args, err := requireArgs(fs, "<id>")
+					if err != nil {
+						return err
+					}
+					spec := &models.UpdatePolicyRequest{}
+					if err := a.readBody(fileArg(fs), spec); err != nil {
+						return err
+					}
+					policy, err := a.client.Policies.Update(ctx, args[0], spec)
+					if err != nil {
+						return err
+					}
+					return a.print(policy, func() *table { return policiesTable(policy) })
+				},
+			},
+			"apply": {
+				usage: "-f FILE",
+				help:  "Apply policies to service accounts",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					spec := &models.ApplyPolicyRequest{}
+					if err := a.readBody(fileArg(fs), spec); err != nil {
+						return err
+					}
+					if err := a.client.Policies.Apply(ctx, spec); err != nil {
+						return err
+					}
+					return a.printMessage("policies applied")
+				},
+			},
+			"delete": deleteCommand("policy", func(ctx context.Context, a *app, id string) error {
+				return a.client.Policies.Delete(ctx, id)
+			}),
+		},
+	}
+
+	resources["service-accounts"] = &resource{
+		help: "Manage service accounts",
+		commands: map[string]*command{
+			"list": {
+				help: "List service accounts",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					accounts, err := a.client.ServiceAccounts.List(ctx)
+					if err != nil {
+						return err
+					}
+					return a.print(accounts, func() *table { return serviceAccountsTable(accounts...) })
+				},
+			},
+			"get": {
+				usage: "<id>",
+				help:  "Show a service account",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					args, err := requireArgs(fs, "<id>")
+					if err != nil {
+						return err
+					}
+					account, err := a.client.ServiceAccounts.Get(ctx, args[0])
+					if err != nil {
+						return err
+					}
+					return a.print(account, func() *table { return serviceAccountsTable(account) })
+				},
+			},
+			"create": {
+				usage: "-f FILE",
+				help:  "Create a service account",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					spec := &models.CreateServiceAccountRequest{}
+					if err := a.readBody(fileArg(fs), spec); err != nil {
+						return err
+					}
+					created, err := a.client.ServiceAccounts.Create(ctx, spec)
+					if err != nil {
+						return err
+					}
+					return a.print(created, func() *table {
+						t := &table{headers: []string{"ID"}}
+						t.add(created.ServiceAccountID)
+						return t
+					})
+				},
+			},
+			"update": {
+				usage: "-f FILE",
+				help:  "Update the service account identified by serviceAccountId",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					spec := &models.UpdateServiceAccountRequest{}
+					if err := a.readBody(fileArg(fs), spec); err != nil {
+						return err
+					}
+					updated, err := a.client.ServiceAccounts.Update(ctx, spec)
+					if err != nil {
+						return err
+					}
+					return a.printMessage("service account %s updated", updated.ServiceAccountID)
+				},
+			},
+			"delete": deleteCommand("service account", func(ctx context.Context, a *app, id string) error {
+				return a.client.ServiceAccounts.Delete(ctx, id)
+			}),
+		},
+	}
+
+	resources["api-keys"] = &resource{
+		help: "Manage service account API keys",
+		commands: map[string]*command{
+			"list": {
+				usage: "[--expired] [--revoked]",
+				help:  "List API keys",
+				flags: func(fs *flag.FlagSet) {
+					fs.Bool("expired", false, "include expired keys")
+					fs.Bool("revoked", false, "include revoked keys")
+				},
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					keys, err := a.client.APIKeys.List(ctx, groundcover.APIKeyListOptions{
+						WithExpired: boolArg(fs, "expired"),
+						WithRevoked: boolArg(fs, "revoked"),
+					})
+					if err != nil {
+						return err
+					}
+					return a.print(keys, func() *table {
+						t := &table{headers: []string{"ID", "NAME", "SERVICE ACCOUNT", "CREATED", "LAST ACTIVE"}}
+						for _, key := range keys {
+							t.add(key.ID, key.Name, key.ServiceAccountName, key.CreationDate, key.LastActive)
+						}
+						return t
+					})
+				},
+			},
+			"create": {
+				usage: "-f FILE",
+				help:  "Create an API key; the key is only shown once",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					spec := &models.CreateAPIKeyRequest{}
+					if err := a.readBody(fileArg(fs), spec); err != nil {
+						return err
+					}
+					created, err := a.client.APIKeys.Create(ctx, spec)
+					if err != nil {
+						return err
+					}
+					return a.print(created, func() *table {
+						t := &table{headers: []string{"ID", "API KEY"}}
+						t.add(created.ID, created.APIKey)
+						return t
+					})
+				},
+			},
+			"revoke": {
+				usage: "<id>",
+				help:  "Revoke an API key",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					args, err := requireArgs(fs, "<id>")
+					if err != nil {
+						return err
+					}
+					if err := a.client.APIKeys.Revoke(ctx, args[0]); err != nil {
+						return err
+					}
+					return a.printMessage("API key %s revoked", args[0])
+				},
+			},
+		},
+	}
+
+	resources["ingestion-keys"] = &resource{
+		help: "Manage ingestion keys",
+		commands: map[string]*command{
+			"list": {
+				usage: "[--name NAME] [--type TYPE]",
+				help:  "List ingestion keys",
+				flags: func(fs *flag.FlagSet) {
+					fs.String("name", "", "filter by name")
+					fs.String("type", "", "filter by type")
+				},
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					keys, err := a.client.IngestionKeys.List(ctx, &models.ListIngestionKeysRequest{
+						Name: fs.Lookup("name").Value.String(),
+						Type: fs.Lookup("type").Value.String(),
+					})
+					if err != nil {
+						return err
+					}
+					return a.print(keys, func() *table { return ingestionKeysTable(keys...) })
+				},
+			},
+			"create": {
+				usage: "-f FILE",
+				help:  "Create an ingestion key",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					spec := &models.CreateIngestionKeyRequest{}
+					if err := a.readBody(fileArg(fs), spec); err != nil {
+						return err
+					}
+					key, err := a.client.IngestionKeys.Create(ctx, spec)
+					if err != nil {
+						return err
+					}
+					return a.print(key, func() *table { return ingestionKeysTable(key) })
+				},
+			},
+			"delete": deleteCommand("ingestion key", func(ctx context.Context, a *app, name string) error {
+				return a.client.IngestionKeys.Delete(ctx, name)
+			}),
+		},
+	}
+
+	resources["workflows"] = &resource{
+		help: "Manage workflows",
+		commands: map[string]*command{
+			"list": {
+				help: "List workflows",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					workflows, err := a.client.Workflows.List(ctx)
+					if err != nil {
+						return err
+					}
+					return a.print(workflows, func() *table {
+						t := &table{headers: []string{"ID", "NAME", "REVISION", "LAST STATUS", "LAST EXECUTION"}}
+						for _, workflow := range workflows {
+							t.add(workflow.ID, workflow.Name, workflow.Revision, workflow.LastExecutionStatus, workflow.LastExecutionTime)
+						}
+						return t
+					})
+				},
+			},
+			"create": {
+				usage: "-f FILE",
+				help:  "Create a workflow from its YAML definition",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					definition, err := a.readFile(fileArg(fs))
+					if err != nil {
+						return err
+					}
+					created, err := a.client.Workflows.Create(ctx, string(definition))
+					if err != nil {
+						return err
+					}
+					return a.print(created, func() *table {
+						t := &table{headers: []string{"ID", "REVISION", "STATUS"}}
+						t.add(created.WorkflowID, created.Revision, created.Status)
+						return t
+					})
+				},
+			},
+			"delete": deleteCommand("workflow", func(ctx context.Context, a *app, id string) error {
+				return a.client.Workflows.Delete(ctx, id)
+			}),
+		},
+	}
+
+	resources["logs-pipeline"] = &resource{
+		help: "Manage the logs pipeline configuration",
+		commands: map[string]*command{
+			"get": {
+				help: "Show the configuration",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					config, err := a.client.LogsPipeline.Get(ctx)
+					if err != nil {
+						return err
+					}
+					if config == nil {
+						return a.printMessage("no logs pipeline configuration")
+					}
+					if a.output == outputTable {
+						_, err := a.stdout.Write([]byte(strings.TrimRight(config.Value, "\n") + "\n"))
+						return err
+					}
+					return a.print(config, nil)
+				},
+			},
+			"set": {
+				usage: "-f FILE",
+				help:  "Create or replace the configuration",
+				flags: fileFlag,
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					value, err := a.readFile(fileArg(fs))
+					if err != nil {
+						return err
+					}
+					existing, err := a.client.LogsPipeline.Get(ctx)
+					if err != nil {
+						return err
+					}
+					if existing == nil {
+						_, err = a.client.LogsPipeline.Create(ctx, string(value))
+					} else {
+						_, err = a.client.LogsPipeline.Update(ctx, string(value))
+					}
+					if err != nil {
+						return err
+					}
+					return a.printMessage("logs pipeline configuration updated")
+				},
+			},
+			"delete": {
+				help: "Delete the configuration",
+				run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+					if err := a.client.LogsPipeline.Delete(ctx); err != nil {
+						return err
+					}
+					return a.printMessage("logs pipeline configuration deleted")
+				},
+			},
+		},
+	}
+}
+
+// deleteCommand builds the "delete <id>" command of a resource.
+func deleteCommand(kind string, remove func(ctx context.Context, a *app, id string) error) *command {
+	return &command{
+		usage: "<id>",
+		help:  "Delete a " + kind,
+		run: func(ctx context.Context, a *app, fs *flag.FlagSet) error {
+			args, err := requireArgs(fs, "<id>")
+			if err != nil {
+				return err
+			}
+			if err := remove(ctx, a, args[0]); err != nil {
+				return err
+			}
+			return a.printMessage("%s %s deleted", kind, args[0])
+		},
+	}
+}
+
+func silencesTable(silences ...*models.Silence) *table {
+	t := &table{headers: []string{"ID", "ACTIVE", "STARTS", "ENDS", "COMMENT"}}
+	for _, silence := range silences {
+		t.add(silence.UUID, silence.Active, silence.StartsAt, silence.EndsAt, silence.Comment)
+	}
+	return t
+}
+
+func policiesTable(policies ...*models.Policy) *table {
+	t := &table{headers: []string{"ID", "NAME", "DESCRIPTION", "REVISION"}}
+	for _, policy := range policies {
+		t.add(policy.UUID, policy.Name, policy.Description, policy.RevisionNumber)
+	}
+	return t
+}
+
+func serviceAccountsTable(accounts ...*models.ServiceAccountsWithPolicy) *table {
+	t := &table{headers: []string{"ID", "NAME", "EMAIL", "POLICIES"}}
+	for _, account := range accounts {
+		var names []string
+		for _, policy := range account.Policies {
+			names = append(names, policy.Name)
+		}
+		t.add(account.ServiceAccountID, account.Name, account.Email, strings.Join(names, ","))
+	}
+	return t
+}
+
+func ingestionKeysTable(keys ...*models.IngestionKeyResult) *table {
+	t := &table{headers: []string{"ID", "NAME", "TYPE", "REMOTE CONFIG", "TAGS", "CREATED"}}
+	for _, key := range keys {
+		t.add(key.ID, key.Name, key.Type, key.RemoteConfig, strings.Join(key.Tags, ","), key.CreationDate)
+	}
+	return t
+}
@@ -0,0 +1,357 @@
+// Command fakegen generates the pkg/fakes test doubles from the ClientService
+// interfaces of the generated API clients in pkg/client.
+//
+//	go run ./internal/fakegen -client pkg/client -out pkg/fakes
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const clientImportPath = "github.com/groundcover-com/groundcover-sdk-go/pkg/client"
+
+// service is a ClientService of one client package.
+type service struct {
+	Field      string // GroundcoverAPI field, e.g. "LogsPipeline"
+	Package    string // client package, e.g. "logs_pipeline"
+	ClientType string // unexported implementation, e.g. "logsPipelineClient"
+	Methods    []method
+}
+
+// method is one operation of a ClientService.
+type method struct {
+	Name       string
+	ParamsType string   // e.g. "*monitors.GetMonitorParams"
+	Results    []result // non-error results
+	HTTPMethod string
+	Path       string
+}
+
+type result struct {
+	Field string // field of the multi-result struct, e.g. "NoContent"
+	Type  string // e.g. "*logs_pipeline.GetConfigNoContent"
+}
+
+// ResultType is the R of the fake's Method[P, R].
+func (m method) ResultType(s service) string {
+	if len(m.Results) == 1 {
+		return m.Results[0].Type
+	}
+	return s.Field + m.Name + "Result"
+}
+
+// ReturnTypes is the result list of the ClientService method.
+func (m method) ReturnTypes() string {
+	types := make([]string, 0, len(m.Results)+1)
+	for _, r := range m.Results {
+		types = append(types, r.Type)
+	}
+	return strings.Join(append(types, "error"), ", ")
+}
+
+func main() {
+	clientDir := flag.String("client", "pkg/client", "directory of the generated client")
+	outDir := flag.String("out", "pkg/fakes", "output directory")
+	flag.Parse()
+
+	services, err := parseServices(*clientDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, s := range services {
+		if err := render(filepath.Join(*outDir, s.Package+".go"), serviceTemplate, s); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := render(filepath.Join(*outDir, "api.go"), apiTemplate, services); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseServices reads the services of the GroundcoverAPI struct and their ClientService interfaces.
+func parseServices(clientDir string) ([]service, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filepath.Join(clientDir, "groundcover_api_client.go"), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	apiStruct := findStruct(file, "GroundcoverAPI")
+	if apiStruct == nil {
+		return nil, fmt.Errorf("GroundcoverAPI struct not found in %s", clientDir)
+	}
+
+	var services []service
+	for _, field := range apiStruct.Fields.List {
+		selector, ok := field.Type.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != "ClientService" {
+			continue
+		}
+
+		pkg := selector.X.(*ast.Ident).Name
+		s := service{
+			Field:      field.Names[0].Name,
+			Package:    pkg,
+			ClientType: lowerFirst(field.Names[0].Name) + "Client",
+		}
+		if s.Methods, err = parseMethods(fset, filepath.Join(clientDir, pkg), pkg); err != nil {
+			return nil, err
+		}
+		services = append(services, s)
+	}
+
+	sort.Slice(services, func(i, j int) bool { return services[i].Field < services[j].Field })
+	return services, nil
+}
+
+func parseMethods(fset *token.FileSet, dir, pkg string) ([]method, error) {
+	file, err := parser.ParseFile(fset, filepath.Join(dir, pkg+"_client.go"), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var iface *ast.InterfaceType
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == "ClientService" {
+				iface, _ = ts.Type.(*ast.InterfaceType)
+			}
+		}
+	}
+	if iface == nil {
+		return nil, fmt.Errorf("ClientService not found in %s", dir)
+	}
+
+	routes := parseRoutes(file)
+
+	var methods []method
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || field.Names[0].Name == "SetTransport" {
+			continue
+		}
+
+		name := field.Names[0].Name
+		paramsType, err := qualify(fn.Params.List[0].Type, pkg)
+		if err != nil {
+			return nil, fmt.Errorf("%s.ClientService.%s: %w", pkg, name, err)
+		}
+		m := method{
+			Name:       name,
+			ParamsType: paramsType,
+			HTTPMethod: routes[name][0],
+			Path:       routes[name][1],
+		}
+		for _, res := range fn.Results.List {
+			typ, err := qualify(res.Type, pkg)
+			if err != nil {
+				return nil, fmt.Errorf("%s.ClientService.%s: %w", pkg, name, err)
+			}
+			if typ == "error" {
+				continue
+			}
+			m.Results = append(m.Results, result{
+				Field: strings.TrimPrefix(strings.TrimPrefix(typ, "*"+pkg+"."), name),
+				Type:  typ,
+			})
+		}
+		methods = append(methods, m)
+	}
+	return methods, nil
+}
+
+// parseRoutes returns the HTTP method and path pattern of each Client method.
+func parseRoutes(file *ast.File) map[string][2]string {
+	routes := make(map[string][2]string)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil || fn.Body == nil {
+			continue
+		}
+
+		var route [2]string
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			kv, ok := n.(*ast.KeyValueExpr)
+			if !ok {
+				return true
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			lit, isLit := kv.Value.(*ast.BasicLit)
+			if !ok || !isLit {
+				return true
+			}
+			value, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return true
+			}
+			switch key.Name {
+			case "Method":
+				route[0] = value
+			case "PathPattern":
+				route[1] = value
+			}
+			return true
+		})
+		routes[fn.Name.Name] = route
+	}
+	return routes
+}
+
+func findStruct(file *ast.File, name string) *ast.StructType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == name {
+				st, _ := ts.Type.(*ast.StructType)
+				return st
+			}
+		}
+	}
+	return nil
+}
+
+// qualify renders a type expression of package pkg as seen from another package.
+func qualify(expr ast.Expr, pkg string) (string, error) {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		typ, err := qualify(t.X, pkg)
+		return "*" + typ, err
+	case *ast.Ident:
+		if t.Name == "error" || !unicode.IsUpper(rune(t.Name[0])) {
+			return t.Name, nil
+		}
+		return pkg + "." + t.Name, nil
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			return x.Name + "." + t.Sel.Name, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported type %T", expr)
+}
+
+func lowerFirst(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func render(path string, tmpl *template.Template, data interface{}) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("error formatting %s: %w\n%s", path, err, buf.Bytes())
+	}
+	return os.WriteFile(path, source, 0o644)
+}
+
+var funcs = template.FuncMap{"clientImport": func(pkg string) string { return clientImportPath + "/" + pkg }}
+
+var serviceTemplate = template.Must(template.New("service").Funcs(funcs).Parse(`// Code generated by fakegen. DO NOT EDIT.
+
+package fakes
+
+import (
+	"github.com/go-openapi/runtime"
+
+	"{{ clientImport .Package }}"
+)
+
+// {{ .Field }} is a fake {{ .Package }}.ClientService.
+type {{ .Field }} struct {
+{{- range .Methods }}
+	{{ .Name }} *Method[{{ .ParamsType }}, {{ .ResultType $ }}]
+{{- end }}
+}
+
+// New{{ .Field }} returns a {{ .Field }} fake with no programmed responses.
+func New{{ .Field }}() *{{ .Field }} {
+	return &{{ .Field }}{
+{{- range .Methods }}
+		{{ .Name }}: newMethod[{{ .ParamsType }}, {{ .ResultType $ }}]("{{ $.Package }}.{{ .Name }}", "{{ .HTTPMethod }}", "{{ .Path }}"),
+{{- end }}
+	}
+}
+
+// Client returns the {{ .Package }}.ClientService backed by f.
+func (f *{{ .Field }}) Client() {{ .Package }}.ClientService {
+	return &{{ .ClientType }}{fake: f}
+}
+{{ range .Methods }}{{ if gt (len .Results) 1 }}
+// {{ .ResultType $ }} holds the responses of {{ $.Package }}.{{ .Name }}.
+type {{ .ResultType $ }} struct {
+{{- range .Results }}
+	{{ .Field }} {{ .Type }}
+{{- end }}
+}
+{{ end }}{{ end }}
+type {{ .ClientType }} struct {
+	fake *{{ .Field }}
+}
+{{ range .Methods }}
+func (c *{{ $.ClientType }}) {{ .Name }}(params {{ .ParamsType }}, authInfo runtime.ClientAuthInfoWriter, opts ...{{ $.Package }}.ClientOption) ({{ .ReturnTypes }}) {
+{{- if gt (len .Results) 1 }}
+	result, err := c.fake.{{ .Name }}.call(params)
+	return {{ range .Results }}result.{{ .Field }}, {{ end }}err
+{{- else }}
+	return c.fake.{{ .Name }}.call(params)
+{{- end }}
+}
+{{ end }}
+func (c *{{ .ClientType }}) SetTransport(transport runtime.ClientTransport) {}
+`))
+
+var apiTemplate = template.Must(template.New("api").Funcs(funcs).Parse(`// Code generated by fakegen. DO NOT EDIT.
+
+package fakes
+
+import (
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client"
+)
+
+// Fakes holds the service fakes behind a fake client.GroundcoverAPI.
+type Fakes struct {
+{{- range . }}
+	{{ .Field }} *{{ .Field }}
+{{- end }}
+}
+
+// NewFakeGroundcoverAPI returns a client.GroundcoverAPI whose services are
+// all fakes, together with the fakes to program and inspect them.
+func NewFakeGroundcoverAPI() (*client.GroundcoverAPI, *Fakes) {
+	f := &Fakes{
+{{- range . }}
+		{{ .Field }}: New{{ .Field }}(),
+{{- end }}
+	}
+
+	return &client.GroundcoverAPI{
+{{- range . }}
+		{{ .Field }}: f.{{ .Field }}.Client(),
+{{- end }}
+	}, f
+}
+`))
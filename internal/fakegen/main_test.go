@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFakesUpToDate fails when pkg/client changed without regenerating pkg/fakes.
+func TestFakesUpToDate(t *testing.T) {
+	services, err := parseServices("../../pkg/client")
+	if err != nil {
+		t.Fatalf("parseServices failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	files := []string{"api.go"}
+	for _, s := range services {
+		if err := render(filepath.Join(dir, s.Package+".go"), serviceTemplate, s); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, s.Package+".go")
+	}
+	if err := render(filepath.Join(dir, "api.go"), apiTemplate, services); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range files {
+		want, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join("../../pkg/fakes", name))
+		if err != nil {
+			t.Fatalf("%s: %v (run go generate ./pkg/fakes)", name, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("pkg/fakes/%s is out of date, run go generate ./pkg/fakes", name)
+		}
+	}
+}
+
+func TestUnsupportedType(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "groundcover_api_client.go"), []byte(`package client
+
+type GroundcoverAPI struct {
+	Monitors monitors.ClientService
+}
+`), 0o644)
+	os.Mkdir(filepath.Join(dir, "monitors"), 0o755)
+	os.WriteFile(filepath.Join(dir, "monitors", "monitors_client.go"), []byte(`package monitors
+
+type ClientService interface {
+	ListMonitors(params map[string]string) (*ListMonitorsOK, error)
+}
+`), 0o644)
+
+	_, err := parseServices(dir)
+	if err == nil || err.Error() != "monitors.ClientService.ListMonitors: unsupported type *ast.MapType" {
+		t.Errorf("expected an unsupported type error, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+// Package logrecord reads common fields of log search records, which the
+// API returns as untyped JSON objects.
+package logrecord
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+)
+
+// TimestampFields are the fields searched, in order, for the record time.
+var TimestampFields = []string{"timestamp", "time", "@timestamp"}
+
+// MessageFields are the fields searched, in order, for the log line.
+var MessageFields = []string{"content", "message", "msg", "body", "log"}
+
+// Timestamp returns the time in the first of fields holding a timestamp.
+func Timestamp(record map[string]interface{}, fields []string) (time.Time, bool) {
+	for _, field := range fields {
+		if timestamp, ok := ParseTimestamp(record[field]); ok {
+			return timestamp, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Message returns the first of fields holding a string.
+func Message(record map[string]interface{}, fields []string) (string, bool) {
+	for _, field := range fields {
+		if message, ok := record[field].(string); ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// ParseTimestamp accepts RFC 3339 strings and Unix times in seconds,
+// milliseconds, microseconds or nanoseconds, told apart by magnitude.
+func ParseTimestamp(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t.UTC(), true
+		}
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return unixTime(n), true
+		}
+	case float64:
+		return unixTime(v), true
+	case json.Number:
+		if n, err := v.Float64(); err == nil {
+			return unixTime(n), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func unixTime(n float64) time.Time {
+	switch abs := math.Abs(n); {
+	case abs >= 1e17:
+		return time.Unix(0, int64(n)).UTC()
+	case abs >= 1e14:
+		return time.UnixMicro(int64(n)).UTC()
+	case abs >= 1e11:
+		return time.UnixMilli(int64(n)).UTC()
+	default:
+		sec, frac := math.Modf(n)
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+	}
+}
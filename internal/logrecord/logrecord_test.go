@@ -0,0 +1,50 @@
+package logrecord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2024, 1, 1, 0, 0, 1, 500000000, time.UTC)
+	tests := []interface{}{
+		"2024-01-01T00:00:01.5Z",
+		float64(want.UnixNano()) / 1e9,
+		float64(want.UnixMilli()),
+		float64(want.UnixMicro()),
+		float64(want.UnixNano()),
+		"1704067201500",
+	}
+	for _, value := range tests {
+		got, ok := ParseTimestamp(value)
+		if !ok {
+			t.Errorf("ParseTimestamp(%v) failed", value)
+			continue
+		}
+		if got.Sub(want).Abs() > time.Microsecond {
+			t.Errorf("ParseTimestamp(%v) = %v, want %v", value, got, want)
+		}
+	}
+
+	if _, ok := ParseTimestamp(true); ok {
+		t.Error("Expected bool timestamp to be rejected")
+	}
+}
+
+func TestTimestampAndMessage(t *testing.T) {
+	record := map[string]interface{}{
+		"time":    "2024-01-01T00:00:00Z",
+		"message": "started",
+		"content": 42,
+	}
+
+	if got, ok := Timestamp(record, TimestampFields); !ok || !got.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected timestamp %v, %v", got, ok)
+	}
+	if got, ok := Message(record, MessageFields); !ok || got != "started" {
+		t.Errorf("Expected the first string message field, got %q, %v", got, ok)
+	}
+	if _, ok := Message(record, []string{"missing"}); ok {
+		t.Error("Expected no message")
+	}
+}
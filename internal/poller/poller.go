@@ -0,0 +1,80 @@
+// Package poller polls a source in overlapping time windows and emits each
+// item once. It backs the tail -f style followers of logtail and k8sevents.
+package poller
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// Poller polls items in windows from the end of the previous poll, less
+// Overlap to cover ingestion lag, to now. The first window starts Lookback
+// before Run.
+type Poller[T any] struct {
+	Interval time.Duration
+	Overlap  time.Duration
+	Lookback time.Duration
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+	// Poll returns the items between start and end, stopping at the first
+	// error.
+	Poll func(ctx context.Context, start, end time.Time) iter.Seq2[T, error]
+	// Key identifies an item; items with a key already emitted are skipped.
+	Key func(T) string
+}
+
+// Run polls until ctx is done or a poll fails, sending new items to out in
+// the order of each poll. It returns nil once ctx is done, and the error of
+// the failed poll otherwise.
+func (p *Poller[T]) Run(ctx context.Context, out chan<- T) error {
+	now := p.Now
+	if now == nil {
+		now = time.Now
+	}
+	cursor := now().Add(-p.Lookback)
+	seen := map[string]time.Time{}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+
+		end := now()
+		for item, err := range p.Poll(ctx, cursor.Add(-p.Overlap), end) {
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			key := p.Key(item)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = end
+
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		// An item seen in a window ending before the next window starts
+		// cannot be returned again, whatever its own timestamp says.
+		cursor = end
+		for key, seenAt := range seen {
+			if seenAt.Before(cursor.Add(-p.Overlap)) {
+				delete(seen, key)
+			}
+		}
+
+		timer.Reset(p.Interval)
+	}
+}
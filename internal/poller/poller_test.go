@@ -0,0 +1,88 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+)
+
+type item struct {
+	key string
+	at  time.Time
+}
+
+func TestRun(t *testing.T) {
+	base := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	clock := base
+	items := []item{{"a", base.Add(-30 * time.Second)}, {"b", base.Add(-10 * time.Second)}}
+
+	var windows [][2]time.Time
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := &Poller[item]{
+		Interval: time.Millisecond,
+		Overlap:  20 * time.Second,
+		Lookback: time.Minute,
+		Now:      func() time.Time { return clock },
+		Key:      func(i item) string { return i.key },
+		Poll: func(ctx context.Context, start, end time.Time) iter.Seq2[item, error] {
+			windows = append(windows, [2]time.Time{start, end})
+			batch := slices.Clone(items)
+			switch len(windows) {
+			case 1:
+				clock = clock.Add(10 * time.Second)
+				items = append(items, item{"c", clock})
+			case 2:
+				// "b" is still in the window but was emitted by the first poll.
+				clock = clock.Add(time.Minute)
+			case 3:
+				return func(yield func(item, error) bool) { yield(item{}, errors.New("unavailable")) }
+			}
+			return func(yield func(item, error) bool) {
+				for _, i := range batch {
+					if !start.After(i.at) && i.at.Before(end.Add(time.Nanosecond)) && !yield(i, nil) {
+						return
+					}
+				}
+			}
+		},
+	}
+
+	out := make(chan item, 10)
+	err := p.Run(ctx, out)
+	close(out)
+	if err == nil || err.Error() != "unavailable" {
+		t.Errorf("Expected the poll error, got %v", err)
+	}
+
+	var keys []string
+	for i := range out {
+		keys = append(keys, i.key)
+	}
+	if !slices.Equal(keys, []string{"a", "b", "c"}) {
+		t.Errorf("Expected each item once, got %v", keys)
+	}
+	if len(windows) != 3 || !windows[0][0].Equal(base.Add(-80*time.Second)) || !windows[1][0].Equal(base.Add(-20*time.Second)) {
+		t.Errorf("Unexpected windows %v", windows)
+	}
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Poller[string]{
+		Interval: time.Hour,
+		Key:      func(s string) string { return s },
+		Poll: func(ctx context.Context, start, end time.Time) iter.Seq2[string, error] {
+			return func(yield func(string, error) bool) {
+				cancel()
+				yield("", ctx.Err())
+			}
+		},
+	}
+	if err := p.Run(ctx, make(chan string)); err != nil {
+		t.Errorf("Expected nil once the context is done, got %v", err)
+	}
+}
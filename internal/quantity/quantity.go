@@ -0,0 +1,48 @@
+// Package quantity renders CPU and memory amounts for reports. CPU is in
+// millicores and memory in bytes, as in the workload and metric APIs.
+package quantity
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// CPU renders millicores as a Kubernetes quantity, e.g. "250m" or "2".
+func CPU(millicores float64) string {
+	m := int64(millicores + 0.5)
+	if m != 0 && m%1000 == 0 {
+		return strconv.FormatInt(m/1000, 10)
+	}
+	return strconv.FormatInt(m, 10) + "m"
+}
+
+// Memory renders bytes as a Kubernetes quantity in binary units, e.g.
+// "512Mi" or "2Gi", rounding up to a mebibyte.
+func Memory(bytes float64) string {
+	mi := int64(math.Ceil(bytes / (1 << 20)))
+	if mi != 0 && mi%1024 == 0 {
+		return strconv.FormatInt(mi/1024, 10) + "Gi"
+	}
+	return strconv.FormatInt(mi, 10) + "Mi"
+}
+
+// Cores renders millicores as cores with one decimal, e.g. "2.5 cores".
+func Cores(millicores float64) string {
+	return fmt.Sprintf("%.1f cores", millicores/1000)
+}
+
+// Bytes renders bytes in the largest binary unit they fill, with one
+// decimal, e.g. "1.5GiB" or "512B".
+func Bytes(bytes float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	i := 0
+	for bytes >= 1024 && i < len(units)-1 {
+		bytes /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f%s", bytes, units[i])
+	}
+	return fmt.Sprintf("%.1f%s", bytes, units[i])
+}
@@ -0,0 +1,28 @@
+package quantity
+
+import "testing"
+
+const mi = 1 << 20
+
+func TestQuantities(t *testing.T) {
+	for millicores, want := range map[float64]string{0: "0m", 250: "250m", 1000: "1", 2500: "2500m", 99.6: "100m"} {
+		if got := CPU(millicores); got != want {
+			t.Errorf("CPU(%v) = %s, want %s", millicores, got, want)
+		}
+	}
+	for bytes, want := range map[float64]string{0: "0Mi", 1: "1Mi", 512 * mi: "512Mi", 2048 * mi: "2Gi", 1500 * mi: "1500Mi"} {
+		if got := Memory(bytes); got != want {
+			t.Errorf("Memory(%v) = %s, want %s", bytes, got, want)
+		}
+	}
+	for millicores, want := range map[float64]string{0: "0.0 cores", 250: "0.2 cores", 2500: "2.5 cores"} {
+		if got := Cores(millicores); got != want {
+			t.Errorf("Cores(%v) = %s, want %s", millicores, got, want)
+		}
+	}
+	for bytes, want := range map[float64]string{0: "0B", 512: "512B", 1536: "1.5KiB", 256 * mi: "256.0MiB", 3 << 30: "3.0GiB", 1 << 60: "1024.0PiB"} {
+		if got := Bytes(bytes); got != want {
+			t.Errorf("Bytes(%v) = %s, want %s", bytes, got, want)
+		}
+	}
+}
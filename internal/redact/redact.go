@@ -0,0 +1,110 @@
+// Package redact scrubs secrets from HTTP headers and JSON payloads before
+// they are logged or persisted.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Placeholder replaces every redacted value.
+const Placeholder = "[REDACTED]"
+
+// DefaultHeaders are the headers redacted by default.
+var DefaultHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// DefaultJSONPaths are redacted in every body. They cover the key returned by
+// createApiKey.
+var DefaultJSONPaths = []string{"apiKey"}
+
+// DefaultEndpointJSONPaths are redacted only in the bodies of the endpoints
+// they are keyed by, matched as path suffixes. They cover the key returned by
+// createIngestionKey and the keys listed by listIngestionKeys; elsewhere
+// "key" names search and label keys, which are not secret.
+var DefaultEndpointJSONPaths = map[string][]string{
+	"/api/rbac/ingestion-keys/create": {"key"},
+	"/api/rbac/ingestion-keys/list":   {"*.key"},
+}
+
+var emailRegex = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// Headers returns a copy of header with the values of the named headers replaced.
+func Headers(header http.Header, names []string) http.Header {
+	redacted := header.Clone()
+	for _, name := range names {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, Placeholder)
+		}
+	}
+	return redacted
+}
+
+// PathsFor returns paths followed by the paths of the endpoints in endpoints
+// that urlPath ends with.
+func PathsFor(urlPath string, paths []string, endpoints map[string][]string) []string {
+	for endpoint, endpointPaths := range endpoints {
+		if strings.HasSuffix(urlPath, endpoint) {
+			paths = append(slices.Clip(paths), endpointPaths...)
+		}
+	}
+	return paths
+}
+
+// JSON replaces the values at the given paths of a JSON document. Paths are
+// dot-separated object keys where "*" matches any key or array index, e.g.
+// "apiKey", "items.*.token". Bodies that are not valid JSON are returned unchanged.
+func JSON(body []byte, paths []string) []byte {
+	if len(paths) == 0 || len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return body
+	}
+
+	for _, path := range paths {
+		doc = redactPath(doc, strings.Split(path, "."))
+	}
+
+	redacted, err := json.Marshal(doc)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func redactPath(node interface{}, segments []string) interface{} {
+	if len(segments) == 0 {
+		return Placeholder
+	}
+
+	head, rest := segments[0], segments[1:]
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if head == "*" || head == key {
+				value[key] = redactPath(child, rest)
+			}
+		}
+	case []interface{}:
+		if head != "*" {
+			return value
+		}
+		for i, child := range value {
+			value[i] = redactPath(child, rest)
+		}
+	}
+	return node
+}
+
+// Emails replaces every email address in s.
+func Emails(s string) string {
+	return emailRegex.ReplaceAllString(s, Placeholder)
+}
@@ -0,0 +1,81 @@
+package redact
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		paths    []string
+		expected string
+	}{
+		{"top level key", `{"apiKey":"secret","id":"1"}`, []string{"apiKey"}, `{"apiKey":"[REDACTED]","id":"1"}`},
+		{"array wildcard", `[{"key":"a","name":"x"},{"key":"b"}]`, []string{"*.key"}, `[{"key":"[REDACTED]","name":"x"},{"key":"[REDACTED]"}]`},
+		{"nested path", `{"items":[{"auth":{"token":"t"}}]}`, []string{"items.*.auth.token"}, `{"items":[{"auth":{"token":"[REDACTED]"}}]}`},
+		{"missing path", `{"name":"x"}`, []string{"apiKey"}, `{"name":"x"}`},
+		{"not json", `title: monitor`, []string{"apiKey"}, `title: monitor`},
+		{"numbers preserved", `{"count":12345678901234567890}`, []string{"apiKey"}, `{"count":12345678901234567890}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := JSON([]byte(tc.body), tc.paths)
+			if !jsonEqual(string(got), tc.expected) {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPathsFor(t *testing.T) {
+	paths := PathsFor("/api/rbac/ingestion-keys/list", DefaultJSONPaths, DefaultEndpointJSONPaths)
+	if got := JSON([]byte(`[{"key":"secret","name":"ci"}]`), paths); !jsonEqual(string(got), `[{"key":"[REDACTED]","name":"ci"}]`) {
+		t.Errorf("expected the ingestion key to be redacted, got %s", got)
+	}
+
+	paths = PathsFor("/api/search/values", DefaultJSONPaths, DefaultEndpointJSONPaths)
+	if got := JSON([]byte(`{"key":"namespace","values":["shop"]}`), paths); !jsonEqual(string(got), `{"key":"namespace","values":["shop"]}`) {
+		t.Errorf("expected search keys to be kept, got %s", got)
+	}
+	if len(DefaultJSONPaths) != 1 {
+		t.Errorf("expected the defaults to be left untouched, got %v", DefaultJSONPaths)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+	header.Set("X-Backend-Id", "backend")
+
+	redacted := Headers(header, DefaultHeaders)
+	if got := redacted.Get("Authorization"); got != Placeholder {
+		t.Errorf("expected Authorization to be redacted, got %q", got)
+	}
+	if got := redacted.Get("X-Backend-Id"); got != "backend" {
+		t.Errorf("expected X-Backend-Id to be kept, got %q", got)
+	}
+	if got := header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("expected the original header to be untouched, got %q", got)
+	}
+}
+
+func TestEmails(t *testing.T) {
+	got := Emails(`{"createdBy":"jane.doe+ops@example.co.uk","name":"x"}`)
+	expected := `{"createdBy":"[REDACTED]","name":"x"}`
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func jsonEqual(a, b string) bool {
+	var va, vb interface{}
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return a == b
+	}
+	return reflect.DeepEqual(va, vb)
+}
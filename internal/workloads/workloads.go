@@ -0,0 +1,60 @@
+// Package workloads pages through WorkloadsList.
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// DefaultPageSize is the number of workloads fetched per request when the
+// request has no Limit.
+const DefaultPageSize = 500
+
+// Lister lists workloads. *groundcover.K8sService implements it.
+type Lister interface {
+	Workloads(ctx context.Context, request *models.WorkloadsListRequest) (*models.WorkloadsListResponse, error)
+}
+
+// All returns the workloads of request, fetching them in pages of
+// request.Limit workloads, DefaultPageSize if unset, from request.Skip on.
+// Paging stops once the response total is reached or, without a total, at
+// the first short page. Iteration stops at the first error, which is
+// yielded.
+func All(ctx context.Context, lister Lister, request *models.WorkloadsListRequest) iter.Seq2[*models.WorkloadsListItem, error] {
+	return func(yield func(*models.WorkloadsListItem, error) bool) {
+		page := *request
+		if page.Limit == 0 {
+			page.Limit = DefaultPageSize
+		}
+		if page.Conditions == nil {
+			page.Conditions = []*models.Condition{}
+		}
+		if page.Sources == nil {
+			page.Sources = []*models.Condition{}
+		}
+
+		for {
+			request := page
+			resp, err := lister.Workloads(ctx, &request)
+			if err != nil {
+				yield(nil, fmt.Errorf("error listing workloads from %d: %w", page.Skip, err))
+				return
+			}
+			for _, workload := range resp.Workloads {
+				if workload == nil {
+					continue
+				}
+				if !yield(workload, nil) {
+					return
+				}
+			}
+			page.Skip += uint32(len(resp.Workloads))
+			if len(resp.Workloads) == 0 || page.Skip >= resp.Total && (resp.Total != 0 || len(resp.Workloads) < int(page.Limit)) {
+				return
+			}
+		}
+	}
+}
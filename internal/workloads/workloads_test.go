@@ -0,0 +1,72 @@
+package workloads
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// pagedLister serves at most pageSize workloads per request, whatever the
+// requested limit.
+type pagedLister struct {
+	workloads []*models.WorkloadsListItem
+	pageSize  int
+	noTotal   bool
+	failAt    uint32
+	requests  []models.WorkloadsListRequest
+}
+
+func (l *pagedLister) Workloads(ctx context.Context, request *models.WorkloadsListRequest) (*models.WorkloadsListResponse, error) {
+	l.requests = append(l.requests, *request)
+	if l.failAt != 0 && request.Skip >= l.failAt {
+		return nil, errors.New("unavailable")
+	}
+	start := min(int(request.Skip), len(l.workloads))
+	end := min(start+int(request.Limit), start+l.pageSize, len(l.workloads))
+	resp := &models.WorkloadsListResponse{Workloads: l.workloads[start:end]}
+	if !l.noTotal {
+		resp.Total = uint32(len(l.workloads))
+	}
+	return resp, nil
+}
+
+func collect(t *testing.T, lister Lister, request *models.WorkloadsListRequest) ([]string, error) {
+	t.Helper()
+	var names []string
+	for workload, err := range All(context.Background(), lister, request) {
+		if err != nil {
+			return names, err
+		}
+		names = append(names, workload.Workload)
+	}
+	return names, nil
+}
+
+func TestAll(t *testing.T) {
+	workloads := []*models.WorkloadsListItem{{Workload: "a"}, {Workload: "b"}, {Workload: "c"}, {Workload: "d"}, {Workload: "e"}}
+
+	// The total is trusted over short pages.
+	lister := &pagedLister{workloads: workloads, pageSize: 2}
+	names, err := collect(t, lister, &models.WorkloadsListRequest{SortBy: "rps"})
+	if err != nil || len(names) != 5 || len(lister.requests) != 3 {
+		t.Errorf("Expected 5 workloads in 3 requests, got %v in %d: %v", names, len(lister.requests), err)
+	}
+	if lister.requests[0].Limit != DefaultPageSize || lister.requests[2].Skip != 4 || lister.requests[1].SortBy != "rps" || lister.requests[0].Conditions == nil {
+		t.Errorf("Unexpected requests %+v", lister.requests)
+	}
+
+	// Without a total, a short page is the last.
+	lister = &pagedLister{workloads: workloads, pageSize: 3, noTotal: true}
+	names, err = collect(t, lister, &models.WorkloadsListRequest{Limit: 3})
+	if err != nil || len(names) != 5 || len(lister.requests) != 2 {
+		t.Errorf("Expected 5 workloads in 2 requests, got %v in %d: %v", names, len(lister.requests), err)
+	}
+
+	lister = &pagedLister{workloads: workloads, pageSize: 2, failAt: 2}
+	names, err = collect(t, lister, &models.WorkloadsListRequest{})
+	if len(names) != 2 || err == nil || err.Error() != "error listing workloads from 2: unavailable" {
+		t.Errorf("Expected an error after 2 workloads, got %v and %v", names, err)
+	}
+}
@@ -0,0 +1,251 @@
+// Package apierrors provides a single error type for every groundcover API
+// failure, so callers can handle "not found" or "conflict" without a type
+// switch over the generated error types of each client package.
+//
+//	_, err := sdkClient.Monitors.DeleteMonitor(params, nil)
+//	if errors.Is(apierrors.From(err), apierrors.ErrNotFound) {
+//		// already gone
+//	}
+//
+// Clients created with transport.WithUnifiedErrors return *APIError values
+// directly, enriched with the request ID and raw response body.
+package apierrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+)
+
+// Sentinel errors matched by *APIError through errors.Is.
+var (
+	ErrNotFound     = errors.New("groundcover: not found")
+	ErrUnauthorized = errors.New("groundcover: unauthorized")
+	ErrRateLimited  = errors.New("groundcover: rate limited")
+	ErrConflict     = errors.New("groundcover: conflict")
+	ErrValidation   = errors.New("groundcover: validation failed")
+	ErrServer       = errors.New("groundcover: server error")
+)
+
+// APIError is a failed groundcover API call.
+type APIError struct {
+	StatusCode int
+	Operation  string // e.g. "monitors.DeleteMonitor"
+	Method     string
+	Path       string // path pattern, e.g. "/api/monitors/{id}"
+	Message    string // message returned by the server, if any
+	RequestID  string
+	RawBody    []byte
+	Err        error // the generated error the APIError was adapted from
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString("groundcover: ")
+	if e.Operation != "" {
+		b.WriteString(e.Operation)
+		b.WriteString(" ")
+	}
+	fmt.Fprintf(&b, "failed with status %d", e.StatusCode)
+	if e.Message != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Message)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request ID %s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// Unwrap returns the generated error, so errors.As still matches the
+// per-operation types such as *monitors.DeleteMonitorNotFound.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the sentinel corresponding to the status code.
+func (e *APIError) Is(target error) bool {
+	sentinel := sentinelFor(e.StatusCode)
+	return sentinel != nil && target == sentinel
+}
+
+func sentinelFor(status int) error {
+	switch {
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrUnauthorized
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusConflict:
+		return ErrConflict
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case status >= http.StatusInternalServerError:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+// As returns the *APIError in err's chain, adapting generated errors on the way.
+func As(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(From(err), &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// StatusCode returns the HTTP status of an API error, or 0 if err is not one.
+func StatusCode(err error) int {
+	if apiErr, ok := As(err); ok {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// generatedError is implemented by every error response type generated for the client packages.
+type generatedError interface {
+	error
+	Code() int
+}
+
+// routeRegex matches the "[METHOD /path][status]" prefix of generated error messages.
+var routeRegex = regexp.MustCompile(`^\[([A-Z]+) ([^\]]+)\]`)
+
+// From adapts a generated client error (e.g. *monitors.DeleteMonitorNotFound,
+// *apikeys.DeleteAPIKeyBadRequest or *runtime.APIError) to an *APIError.
+// Errors that are not API responses, and *APIError values, are returned unchanged.
+func From(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var existing *APIError
+	if errors.As(err, &existing) {
+		return err
+	}
+
+	var runtimeErr *runtime.APIError
+	if errors.As(err, &runtimeErr) {
+		apiErr := &APIError{
+			StatusCode: runtimeErr.Code,
+			Err:        err,
+		}
+		apiErr.Method, apiErr.Path = parseRoute(runtimeErr.OperationName)
+		if idx := strings.LastIndex(runtimeErr.OperationName, " "); idx >= 0 {
+			apiErr.Operation = runtimeErr.OperationName[idx+1:]
+		}
+		return apiErr
+	}
+
+	var generated generatedError
+	if !errors.As(err, &generated) {
+		return err
+	}
+
+	apiErr := &APIError{
+		StatusCode: generated.Code(),
+		Operation:  operationName(generated),
+		Message:    payloadMessage(generated),
+		Err:        err,
+	}
+	apiErr.Method, apiErr.Path = parseRoute(generated.Error())
+	return apiErr
+}
+
+// WithResponse sets the request ID and raw body of the response that produced
+// the error, filling in the message from the body when the generated payload
+// had none.
+func (e *APIError) WithResponse(requestID string, rawBody []byte) *APIError {
+	e.RequestID = requestID
+	e.RawBody = rawBody
+	if e.Message == "" {
+		e.Message = MessageFromBody(rawBody)
+	}
+	return e
+}
+
+// MessageFromBody extracts the "message" (or "error") field of a JSON error
+// body, falling back to the trimmed body itself for short plain-text bodies.
+func MessageFromBody(body []byte) string {
+	var payload struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Message != "" {
+			return payload.Message
+		}
+		return payload.Error
+	}
+
+	text := strings.TrimSpace(string(body))
+	if len(text) > 0 && len(text) <= 256 && !strings.ContainsAny(text, "{[<") {
+		return text
+	}
+	return ""
+}
+
+func parseRoute(s string) (method, path string) {
+	if m := routeRegex.FindStringSubmatch(s); m != nil {
+		return m[1], m[2]
+	}
+	return "", ""
+}
+
+// operationName derives "package.Operation" from the generated type name,
+// e.g. *monitors.DeleteMonitorNotFound becomes "monitors.DeleteMonitor".
+func operationName(err generatedError) string {
+	t := reflect.TypeOf(err)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	name := t.Name()
+	for _, suffix := range []string{strings.ReplaceAll(http.StatusText(err.Code()), " ", ""), "Default"} {
+		if suffix != "" && strings.HasSuffix(name, suffix) {
+			name = strings.TrimSuffix(name, suffix)
+			break
+		}
+	}
+
+	pkgPath := t.PkgPath()
+	return pkgPath[strings.LastIndex(pkgPath, "/")+1:] + "." + name
+}
+
+// payloadMessage returns the Message field of the generated error payload, if any.
+func payloadMessage(err generatedError) string {
+	method := reflect.ValueOf(err).MethodByName("GetPayload")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return ""
+	}
+
+	payload := method.Call(nil)[0]
+	for payload.Kind() == reflect.Ptr || payload.Kind() == reflect.Interface {
+		if payload.IsNil() {
+			return ""
+		}
+		payload = payload.Elem()
+	}
+
+	if payload.Kind() == reflect.String {
+		return payload.String()
+	}
+	if payload.Kind() != reflect.Struct {
+		return ""
+	}
+
+	field := payload.FieldByName("Message")
+	if field.IsValid() && field.Kind() == reflect.String {
+		return field.String()
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package apierrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/apikeys"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/monitors"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/policies"
+)
+
+func TestFrom_GeneratedErrors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       error
+		status    int
+		operation string
+		method    string
+		path      string
+		message   string
+		sentinel  error
+	}{
+		{
+			name:      "monitors not found",
+			err:       &monitors.DeleteMonitorNotFound{Payload: &monitors.DeleteMonitorNotFoundBody{Message: "monitor not found"}},
+			status:    404,
+			operation: "monitors.DeleteMonitor",
+			method:    "DELETE",
+			path:      "/api/monitors/{id}",
+			message:   "monitor not found",
+			sentinel:  ErrNotFound,
+		},
+		{
+			name:      "apikeys bad request",
+			err:       &apikeys.DeleteAPIKeyBadRequest{Payload: &apikeys.DeleteAPIKeyBadRequestBody{Message: "invalid id"}},
+			status:    400,
+			operation: "apikeys.DeleteAPIKey",
+			method:    "DELETE",
+			path:      "/api/rbac/apikey/{id}",
+			message:   "invalid id",
+			sentinel:  ErrValidation,
+		},
+		{
+			name:      "monitors conflict without payload",
+			err:       &monitors.CreateMonitorConflict{},
+			status:    409,
+			operation: "monitors.CreateMonitor",
+			method:    "POST",
+			path:      "/api/monitors",
+			sentinel:  ErrConflict,
+		},
+		{
+			name:      "runtime API error",
+			err:       runtime.NewAPIError("[GET /api/rbac/policy/{id}] getPolicy", nil, 429),
+			status:    429,
+			operation: "getPolicy",
+			method:    "GET",
+			path:      "/api/rbac/policy/{id}",
+			sentinel:  ErrRateLimited,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiErr, ok := As(fmt.Errorf("wrapped: %w", tc.err))
+			if !ok {
+				t.Fatalf("expected an *APIError for %T", tc.err)
+			}
+
+			if apiErr.StatusCode != tc.status {
+				t.Errorf("StatusCode: expected %d, got %d", tc.status, apiErr.StatusCode)
+			}
+			if apiErr.Operation != tc.operation {
+				t.Errorf("Operation: expected %s, got %s", tc.operation, apiErr.Operation)
+			}
+			if apiErr.Method != tc.method || apiErr.Path != tc.path {
+				t.Errorf("route: expected %s %s, got %s %s", tc.method, tc.path, apiErr.Method, apiErr.Path)
+			}
+			if apiErr.Message != tc.message {
+				t.Errorf("Message: expected %q, got %q", tc.message, apiErr.Message)
+			}
+			if !errors.Is(apiErr, tc.sentinel) {
+				t.Errorf("expected errors.Is(err, %v)", tc.sentinel)
+			}
+			if !errors.Is(apiErr, tc.err) {
+				t.Errorf("expected the generated error to stay in the chain")
+			}
+		})
+	}
+}
+
+func TestFrom_NonAPIErrors(t *testing.T) {
+	if From(nil) != nil {
+		t.Error("expected nil for a nil error")
+	}
+
+	plain := errors.New("connection refused")
+	if From(plain) != plain {
+		t.Error("expected non-API errors to be returned unchanged")
+	}
+	if StatusCode(plain) != 0 {
+		t.Error("expected status 0 for non-API errors")
+	}
+}
+
+func TestAPIError_Is(t *testing.T) {
+	err := error(&APIError{StatusCode: 403})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Error("expected 403 to match ErrUnauthorized")
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("expected 403 not to match ErrNotFound")
+	}
+
+	var notFound *policies.GetPolicyNotFound
+	if errors.As(err, &notFound) {
+		t.Error("expected no generated error in the chain")
+	}
+}
+
+func TestMessageFromBody(t *testing.T) {
+	testCases := map[string]string{
+		`{"message":"boom"}`:  "boom",
+		`{"error":"denied"}`:  "denied",
+		"plain text failure":  "plain text failure",
+		"<html>oops</html>":   "",
+		`{"unrelated":"yes"}`: "",
+	}
+
+	for body, expected := range testCases {
+		if got := MessageFromBody([]byte(body)); got != expected {
+			t.Errorf("MessageFromBody(%q): expected %q, got %q", body, expected, got)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+// Package capacity reports the capacity of Kubernetes clusters: how much of
+// their allocatable CPU and memory is requested, limited and used, how much
+// headroom is left, and how their pods are doing.
+//
+// A Reporter lists the clusters through ClustersList and checks them
+// against Thresholds, which produce warnings:
+//
+//	reporter := capacity.NewReporter(client.K8s)
+//	report, err := reporter.Report(ctx)
+//	if err != nil {
+//		...
+//	}
+//	for _, warning := range report.Warnings {
+//		fmt.Println(warning.Cluster, warning.Message)
+//	}
+//	os.WriteFile("capacity.md", []byte(report.Markdown()), 0o644)
+//
+// CPU is in millicores and memory in bytes, as returned by ClustersList.
+package capacity
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/utils"
+)
+
+// Lister lists clusters. *groundcover.K8sService implements it.
+type Lister interface {
+	Clusters(ctx context.Context, request *models.ClustersListRequest) (*models.ClustersListResponse, error)
+}
+
+// Resources of a cluster.
+const (
+	ResourceCPU    = "cpu"
+	ResourceMemory = "memory"
+)
+
+// Pod phases.
+const (
+	PhasePending   = "Pending"
+	PhaseRunning   = "Running"
+	PhaseSucceeded = "Succeeded"
+	PhaseFailed    = "Failed"
+	PhaseUnknown   = "Unknown"
+)
+
+// Kinds of warnings.
+const (
+	WarningRequests      = "RequestsCommitted"
+	WarningLimits        = "LimitsOvercommitted"
+	WarningUsage         = "UsageHigh"
+	WarningIdleRequests  = "RequestsIdle"
+	WarningPendingPods   = "PodsPending"
+	WarningFailedPods    = "PodsFailed"
+	WarningUnknownPods   = "PodsUnknown"
+	WarningNoAllocatable = "NoAllocatable"
+)
+
+// Thresholds are the levels past which a cluster gets a warning. Ratios are
+// fractions of allocatable capacity; a zero threshold is disabled.
+type Thresholds struct {
+	// Requests warns when requests reach this ratio, leaving little room to
+	// schedule pods.
+	Requests float64 `json:"requests"`
+	// Limits warns when limits reach this ratio, i.e. the cluster is
+	// overcommitted this many times and pods may be throttled or OOM
+	// killed under load.
+	Limits float64 `json:"limits"`
+	// Usage warns when usage reaches this ratio.
+	Usage float64 `json:"usage"`
+	// IdleRequests warns when usage is below this fraction of requests,
+	// i.e. capacity is reserved but unused.
+	IdleRequests float64 `json:"idleRequests"`
+	// PendingPods, FailedPods and UnknownPods warn when more pods than this
+	// are in the phase. Negative values are disabled.
+	PendingPods int64 `json:"pendingPods"`
+	FailedPods  int64 `json:"failedPods"`
+	UnknownPods int64 `json:"unknownPods"`
+}
+
+// DefaultThresholds are the thresholds of a Reporter without
+// WithThresholds.
+var DefaultThresholds = Thresholds{
+	Requests:     0.85,
+	Limits:       1.5,
+	Usage:        0.8,
+	IdleRequests: 0.25,
+	PendingPods:  0,
+	FailedPods:   0,
+	UnknownPods:  0,
+}
+
+// Resource is the capacity of a cluster in CPU or memory.
+type Resource struct {
+	Allocatable float64 `json:"allocatable"`
+	Requests    float64 `json:"requests"`
+	Limits      float64 `json:"limits"`
+	Usage       float64 `json:"usage"`
+	// RequestsRatio, LimitsRatio and UsageRatio are fractions of
+	// allocatable. LimitsRatio above 1 is the overcommit ratio.
+	RequestsRatio float64 `json:"requestsRatio"`
+	LimitsRatio   float64 `json:"limitsRatio"`
+	UsageRatio    float64 `json:"usageRatio"`
+	// Headroom is the allocatable capacity not yet requested, which new
+	// pods can be scheduled on.
+	Headroom float64 `json:"headroom"`
+	// HeadroomNodes is the headroom in average nodes.
+	HeadroomNodes float64 `json:"headroomNodes"`
+}
+
+// newResource computes the ratios of a resource, from the percentages
+// reported by ClustersList when there is no allocatable amount.
+func newResource(allocatable, requests, limits, usage, requestsPercent, limitsPercent, usagePercent float64, nodes int64) Resource {
+	r := Resource{
+		Allocatable:   allocatable,
+		Requests:      requests,
+		Limits:        limits,
+		Usage:         usage,
+		RequestsRatio: requestsPercent / 100,
+		LimitsRatio:   limitsPercent / 100,
+		UsageRatio:    usagePercent / 100,
+	}
+	if allocatable > 0 {
+		r.RequestsRatio, r.LimitsRatio, r.UsageRatio = requests/allocatable, limits/allocatable, usage/allocatable
+		r.Headroom = max(allocatable-requests, 0)
+		if nodes > 0 {
+			r.HeadroomNodes = r.Headroom / (allocatable / float64(nodes))
+		}
+	}
+	return r
+}
+
+// Cluster is the capacity of a cluster.
+type Cluster struct {
+	Name              string `json:"name"`
+	Env               string `json:"env,omitempty"`
+	CloudProvider     string `json:"cloudProvider,omitempty"`
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	Nodes             int64  `json:"nodes"`
+
+	CPU    Resource `json:"cpu"`
+	Memory Resource `json:"memory"`
+
+	// Pods are the number of pods by phase.
+	Pods      map[string]int64 `json:"pods"`
+	TotalPods int64            `json:"totalPods"`
+
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// Warning is a threshold crossed by a cluster.
+type Warning struct {
+	Cluster string `json:"cluster"`
+	Kind    string `json:"kind"`
+	// Resource is ResourceCPU or ResourceMemory for resource warnings.
+	Resource  string  `json:"resource,omitempty"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Message   string  `json:"message"`
+}
+
+// Report is the capacity of all clusters.
+type Report struct {
+	GeneratedAt time.Time  `json:"generatedAt"`
+	Thresholds  Thresholds `json:"thresholds"`
+	// Clusters are sorted by name.
+	Clusters []*Cluster `json:"clusters"`
+	// Total is the capacity of all clusters together, without warnings.
+	Total *Cluster `json:"total"`
+	// Warnings are the warnings of all clusters.
+	Warnings []Warning `json:"warnings"`
+}
+
+// Option customizes a Reporter.
+type Option func(*Reporter)
+
+// WithThresholds sets the warning thresholds. Defaults to
+// DefaultThresholds.
+func WithThresholds(thresholds Thresholds) Option {
+	return func(r *Reporter) {
+		r.thresholds = thresholds
+	}
+}
+
+// WithSources restricts the report to sources such as an environment. The
+// conditions are sent as the request Sources.
+func WithSources(sources *utils.ConditionSet) Option {
+	return func(r *Reporter) {
+		r.sources = sources.Build()
+	}
+}
+
+// Reporter builds capacity reports.
+type Reporter struct {
+	lister     Lister
+	thresholds Thresholds
+	sources    []*models.Condition
+	now        func() time.Time
+}
+
+// NewReporter creates a Reporter listing clusters through lister.
+func NewReporter(lister Lister, options ...Option) *Reporter {
+	r := &Reporter{
+		lister:     lister,
+		thresholds: DefaultThresholds,
+		sources:    []*models.Condition{},
+		now:        time.Now,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Report lists the clusters and reports their capacity.
+func (r *Reporter) Report(ctx context.Context) (*Report, error) {
+	resp, err := r.lister.Clusters(ctx, &models.ClustersListRequest{Sources: r.sources})
+	if err != nil {
+		return nil, fmt.Errorf("error listing clusters: %w", err)
+	}
+	return NewReport(resp.Clusters, r.thresholds, r.now()), nil
+}
+
+// NewReport reports the capacity of clusters as listed by ClustersList.
+func NewReport(clusters []*models.ClustersListResult, thresholds Thresholds, generatedAt time.Time) *Report {
+	report := &Report{GeneratedAt: generatedAt, Thresholds: thresholds, Clusters: []*Cluster{}, Warnings: []Warning{}}
+	var total models.ClustersListResult
+	total.Name, total.Pods = "Total", map[string]int64{}
+	for _, c := range clusters {
+		if c == nil {
+			continue
+		}
+		cluster := newCluster(c)
+		cluster.Warnings = thresholds.check(cluster)
+		report.Clusters = append(report.Clusters, cluster)
+
+		total.NodesCount += c.NodesCount
+		total.CPUAllocatable += c.CPUAllocatable
+		total.CPURequest += c.CPURequest
+		total.CPULimit += c.CPULimit
+		total.CPUUsage += c.CPUUsage
+		total.MemoryAllocatable += c.MemoryAllocatable
+		total.MemoryRequest += c.MemoryRequest
+		total.MemoryLimit += c.MemoryLimit
+		total.MemoryUsage += c.MemoryUsage
+		for phase, count := range cluster.Pods {
+			total.Pods[phase] += count
+		}
+	}
+	slices.SortFunc(report.Clusters, func(a, b *Cluster) int { return cmp.Compare(a.Name, b.Name) })
+	for _, cluster := range report.Clusters {
+		report.Warnings = append(report.Warnings, cluster.Warnings...)
+	}
+	report.Total = newCluster(&total)
+	return report
+}
+
+func newCluster(c *models.ClustersListResult) *Cluster {
+	cluster := &Cluster{
+		Name:              c.Name,
+		Env:               c.Env,
+		CloudProvider:     c.CloudProvider,
+		KubernetesVersion: c.KubernetesVersion,
+		Nodes:             c.NodesCount,
+		CPU: newResource(c.CPUAllocatable, c.CPURequest, c.CPULimit, c.CPUUsage,
+			c.CPURequestAllocatablePercent, c.CPULimitAllocatablePercent, c.CPUUsageAllocatablePercent, c.NodesCount),
+		Memory: newResource(c.MemoryAllocatable, c.MemoryRequest, c.MemoryLimit, c.MemoryUsage,
+			c.MemoryRequestAllocatablePercent, c.MemoryLimitAllocatablePercent, c.MemoryUsageAllocatablePercent, c.NodesCount),
+		Pods: map[string]int64{},
+	}
+	for phase, count := range c.Pods {
+		// Phases are reported in varying case.
+		if canonical := canonicalPhase(phase); canonical != "" {
+			phase = canonical
+		}
+		cluster.Pods[phase] += count
+		cluster.TotalPods += count
+	}
+	return cluster
+}
+
+func canonicalPhase(phase string) string {
+	for _, canonical := range []string{PhasePending, PhaseRunning, PhaseSucceeded, PhaseFailed, PhaseUnknown} {
+		if strings.EqualFold(phase, canonical) {
+			return canonical
+		}
+	}
+	return ""
+}
+
+// check returns the warnings of a cluster.
+func (t Thresholds) check(c *Cluster) []Warning {
+	var warnings []Warning
+	add := func(kind, resource string, value, threshold float64, format string, args ...interface{}) {
+		warnings = append(warnings, Warning{
+			Cluster:   c.Name,
+			Kind:      kind,
+			Resource:  resource,
+			Value:     value,
+			Threshold: threshold,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, resource := range []struct {
+		name  string
+		label string
+		r     Resource
+	}{{ResourceCPU, "CPU", c.CPU}, {ResourceMemory, "Memory", c.Memory}} {
+		r := resource.r
+		if r.Allocatable <= 0 && r.RequestsRatio == 0 && r.LimitsRatio == 0 && r.UsageRatio == 0 {
+			if c.Nodes > 0 {
+				add(WarningNoAllocatable, resource.name, 0, 0, "%s allocatable capacity is not reported", resource.label)
+			}
+			continue
+		}
+		if t.Requests > 0 && r.RequestsRatio >= t.Requests {
+			add(WarningRequests, resource.name, r.RequestsRatio, t.Requests,
+				"%s requests are at %.0f%% of allocatable (threshold %.0f%%)", resource.label, r.RequestsRatio*100, t.Requests*100)
+		}
+		if t.Limits > 0 && r.LimitsRatio >= t.Limits {
+			add(WarningLimits, resource.name, r.LimitsRatio, t.Limits,
+				"%s limits are overcommitted %.2fx allocatable (threshold %.2fx)", resource.label, r.LimitsRatio, t.Limits)
+		}
+		if t.Usage > 0 && r.UsageRatio >= t.Usage {
+			add(WarningUsage, resource.name, r.UsageRatio, t.Usage,
+				"%s usage is at %.0f%% of allocatable (threshold %.0f%%)", resource.label, r.UsageRatio*100, t.Usage*100)
+		}
+		if t.IdleRequests > 0 && r.Requests > 0 && r.Usage/r.Requests < t.IdleRequests {
+			add(WarningIdleRequests, resource.name, r.Usage/r.Requests, t.IdleRequests,
+				"%s usage is only %.0f%% of requests (threshold %.0f%%)", resource.label, r.Usage/r.Requests*100, t.IdleRequests*100)
+		}
+	}
+
+	for _, pods := range []struct {
+		kind, phase string
+		threshold   int64
+	}{
+		{WarningPendingPods, PhasePending, t.PendingPods},
+		{WarningFailedPods, PhaseFailed, t.FailedPods},
+		{WarningUnknownPods, PhaseUnknown, t.UnknownPods},
+	} {
+		if count := c.Pods[pods.phase]; pods.threshold >= 0 && count > pods.threshold {
+			noun := "pods"
+			if count == 1 {
+				noun = "pod"
+			}
+			add(pods.kind, "", float64(count), float64(pods.threshold), "%d %s %s (threshold %d)", count, strings.ToLower(pods.phase), noun, pods.threshold)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,135 @@
+package capacity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+const gi = 1 << 30
+
+type fakeLister struct {
+	clusters []*models.ClustersListResult
+	err      error
+	requests []models.ClustersListRequest
+}
+
+func (l *fakeLister) Clusters(ctx context.Context, request *models.ClustersListRequest) (*models.ClustersListResponse, error) {
+	l.requests = append(l.requests, *request)
+	if l.err != nil {
+		return nil, l.err
+	}
+	return &models.ClustersListResponse{Clusters: l.clusters, TotalCount: int64(len(l.clusters))}, nil
+}
+
+func fixture() *fakeLister {
+	return &fakeLister{clusters: []*models.ClustersListResult{
+		{
+			Name: "staging", Env: "staging", NodesCount: 2,
+			CPUAllocatable: 8000, CPURequest: 2000, CPULimit: 4000, CPUUsage: 300,
+			MemoryAllocatable: 32 * gi, MemoryRequest: 8 * gi, MemoryLimit: 16 * gi, MemoryUsage: 6 * gi,
+			Pods: map[string]int64{"Running": 40, "Succeeded": 2},
+		},
+		{
+			Name: "prod", Env: "prod", CloudProvider: "AWS", KubernetesVersion: "1.31", NodesCount: 4,
+			CPUAllocatable: 16000, CPURequest: 14400, CPULimit: 32000, CPUUsage: 9000,
+			MemoryAllocatable: 64 * gi, MemoryRequest: 40 * gi, MemoryLimit: 80 * gi, MemoryUsage: 54 * gi,
+			Pods: map[string]int64{"running": 120, "pending": 3, "Failed": 1, "Evicted": 2},
+		},
+	}}
+}
+
+func kinds(warnings []Warning) []string {
+	var kinds []string
+	for _, warning := range warnings {
+		kinds = append(kinds, warning.Resource+":"+warning.Kind)
+	}
+	return kinds
+}
+
+func TestReport(t *testing.T) {
+	lister := fixture()
+	reporter := NewReporter(lister)
+	reporter.now = func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) }
+	report, err := reporter.Report(context.Background())
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if len(lister.requests) != 1 || lister.requests[0].Sources == nil {
+		t.Errorf("Unexpected requests %+v", lister.requests)
+	}
+
+	if len(report.Clusters) != 2 || report.Clusters[0].Name != "prod" {
+		t.Fatalf("Expected prod and staging, got %+v", report.Clusters)
+	}
+	prod := report.Clusters[0]
+	if prod.CPU.RequestsRatio != 0.9 || prod.CPU.LimitsRatio != 2 || prod.CPU.Headroom != 1600 || prod.CPU.HeadroomNodes != 0.4 {
+		t.Errorf("Unexpected prod CPU %+v", prod.CPU)
+	}
+	if prod.Pods[PhaseRunning] != 120 || prod.Pods[PhasePending] != 3 || prod.Pods["Evicted"] != 2 || prod.TotalPods != 126 {
+		t.Errorf("Unexpected prod pods %v", prod.Pods)
+	}
+	want := "cpu:RequestsCommitted cpu:LimitsOvercommitted memory:UsageHigh :PodsPending :PodsFailed"
+	if got := strings.Join(kinds(prod.Warnings), " "); got != want {
+		t.Errorf("Unexpected prod warnings %s", got)
+	}
+	if got := strings.Join(kinds(report.Clusters[1].Warnings), " "); got != "cpu:RequestsIdle" {
+		t.Errorf("Unexpected staging warnings %s", got)
+	}
+	if len(report.Warnings) != 6 || report.Warnings[0].Message != "CPU requests are at 90% of allocatable (threshold 85%)" {
+		t.Errorf("Unexpected warnings %+v", report.Warnings)
+	}
+	if total := report.Total; total.Nodes != 6 || total.CPU.Allocatable != 24000 || total.Memory.RequestsRatio != 0.5 || total.TotalPods != 168 || total.Warnings != nil {
+		t.Errorf("Unexpected total %+v", total)
+	}
+
+	assertGolden(t, "testdata/report.md", report.Markdown())
+	assertGolden(t, "testdata/report.html", report.HTML())
+
+	encoded, _ := json.Marshal(report)
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded["generatedAt"] != "2026-10-18T12:00:00Z" || len(decoded["warnings"].([]interface{})) != 6 {
+		t.Errorf("Unexpected JSON %s", encoded)
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	thresholds := Thresholds{Limits: 3, PendingPods: 5, FailedPods: -1, UnknownPods: -1}
+	report := NewReport(fixture().clusters, thresholds, time.Time{})
+	if len(report.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", kinds(report.Warnings))
+	}
+
+	// Percentages stand in for missing amounts.
+	report = NewReport([]*models.ClustersListResult{
+		{Name: "a", NodesCount: 1, CPURequestAllocatablePercent: 95, MemoryLimitAllocatablePercent: 160},
+		{Name: "b", NodesCount: 1},
+	}, DefaultThresholds, time.Time{})
+	if got := strings.Join(kinds(report.Warnings), " "); got != "cpu:RequestsCommitted memory:LimitsOvercommitted cpu:NoAllocatable memory:NoAllocatable" {
+		t.Errorf("Unexpected warnings %s", got)
+	}
+	if report.Markdown() == "" || !strings.Contains(report.HTML(), "<li><strong>b</strong>: CPU allocatable capacity is not reported</li>") {
+		t.Errorf("Unexpected HTML %s", report.HTML())
+	}
+
+	if _, err := NewReporter(&fakeLister{err: errors.New("unavailable")}).Report(context.Background()); err == nil || err.Error() != "error listing clusters: unavailable" {
+		t.Errorf("Expected the listing error, got %v", err)
+	}
+}
+
+func assertGolden(t *testing.T, path, got string) {
+	t.Helper()
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs:\n%s", path, got)
+	}
+}
@@ -0,0 +1,136 @@
+package capacity
+
+import (
+	"fmt"
+	"html/template"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/quantity"
+)
+
+// section is a titled table of a report, rendered by Markdown and HTML.
+type section struct {
+	Title   string
+	Headers []string
+	Rows    [][]string
+}
+
+// sections returns the tables of the report, each with a last row for the
+// total of all clusters.
+func (r *Report) sections() []section {
+	clusters := append(slices.Clone(r.Clusters), r.Total)
+
+	resources := []section{{Title: "CPU"}, {Title: "Memory"}}
+	for i, format := range []func(float64) string{quantity.Cores, quantity.Bytes} {
+		resources[i].Headers = []string{"Cluster", "Nodes", "Allocatable", "Requests", "Limits", "Usage", "Headroom"}
+		for _, c := range clusters {
+			res := c.CPU
+			if i == 1 {
+				res = c.Memory
+			}
+			resources[i].Rows = append(resources[i].Rows, []string{
+				c.Name,
+				fmt.Sprint(c.Nodes),
+				format(res.Allocatable),
+				fmt.Sprintf("%s (%.0f%%)", format(res.Requests), res.RequestsRatio*100),
+				fmt.Sprintf("%s (%.2fx)", format(res.Limits), res.LimitsRatio),
+				fmt.Sprintf("%s (%.0f%%)", format(res.Usage), res.UsageRatio*100),
+				fmt.Sprintf("%s (%.1f nodes)", format(res.Headroom), res.HeadroomNodes),
+			})
+		}
+	}
+
+	// Known phases first, then any others the API reports.
+	phases := []string{PhaseRunning, PhasePending, PhaseFailed, PhaseSucceeded, PhaseUnknown}
+	var others []string
+	for phase := range r.Total.Pods {
+		if !slices.Contains(phases, phase) {
+			others = append(others, phase)
+		}
+	}
+	slices.Sort(others)
+	phases = append(phases, others...)
+	pods := section{Title: "Pods", Headers: append(append([]string{"Cluster"}, phases...), "Total")}
+	for _, c := range clusters {
+		row := []string{c.Name}
+		for _, phase := range phases {
+			row = append(row, fmt.Sprint(c.Pods[phase]))
+		}
+		pods.Rows = append(pods.Rows, append(row, fmt.Sprint(c.TotalPods)))
+	}
+
+	return append(resources, pods)
+}
+
+// Markdown renders the report as Markdown, with a table per resource, the
+// pod phases and the warnings.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Cluster capacity\n\nGenerated %s for %d clusters.\n", r.GeneratedAt.UTC().Format(time.RFC3339), len(r.Clusters))
+	for _, s := range r.sections() {
+		fmt.Fprintf(&b, "\n## %s\n\n| %s |\n|%s\n", s.Title, strings.Join(s.Headers, " | "), strings.Repeat(" --- |", len(s.Headers)))
+		for i, row := range s.Rows {
+			if i == len(s.Rows)-1 {
+				row = slices.Clone(row)
+				row[0] = "**" + row[0] + "**"
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(row, " | "))
+		}
+	}
+
+	b.WriteString("\n## Warnings\n\n")
+	if len(r.Warnings) == 0 {
+		b.WriteString("No warnings.\n")
+	}
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(&b, "- **%s**: %s\n", warning.Cluster, warning.Message)
+	}
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("capacity").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Cluster capacity</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+tr:last-child td { font-weight: bold; }
+.warnings li { color: #b45309; }
+</style>
+</head>
+<body>
+<h1>Cluster capacity</h1>
+<p>Generated {{.GeneratedAt}} for {{.Count}} clusters.</p>
+{{range .Sections}}<h2>{{.Title}}</h2>
+<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}<h2>Warnings</h2>
+{{if .Warnings}}<ul class="warnings">
+{{range .Warnings}}<li><strong>{{.Cluster}}</strong>: {{.Message}}</li>
+{{end}}</ul>
+{{else}}<p>No warnings.</p>
+{{end}}</body>
+</html>
+`))
+
+// HTML renders the report as a standalone HTML page with the same content
+// as Markdown.
+func (r *Report) HTML() string {
+	var b strings.Builder
+	// Executing into a strings.Builder cannot fail.
+	htmlTemplate.Execute(&b, map[string]interface{}{
+		"GeneratedAt": r.GeneratedAt.UTC().Format(time.RFC3339),
+		"Count":       len(r.Clusters),
+		"Sections":    r.sections(),
+		"Warnings":    r.Warnings,
+	})
+	return b.String()
+}
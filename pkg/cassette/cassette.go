@@ -0,0 +1,437 @@
+// Package cassette records groundcover API traffic to files and replays it,
+// so tests built on the SDK run deterministically without a live backend.
+//
+// A Recorder plugs into the client through transport.WithTransportWrapper:
+//
+//	rec, err := cassette.New("testdata/monitors.yaml", cassette.ModeReplay)
+//	...
+//	sdkClient, err := transport.NewSDKClient(apiKey, backendID, baseURL,
+//		transport.WithTransportWrapper(rec.Wrap))
+//
+// In ModeRecord requests go to the API and the scrubbed request/response pairs
+// are written to the cassette by Save. In ModeReplay no request leaves the
+// process; every request must match a recorded interaction or the call fails
+// with an *UnmatchedRequestError.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/redact"
+)
+
+// Mode selects whether a Recorder records or replays.
+type Mode int
+
+// Possible Modes.
+const (
+	ModeReplay Mode = iota
+	ModeRecord
+)
+
+// Match selects the request properties compared during replay.
+type Match int
+
+// Possible Match flags, combined with |.
+const (
+	MatchMethod Match = 1 << iota
+	MatchPath
+	MatchQuery
+	MatchBody
+
+	MatchDefault = MatchMethod | MatchPath | MatchQuery | MatchBody
+)
+
+// Request is a recorded request.
+type Request struct {
+	Method  string              `yaml:"method"`
+	Path    string              `yaml:"path"`
+	Query   string              `yaml:"query,omitempty"`
+	Headers map[string][]string `yaml:"headers,omitempty"`
+	Body    string              `yaml:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int                 `yaml:"status"`
+	Headers    map[string][]string `yaml:"headers,omitempty"`
+	Body       string              `yaml:"body,omitempty"`
+}
+
+// Interaction is a recorded request/response pair.
+type Interaction struct {
+	Request  Request  `yaml:"request"`
+	Response Response `yaml:"response"`
+}
+
+// Cassette is the on-disk format of a recording.
+type Cassette struct {
+	Interactions []*Interaction `yaml:"interactions"`
+}
+
+// UnmatchedRequestError is returned in ModeReplay for requests that match no recorded interaction.
+type UnmatchedRequestError struct {
+	Cassette string
+	Method   string
+	Path     string
+	Query    string
+	Body     string
+}
+
+func (e *UnmatchedRequestError) Error() string {
+	msg := fmt.Sprintf("cassette %s: no recorded interaction matches %s %s", e.Cassette, e.Method, e.Path)
+	if e.Query != "" {
+		msg += "?" + e.Query
+	}
+	if e.Body != "" {
+		msg += " with body " + e.Body
+	}
+	return msg
+}
+
+// Option customizes a Recorder.
+type Option func(*Recorder)
+
+// WithMatch sets the request properties compared during replay. Defaults to MatchDefault.
+func WithMatch(match Match) Option {
+	return func(r *Recorder) {
+		r.match = match
+	}
+}
+
+// WithIgnoredBodyPaths excludes JSON body paths (e.g. "Start", "End") from
+// body matching, for values that change on every run.
+func WithIgnoredBodyPaths(paths ...string) Option {
+	return func(r *Recorder) {
+		r.ignoredPaths = append(r.ignoredPaths, paths...)
+	}
+}
+
+// WithRedactedHeaders scrubs additional headers. Authorization is always scrubbed.
+func WithRedactedHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		r.redactedHeaders = append(r.redactedHeaders, names...)
+	}
+}
+
+// WithRedactedJSONPaths scrubs additional JSON body paths. The keys returned
+// by the API key and ingestion key endpoints are always scrubbed.
+func WithRedactedJSONPaths(paths ...string) Option {
+	return func(r *Recorder) {
+		r.redactedPaths = append(r.redactedPaths, paths...)
+	}
+}
+
+// WithRepeats lets replay serve an already used interaction again when no
+// unused one matches, e.g. for polling loops.
+func WithRepeats() Option {
+	return func(r *Recorder) {
+		r.repeats = true
+	}
+}
+
+// Recorder records or replays HTTP interactions.
+type Recorder struct {
+	path            string
+	mode            Mode
+	match           Match
+	ignoredPaths    []string
+	redactedHeaders []string
+	redactedPaths   []string
+	repeats         bool
+
+	mu        sync.Mutex
+	cassette  *Cassette
+	used      []bool
+	unmatched []*UnmatchedRequestError
+}
+
+// New creates a Recorder for the cassette file at path. In ModeReplay the
+// file must exist.
+func New(path string, mode Mode, options ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:            path,
+		mode:            mode,
+		match:           MatchDefault,
+		redactedHeaders: append([]string{}, redact.DefaultHeaders...),
+		redactedPaths:   append([]string{}, redact.DefaultJSONPaths...),
+		cassette:        &Cassette{},
+	}
+	for _, option := range options {
+		option(r)
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading cassette: %w", err)
+		}
+		if err := yaml.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("error parsing cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Wrap returns an http.RoundTripper that records through next, or replays
+// without calling it. It has the signature expected by transport.WithTransportWrapper.
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if r.mode == ModeRecord {
+			return r.record(next, req)
+		}
+		return r.replay(req)
+	})
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*Interaction(nil), r.cassette.Interactions...)
+}
+
+// Unmatched returns the requests that failed to match during replay.
+func (r *Recorder) Unmatched() []*UnmatchedRequestError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*UnmatchedRequestError(nil), r.unmatched...)
+}
+
+// Unused returns the recorded interactions that were never replayed.
+func (r *Recorder) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []*Interaction
+	for i, interaction := range r.cassette.Interactions {
+		if i < len(r.used) && !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// Save atomically writes the recorded interactions to the cassette file.
+// It is a no-op in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := yaml.Marshal(r.cassette)
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), r.path)
+}
+
+func (r *Recorder) record(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := drainBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := &Interaction{
+		Request: Request{
+			Method:  req.Method,
+			Path:    req.URL.Path,
+			Query:   redact.Emails(req.URL.RawQuery),
+			Headers: r.scrubHeaders(req.Header),
+			Body:    r.scrubBody(req.URL.Path, reqBody),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    r.scrubHeaders(resp.Header),
+			Body:       r.scrubBody(req.URL.Path, respBody),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	incoming := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  redact.Emails(req.URL.RawQuery),
+		Body:   r.scrubBody(req.URL.Path, reqBody),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !r.matches(&interaction.Request, &incoming) {
+			continue
+		}
+		if !r.used[i] {
+			match = i
+			break
+		}
+		if r.repeats && match < 0 {
+			match = i
+		}
+	}
+
+	if match < 0 {
+		unmatched := &UnmatchedRequestError{
+			Cassette: r.path,
+			Method:   incoming.Method,
+			Path:     incoming.Path,
+			Query:    incoming.Query,
+			Body:     incoming.Body,
+		}
+		r.unmatched = append(r.unmatched, unmatched)
+		return nil, unmatched
+	}
+
+	r.used[match] = true
+	recorded := r.cassette.Interactions[match].Response
+
+	header := http.Header{}
+	for name, values := range recorded.Headers {
+		header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) matches(recorded, incoming *Request) bool {
+	if r.match&MatchMethod != 0 && recorded.Method != incoming.Method {
+		return false
+	}
+	if r.match&MatchPath != 0 && recorded.Path != incoming.Path {
+		return false
+	}
+	if r.match&MatchQuery != 0 && recorded.Query != incoming.Query {
+		return false
+	}
+	if r.match&MatchBody != 0 && r.normalizeBody(recorded.Body) != r.normalizeBody(incoming.Body) {
+		return false
+	}
+	return true
+}
+
+// normalizeBody makes JSON bodies comparable regardless of key order and whitespace.
+func (r *Recorder) normalizeBody(body string) string {
+	normalized := redact.JSON([]byte(body), r.ignoredPaths)
+
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(normalized))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return strings.TrimSpace(body)
+	}
+
+	canonical, err := json.Marshal(doc)
+	if err != nil {
+		return strings.TrimSpace(body)
+	}
+	return string(canonical)
+}
+
+func (r *Recorder) scrubHeaders(header http.Header) map[string][]string {
+	if len(header) == 0 {
+		return nil
+	}
+
+	scrubbed := redact.Headers(header, r.redactedHeaders)
+	for name, values := range scrubbed {
+		for i, value := range values {
+			values[i] = redact.Emails(value)
+		}
+		scrubbed[name] = values
+	}
+	return scrubbed
+}
+
+func (r *Recorder) scrubBody(path string, body []byte) string {
+	paths := redact.PathsFor(path, r.redactedPaths, redact.DefaultEndpointJSONPaths)
+	return redact.Emails(string(redact.JSON(body, paths)))
+}
+
+// drainBody reads a body and replaces it with an equivalent unread one.
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,161 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/apikeys"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/policies"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/transport"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/rbac/apikey/create":
+			w.Write([]byte(`{"apiKey":"gc-live-secret","id":"key-1"}`))
+		case "/api/rbac/policy/p-1":
+			w.Write([]byte(`{"uuid":"p-1","name":"admins","revisionNumber":1,"createdBy":"jane@example.com"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "apikeys.yaml")
+	ctx := context.Background()
+	name := "ci"
+
+	// Record
+	rec, err := New(path, ModeRecord, WithIgnoredBodyPaths("description"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	sdkClient, err := transport.NewSDKClient("secret-token", "backend-1", server.URL, transport.WithTransportWrapper(rec.Wrap))
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	created, err := sdkClient.Apikeys.CreateAPIKey(apikeys.NewCreateAPIKeyParamsWithContext(ctx).WithBody(&models.CreateAPIKeyRequest{Name: &name, Description: "first run"}), nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	if created.Payload.APIKey != "gc-live-secret" {
+		t.Fatalf("expected the live key while recording, got %q", created.Payload.APIKey)
+	}
+	if _, err := sdkClient.Policies.GetPolicy(policies.NewGetPolicyParamsWithContext(ctx).WithID("p-1"), nil); err != nil {
+		t.Fatalf("GetPolicy failed: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the cassette to be written: %v", err)
+	}
+	for _, secret := range []string{"gc-live-secret", "secret-token", "jane@example.com"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("expected %q to be scrubbed from the cassette:\n%s", secret, data)
+		}
+	}
+
+	// Replay against an address nothing listens on
+	replayer, err := New(path, ModeReplay, WithIgnoredBodyPaths("description"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	replayClient, err := transport.NewSDKClient("other-token", "backend-1", "http://127.0.0.1:1", transport.WithTransportWrapper(replayer.Wrap))
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	replayed, err := replayClient.Apikeys.CreateAPIKey(apikeys.NewCreateAPIKeyParamsWithContext(ctx).WithBody(&models.CreateAPIKeyRequest{Name: &name, Description: "second run"}), nil)
+	if err != nil {
+		t.Fatalf("replayed CreateAPIKey failed: %v", err)
+	}
+	if replayed.Payload.ID != "key-1" {
+		t.Errorf("expected the recorded ID, got %q", replayed.Payload.ID)
+	}
+
+	policy, err := replayClient.Policies.GetPolicy(policies.NewGetPolicyParamsWithContext(ctx).WithID("p-1"), nil)
+	if err != nil {
+		t.Fatalf("replayed GetPolicy failed: %v", err)
+	}
+	if policy.Payload.Name == nil || *policy.Payload.Name != "admins" {
+		t.Errorf("expected the recorded policy name, got %v", policy.Payload.Name)
+	}
+
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("expected all interactions to be replayed, %d unused", len(unused))
+	}
+
+	// Each interaction is served once, so a repeated call fails loudly.
+	_, err = replayClient.Policies.GetPolicy(policies.NewGetPolicyParamsWithContext(ctx).WithID("p-1"), nil)
+	var unmatched *UnmatchedRequestError
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("expected *UnmatchedRequestError, got %v", err)
+	}
+	if unmatched.Path != "/api/rbac/policy/p-1" {
+		t.Errorf("expected the unmatched path to be reported, got %q", unmatched.Path)
+	}
+	if len(replayer.Unmatched()) != 1 {
+		t.Errorf("expected 1 unmatched request, got %d", len(replayer.Unmatched()))
+	}
+}
+
+func TestReplay_BodyMatching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.yaml")
+	cassette := `interactions:
+- request:
+    method: POST
+    path: /api/logs/v2/search
+    body: '{"Query":"level:error","End":"2024-01-01T00:00:00Z"}'
+  response:
+    status: 200
+    headers:
+      Content-Type: [application/json]
+    body: '[{"count()":3}]'
+`
+	if err := os.WriteFile(path, []byte(cassette), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		options []Option
+		body    string
+		matched bool
+	}{
+		{"reordered keys", nil, `{ "End":"2024-01-01T00:00:00Z", "Query":"level:error" }`, true},
+		{"different query", nil, `{"Query":"level:info","End":"2024-01-01T00:00:00Z"}`, false},
+		{"ignored path", []Option{WithIgnoredBodyPaths("End")}, `{"Query":"level:error","End":"2025-06-01T00:00:00Z"}`, true},
+		{"body not matched", []Option{WithMatch(MatchMethod | MatchPath)}, `{"Query":"anything"}`, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, err := New(path, ModeReplay, tc.options...)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "http://api.example.com/api/logs/v2/search", strings.NewReader(tc.body))
+			resp, err := rec.Wrap(nil).RoundTrip(req)
+			if tc.matched != (err == nil) {
+				t.Fatalf("expected matched=%v, got error %v", tc.matched, err)
+			}
+			if tc.matched && resp.StatusCode != http.StatusOK {
+				t.Errorf("expected status 200, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
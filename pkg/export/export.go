@@ -0,0 +1,360 @@
+// Package export writes search results to files for offline analysis.
+//
+// Write streams rows from a search iterator, or from a []interface{} search
+// payload adapted with Rows, to NDJSON, CSV or Parquet:
+//
+//	result, err := gc.Logs.Search(ctx, request)
+//	...
+//	manifest, err := export.Write(ctx, "checkout-logs.ndjson.zst", export.FormatNDJSON, export.Rows(result),
+//		export.WithCompression(export.CompressionZstd),
+//		export.WithQuery(request.Query),
+//		export.WithTimeRange(time.Time(*request.Start), time.Time(*request.End)))
+//
+// Files are written atomically: rows go to a temporary file in the target
+// directory which is renamed into place once complete. A manifest recording
+// the query, time range and row count is then written next to it, at
+// ManifestPath(path).
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Format is the file format of an export.
+type Format string
+
+// Supported Formats.
+const (
+	FormatNDJSON  Format = "ndjson"
+	FormatCSV     Format = "csv"
+	FormatParquet Format = "parquet"
+)
+
+// Compression is the compression of an export. Parquet files are compressed
+// per page with the codec; other formats are compressed as a whole.
+type Compression string
+
+// Supported Compressions.
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+// DefaultSampleSize is the number of rows scanned to detect CSV columns and
+// the Parquet schema.
+const DefaultSampleSize = 1000
+
+// Manifest describes a completed export.
+type Manifest struct {
+	File        string      `json:"file"`
+	Format      Format      `json:"format"`
+	Compression Compression `json:"compression,omitempty"`
+	Query       string      `json:"query,omitempty"`
+	Start       *time.Time  `json:"start,omitempty"`
+	End         *time.Time  `json:"end,omitempty"`
+	Rows        int64       `json:"rows"`
+	Columns     []string    `json:"columns,omitempty"`
+	CreatedAt   time.Time   `json:"createdAt"`
+}
+
+// Option customizes an export.
+type Option func(*config)
+
+type config struct {
+	compression Compression
+	columns     []string
+	sampleSize  int
+	query       string
+	start, end  *time.Time
+	now         func() time.Time
+}
+
+// WithCompression compresses the export. Defaults to CompressionNone.
+func WithCompression(compression Compression) Option {
+	return func(c *config) {
+		c.compression = compression
+	}
+}
+
+// WithColumns sets the CSV and Parquet columns, in order. By default the
+// columns are the sorted keys of the first rows, see WithSampleSize.
+// Parquet columns are always sorted by name. Row fields outside the columns
+// are not exported.
+func WithColumns(columns ...string) Option {
+	return func(c *config) {
+		c.columns = columns
+	}
+}
+
+// WithSampleSize sets the number of rows scanned to detect columns and
+// Parquet column types. Defaults to DefaultSampleSize.
+func WithSampleSize(size int) Option {
+	return func(c *config) {
+		c.sampleSize = size
+	}
+}
+
+// WithQuery records the search query in the manifest.
+func WithQuery(query string) Option {
+	return func(c *config) {
+		c.query = query
+	}
+}
+
+// WithTimeRange records the search time range in the manifest.
+func WithTimeRange(start, end time.Time) Option {
+	return func(c *config) {
+		start, end = start.UTC(), end.UTC()
+		c.start, c.end = &start, &end
+	}
+}
+
+// ManifestPath returns the path of the manifest of the export at path.
+func ManifestPath(path string) string {
+	return path + ".manifest.json"
+}
+
+// Rows adapts a search payload, a list of objects, to the row iterator
+// taken by Write.
+func Rows(payload interface{}) iter.Seq2[map[string]interface{}, error] {
+	return func(yield func(map[string]interface{}, error) bool) {
+		var items []interface{}
+		switch v := payload.(type) {
+		case nil:
+			return
+		case []interface{}:
+			items = v
+		case []map[string]interface{}:
+			for _, row := range v {
+				if !yield(row, nil) {
+					return
+				}
+			}
+			return
+		default:
+			yield(nil, fmt.Errorf("unexpected search payload of type %T", payload))
+			return
+		}
+
+		for i, item := range items {
+			row, ok := item.(map[string]interface{})
+			if !ok {
+				yield(nil, fmt.Errorf("row %d: unexpected value of type %T", i, item))
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+// rowWriter encodes rows in one format.
+type rowWriter interface {
+	writeRow(row map[string]interface{}) error
+	close() error
+}
+
+// Write exports rows to path in format and writes its manifest. Nothing is
+// left at path or at the manifest path when the export fails.
+func Write(ctx context.Context, path string, format Format, rows iter.Seq2[map[string]interface{}, error], options ...Option) (*Manifest, error) {
+	c := &config{sampleSize: DefaultSampleSize, now: time.Now}
+	for _, option := range options {
+		option(c)
+	}
+
+	switch format {
+	case FormatNDJSON, FormatCSV, FormatParquet:
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+	switch c.compression {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+	default:
+		return nil, fmt.Errorf("unsupported export compression %q", c.compression)
+	}
+
+	manifest := &Manifest{
+		File:        filepath.Base(path),
+		Format:      format,
+		Compression: c.compression,
+		Query:       c.query,
+		Start:       c.start,
+		End:         c.end,
+	}
+
+	err := writeAtomic(path, func(file io.Writer) error {
+		return c.export(ctx, file, format, rows, manifest)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.CreatedAt = c.now().UTC()
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("error encoding manifest: %w", err)
+	}
+	err = writeAtomic(ManifestPath(path), func(file io.Writer) error {
+		_, err := file.Write(append(data, '\n'))
+		return err
+	})
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// export streams rows to file. Rows are buffered until the sample is
+// complete, since the CSV header and Parquet schema come first.
+func (c *config) export(ctx context.Context, file io.Writer, format Format, rows iter.Seq2[map[string]interface{}, error], manifest *Manifest) (err error) {
+	out, err := compressWriter(file, format, c.compression)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	var sample []map[string]interface{}
+	var writer rowWriter
+	start := func() error {
+		w, err := c.newRowWriter(out, format, sample)
+		if err != nil {
+			return err
+		}
+		writer = w
+		manifest.Columns = columnsOf(writer)
+		for _, row := range sample {
+			if err := writer.writeRow(row); err != nil {
+				return fmt.Errorf("row %d: %w", manifest.Rows, err)
+			}
+			manifest.Rows++
+		}
+		sample = nil
+		return nil
+	}
+
+	for row, err := range rows {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if writer == nil {
+			sample = append(sample, row)
+			if len(sample) < c.sampleSize {
+				continue
+			}
+			if err := start(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := writer.writeRow(row); err != nil {
+			return fmt.Errorf("row %d: %w", manifest.Rows, err)
+		}
+		manifest.Rows++
+	}
+
+	if writer == nil {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	return writer.close()
+}
+
+func (c *config) newRowWriter(w io.Writer, format Format, sample []map[string]interface{}) (rowWriter, error) {
+	columns := c.columns
+	if len(columns) == 0 && format != FormatNDJSON {
+		columns = detectColumns(sample)
+	}
+
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatParquet:
+		return newParquetWriter(w, columns, sample, c.compression)
+	default:
+		return newNDJSONWriter(w), nil
+	}
+}
+
+// detectColumns returns the sorted keys of the sampled rows.
+func detectColumns(sample []map[string]interface{}) []string {
+	seen := map[string]bool{}
+	for _, row := range sample {
+		for key := range row {
+			seen[key] = true
+		}
+	}
+	columns := make([]string, 0, len(seen))
+	for key := range seen {
+		columns = append(columns, key)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func columnsOf(writer rowWriter) []string {
+	if w, ok := writer.(interface{ columnNames() []string }); ok {
+		return w.columnNames()
+	}
+	return nil
+}
+
+// writeAtomic writes path through a temporary file in the same directory
+// that is renamed into place only when write succeeds.
+func writeAtomic(path string, write func(io.Writer) error) (err error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating export file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+var errUnsupported = errors.New("unsupported value")
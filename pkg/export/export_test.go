@@ -0,0 +1,311 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/parquet-go/parquet-go"
+)
+
+func testPayload() []interface{} {
+	return []interface{}{
+		map[string]interface{}{"timestamp": "2024-01-01T00:00:01Z", "content": "started", "level": "info", "latency": float64(12)},
+		map[string]interface{}{"timestamp": "2024-01-01T00:00:02Z", "content": "failed, retrying", "level": "error", "retry": true},
+		map[string]interface{}{"timestamp": "2024-01-01T00:00:03Z", "content": "done", "attributes": map[string]interface{}{"pod": "checkout-1"}},
+	}
+}
+
+func readManifest(t *testing.T, path string) *Manifest {
+	t.Helper()
+	data, err := os.ReadFile(ManifestPath(path))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		t.Fatalf("Failed to decode manifest: %v", err)
+	}
+	return manifest
+}
+
+func TestWriteNDJSONGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.ndjson.gz")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	manifest, err := Write(context.Background(), path, FormatNDJSON, Rows(testPayload()),
+		WithCompression(CompressionGzip),
+		WithQuery("level:error"),
+		WithTimeRange(start, end))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if manifest.Rows != 3 {
+		t.Errorf("Expected 3 rows, got %d", manifest.Rows)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Expected gzip file: %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[2]), &row); err != nil {
+		t.Fatalf("Invalid NDJSON line %q: %v", lines[2], err)
+	}
+	if !reflect.DeepEqual(row, testPayload()[2]) {
+		t.Errorf("Unexpected row %v", row)
+	}
+
+	written := readManifest(t, path)
+	if written.File != "logs.ndjson.gz" || written.Format != FormatNDJSON || written.Compression != CompressionGzip {
+		t.Errorf("Unexpected manifest %+v", written)
+	}
+	if written.Query != "level:error" || written.Rows != 3 {
+		t.Errorf("Unexpected manifest query or rows %+v", written)
+	}
+	if written.Start == nil || !written.Start.Equal(start) || written.End == nil || !written.End.Equal(end) {
+		t.Errorf("Unexpected manifest time range %v - %v", written.Start, written.End)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("detected columns", func(t *testing.T) {
+		path := filepath.Join(dir, "detected.csv")
+		manifest, err := Write(context.Background(), path, FormatCSV, Rows(testPayload()), WithSampleSize(2))
+		if err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+
+		records := readCSV(t, path)
+		wantHeader := []string{"content", "latency", "level", "retry", "timestamp"}
+		if !reflect.DeepEqual(records[0], wantHeader) || !reflect.DeepEqual(manifest.Columns, wantHeader) {
+			t.Errorf("Expected header %v, got %v (manifest %v)", wantHeader, records[0], manifest.Columns)
+		}
+		if got := records[2]; got[0] != "failed, retrying" || got[3] != "true" {
+			t.Errorf("Unexpected record %v", got)
+		}
+		// attributes appears after the sample, so it is not a column.
+		if len(records) != 4 || len(records[3]) != len(wantHeader) {
+			t.Errorf("Unexpected records %v", records)
+		}
+	})
+
+	t.Run("chosen columns", func(t *testing.T) {
+		path := filepath.Join(dir, "chosen.csv.zst")
+		_, err := Write(context.Background(), path, FormatCSV, Rows(testPayload()),
+			WithColumns("timestamp", "attributes"),
+			WithCompression(CompressionZstd))
+		if err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+
+		compressed, _ := os.ReadFile(path)
+		decoder, _ := zstd.NewReader(nil)
+		data, err := decoder.DecodeAll(compressed, nil)
+		if err != nil {
+			t.Fatalf("Expected zstd file: %v", err)
+		}
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			t.Fatalf("Invalid CSV: %v", err)
+		}
+		want := [][]string{
+			{"timestamp", "attributes"},
+			{"2024-01-01T00:00:01Z", ""},
+			{"2024-01-01T00:00:02Z", ""},
+			{"2024-01-01T00:00:03Z", `{"pod":"checkout-1"}`},
+		}
+		if !reflect.DeepEqual(records, want) {
+			t.Errorf("Expected %v, got %v", want, records)
+		}
+	})
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	return records
+}
+
+func TestWriteParquet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.parquet")
+	manifest, err := Write(context.Background(), path, FormatParquet, Rows(testPayload()), WithCompression(CompressionZstd))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if manifest.Rows != 3 {
+		t.Errorf("Expected 3 rows, got %d", manifest.Rows)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	info, _ := file.Stat()
+	pf, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		t.Fatalf("Invalid Parquet file: %v", err)
+	}
+	if pf.NumRows() != 3 {
+		t.Errorf("Expected 3 Parquet rows, got %d", pf.NumRows())
+	}
+
+	kinds := map[string]parquet.Kind{}
+	for _, field := range pf.Schema().Fields() {
+		kinds[field.Name()] = field.Type().Kind()
+	}
+	want := map[string]parquet.Kind{
+		"attributes": parquet.ByteArray,
+		"content":    parquet.ByteArray,
+		"latency":    parquet.Double,
+		"level":      parquet.ByteArray,
+		"retry":      parquet.Boolean,
+		"timestamp":  parquet.ByteArray,
+	}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("Expected schema %v, got %v", want, kinds)
+	}
+
+	rows := make([]parquet.Row, 3)
+	reader := parquet.NewReader(pf)
+	n, err := reader.ReadRows(rows)
+	if err != nil && !errors.Is(err, io.EOF) {
+		t.Fatalf("Failed to read rows: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("Expected to read 3 rows, got %d", n)
+	}
+	// Columns are sorted: attributes, content, latency, level, retry, timestamp.
+	if got := rows[0][2].Double(); got != 12 {
+		t.Errorf("Expected latency 12, got %v", got)
+	}
+	if !rows[1][2].IsNull() || !rows[1][4].Boolean() {
+		t.Errorf("Unexpected second row %v", rows[1])
+	}
+	if got := rows[2][0].String(); got != `{"pod":"checkout-1"}` {
+		t.Errorf("Expected JSON-encoded attributes, got %q", got)
+	}
+}
+
+func TestWriteParquetIntegers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.parquet")
+	payload := []interface{}{
+		map[string]interface{}{"timestamp": json.Number("1704067200123456789"), "count": json.Number("3"), "ratio": json.Number("0.5")},
+		map[string]interface{}{"timestamp": json.Number("1704067200987654321"), "count": int64(4), "ratio": json.Number("2")},
+	}
+	if _, err := Write(context.Background(), path, FormatParquet, Rows(payload)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	info, _ := file.Stat()
+	pf, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		t.Fatalf("Invalid Parquet file: %v", err)
+	}
+	kinds := map[string]parquet.Kind{}
+	for _, field := range pf.Schema().Fields() {
+		kinds[field.Name()] = field.Type().Kind()
+	}
+	want := map[string]parquet.Kind{"count": parquet.Int64, "ratio": parquet.Double, "timestamp": parquet.Int64}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("Expected schema %v, got %v", want, kinds)
+	}
+
+	rows := make([]parquet.Row, 2)
+	if n, err := parquet.NewReader(pf).ReadRows(rows); n != 2 || (err != nil && !errors.Is(err, io.EOF)) {
+		t.Fatalf("Expected to read 2 rows, got %d: %v", n, err)
+	}
+	// Columns are sorted: count, ratio, timestamp.
+	if got := rows[0][2].Int64(); got != 1704067200123456789 {
+		t.Errorf("Expected the exact timestamp, got %d", got)
+	}
+	if got := rows[1][0].Int64(); got != 4 {
+		t.Errorf("Expected count 4, got %d", got)
+	}
+}
+
+func TestWriteFailureLeavesNothing(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs.ndjson")
+	iterErr := errors.New("search failed")
+
+	rows := func(yield func(map[string]interface{}, error) bool) {
+		if !yield(map[string]interface{}{"content": "first"}, nil) {
+			return
+		}
+		yield(nil, iterErr)
+	}
+
+	if _, err := Write(context.Background(), path, FormatNDJSON, rows); !errors.Is(err, iterErr) {
+		t.Fatalf("Expected iterator error, got %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("Expected no files after a failed export, got %v", entries)
+	}
+
+	// A failed export keeps a previous export intact.
+	if err := os.WriteFile(path, []byte("previous\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Write(ctx, path, FormatNDJSON, Rows(testPayload())); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context error, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "previous\n" {
+		t.Errorf("Expected previous export to be kept, got %q", data)
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.csv")
+	manifest, err := Write(context.Background(), path, FormatCSV, Rows(nil), WithColumns("timestamp"))
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if manifest.Rows != 0 {
+		t.Errorf("Expected 0 rows, got %d", manifest.Rows)
+	}
+	if records := readCSV(t, path); !reflect.DeepEqual(records, [][]string{{"timestamp"}}) {
+		t.Errorf("Expected header only, got %v", records)
+	}
+}
@@ -0,0 +1,287 @@
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/parquet-go/parquet-go"
+)
+
+// compressWriter wraps w in the compression of a non-Parquet export.
+func compressWriter(w io.Writer, format Format, compression Compression) (io.WriteCloser, error) {
+	if format == FormatParquet {
+		return nopCloser{w}, nil
+	}
+	switch compression {
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		encoder, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("error creating zstd writer: %w", err)
+		}
+		return encoder, nil
+	default:
+		return nopCloser{w}, nil
+	}
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// ndjsonWriter writes one JSON object per line.
+type ndjsonWriter struct {
+	buf     *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	buf := bufio.NewWriter(w)
+	return &ndjsonWriter{buf: buf, encoder: json.NewEncoder(buf)}
+}
+
+func (w *ndjsonWriter) writeRow(row map[string]interface{}) error {
+	return w.encoder.Encode(row)
+}
+
+func (w *ndjsonWriter) close() error {
+	return w.buf.Flush()
+}
+
+// csvWriter writes a header and one record per row. Nested values are
+// JSON-encoded.
+type csvWriter struct {
+	writer  *csv.Writer
+	columns []string
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer, columns: columns, record: make([]string, len(columns))}, nil
+}
+
+func (w *csvWriter) writeRow(row map[string]interface{}) error {
+	for i, column := range w.columns {
+		cell, err := formatValue(row[column])
+		if err != nil {
+			return fmt.Errorf("column %q: %w", column, err)
+		}
+		w.record[i] = cell
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) columnNames() []string {
+	return w.columns
+}
+
+// formatValue renders a JSON value as a CSV cell or Parquet string.
+func formatValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("%w of type %T", errUnsupported, value)
+		}
+		return string(data), nil
+	}
+}
+
+// columnType is the inferred Parquet type of a column.
+type columnType int
+
+const (
+	columnString columnType = iota
+	columnInt64
+	columnDouble
+	columnBoolean
+)
+
+// inferTypes types each column by its sampled values: all integers give an
+// INT64 column, all numbers a DOUBLE one, all booleans a BOOLEAN one and
+// anything else a STRING one. Only json.Number and Go integers count as
+// integers, since a float64 may already have lost precision.
+func inferTypes(columns []string, sample []map[string]interface{}) []columnType {
+	types := make([]columnType, len(columns))
+	for i, column := range columns {
+		integers, numbers, booleans, others := 0, 0, 0, 0
+		for _, row := range sample {
+			switch v := row[column].(type) {
+			case nil:
+			case json.Number:
+				if _, err := v.Int64(); err == nil {
+					integers++
+				}
+				numbers++
+			case int, int64:
+				integers++
+				numbers++
+			case float64:
+				numbers++
+			case bool:
+				booleans++
+			default:
+				others++
+			}
+		}
+		switch {
+		case others == 0 && booleans == 0 && numbers > 0 && integers == numbers:
+			types[i] = columnInt64
+		case others == 0 && booleans == 0 && numbers > 0:
+			types[i] = columnDouble
+		case others == 0 && numbers == 0 && booleans > 0:
+			types[i] = columnBoolean
+		default:
+			types[i] = columnString
+		}
+	}
+	return types
+}
+
+// parquetWriter writes rows with a schema of optional columns inferred from
+// the sample.
+type parquetWriter struct {
+	writer  *parquet.Writer
+	columns []string
+	types   []columnType
+	row     parquet.Row
+}
+
+func newParquetWriter(w io.Writer, columns []string, sample []map[string]interface{}, compression Compression) (*parquetWriter, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns to export: set WithColumns or export at least one row")
+	}
+
+	// Parquet orders the fields of a group by name; keep the columns aligned.
+	sorted := append([]string{}, columns...)
+	sort.Strings(sorted)
+	types := inferTypes(sorted, sample)
+
+	group := parquet.Group{}
+	for i, column := range sorted {
+		switch types[i] {
+		case columnInt64:
+			group[column] = parquet.Optional(parquet.Leaf(parquet.Int64Type))
+		case columnDouble:
+			group[column] = parquet.Optional(parquet.Leaf(parquet.DoubleType))
+		case columnBoolean:
+			group[column] = parquet.Optional(parquet.Leaf(parquet.BooleanType))
+		default:
+			group[column] = parquet.Optional(parquet.String())
+		}
+	}
+
+	options := []parquet.WriterOption{parquet.NewSchema("export", group)}
+	switch compression {
+	case CompressionGzip:
+		options = append(options, parquet.Compression(&parquet.Gzip))
+	case CompressionZstd:
+		options = append(options, parquet.Compression(&parquet.Zstd))
+	}
+
+	return &parquetWriter{
+		writer:  parquet.NewWriter(w, options...),
+		columns: sorted,
+		types:   types,
+		row:     make(parquet.Row, len(sorted)),
+	}, nil
+}
+
+func (w *parquetWriter) writeRow(row map[string]interface{}) error {
+	for i, column := range w.columns {
+		value, err := w.value(w.types[i], row[column])
+		if err != nil {
+			return fmt.Errorf("column %q: %w", column, err)
+		}
+		w.row[i] = value.Level(0, 1, i)
+		if value.IsNull() {
+			w.row[i] = value.Level(0, 0, i)
+		}
+	}
+	_, err := w.writer.WriteRows([]parquet.Row{w.row})
+	return err
+}
+
+func (w *parquetWriter) value(typ columnType, value interface{}) (parquet.Value, error) {
+	if value == nil {
+		return parquet.NullValue(), nil
+	}
+	switch typ {
+	case columnInt64:
+		switch v := value.(type) {
+		case int:
+			return parquet.Int64Value(int64(v)), nil
+		case int64:
+			return parquet.Int64Value(v), nil
+		case json.Number:
+			if i, err := v.Int64(); err == nil {
+				return parquet.Int64Value(i), nil
+			}
+		}
+		return parquet.Value{}, fmt.Errorf("%w %v of type %T in an INT64 column, increase the sample size", errUnsupported, value, value)
+	case columnDouble:
+		switch v := value.(type) {
+		case float64:
+			return parquet.DoubleValue(v), nil
+		case int:
+			return parquet.DoubleValue(float64(v)), nil
+		case int64:
+			return parquet.DoubleValue(float64(v)), nil
+		case json.Number:
+			f, err := v.Float64()
+			if err != nil {
+				return parquet.Value{}, err
+			}
+			return parquet.DoubleValue(f), nil
+		}
+		return parquet.Value{}, fmt.Errorf("%w of type %T in a DOUBLE column, increase the sample size", errUnsupported, value)
+	case columnBoolean:
+		if v, ok := value.(bool); ok {
+			return parquet.BooleanValue(v), nil
+		}
+		return parquet.Value{}, fmt.Errorf("%w of type %T in a BOOLEAN column, increase the sample size", errUnsupported, value)
+	default:
+		s, err := formatValue(value)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+		return parquet.ByteArrayValue([]byte(s)), nil
+	}
+}
+
+func (w *parquetWriter) close() error {
+	return w.writer.Close()
+}
+
+func (w *parquetWriter) columnNames() []string {
+	return w.columns
+}
@@ -0,0 +1,60 @@
+// Code generated by fakegen. DO NOT EDIT.
+
+package fakes
+
+import (
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client"
+)
+
+// Fakes holds the service fakes behind a fake client.GroundcoverAPI.
+type Fakes struct {
+	Apikeys         *Apikeys
+	Events          *Events
+	Ingestionkeys   *Ingestionkeys
+	K8s             *K8s
+	Logs            *Logs
+	LogsPipeline    *LogsPipeline
+	Metrics         *Metrics
+	Monitors        *Monitors
+	Policies        *Policies
+	Search          *Search
+	Serviceaccounts *Serviceaccounts
+	Traces          *Traces
+	Workflows       *Workflows
+}
+
+// NewFakeGroundcoverAPI returns a client.GroundcoverAPI whose services are
+// all fakes, together with the fakes to program and inspect them.
+func NewFakeGroundcoverAPI() (*client.GroundcoverAPI, *Fakes) {
+	f := &Fakes{
+		Apikeys:         NewApikeys(),
+		Events:          NewEvents(),
+		Ingestionkeys:   NewIngestionkeys(),
+		K8s:             NewK8s(),
+		Logs:            NewLogs(),
+		LogsPipeline:    NewLogsPipeline(),
+		Metrics:         NewMetrics(),
+		Monitors:        NewMonitors(),
+		Policies:        NewPolicies(),
+		Search:          NewSearch(),
+		Serviceaccounts: NewServiceaccounts(),
+		Traces:          NewTraces(),
+		Workflows:       NewWorkflows(),
+	}
+
+	return &client.GroundcoverAPI{
+		Apikeys:         f.Apikeys.Client(),
+		Events:          f.Events.Client(),
+		Ingestionkeys:   f.Ingestionkeys.Client(),
+		K8s:             f.K8s.Client(),
+		Logs:            f.Logs.Client(),
+		LogsPipeline:    f.LogsPipeline.Client(),
+		Metrics:         f.Metrics.Client(),
+		Monitors:        f.Monitors.Client(),
+		Policies:        f.Policies.Client(),
+		Search:          f.Search.Client(),
+		Serviceaccounts: f.Serviceaccounts.Client(),
+		Traces:          f.Traces.Client(),
+		Workflows:       f.Workflows.Client(),
+	}, f
+}
@@ -0,0 +1,182 @@
+// Package fakes provides programmable test doubles for every ClientService
+// of the generated API clients.
+//
+//	api, fake := fakes.NewFakeGroundcoverAPI()
+//	fake.Monitors.GetMonitor.Returns(&monitors.GetMonitorOK{Payload: []byte("title: High CPU\n")}, nil)
+//	fake.Policies.DeletePolicy.FailsWithStatus(http.StatusNotFound, "policy not found")
+//
+//	runCodeUnderTest(api)
+//
+//	fake.Monitors.GetMonitor.AssertCalledWith(t, func(p *monitors.GetMonitorParams) bool { return p.ID == "m-1" })
+//
+// Each fake service has one *Method per operation. Unstubbed operations fail
+// with ErrNotStubbed. The service fakes are generated from the client
+// packages; run go generate after regenerating pkg/client.
+package fakes
+
+//go:generate go run ../../internal/fakegen -client ../client -out .
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/apierrors"
+)
+
+// ErrNotStubbed is returned by operations that were called without a programmed response.
+var ErrNotStubbed = errors.New("fakes: operation not stubbed")
+
+// TB is the subset of testing.TB used by the assertion helpers.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+type stubbedResult[R any] struct {
+	result R
+	err    error
+}
+
+// Method is the fake of a single operation taking params of type P and
+// returning R. Responses are chosen in this order: ReturnsOnCall, Handle,
+// Returns. It is safe for concurrent use.
+type Method[P any, R any] struct {
+	operation string // e.g. "monitors.GetMonitor"
+	method    string
+	path      string
+
+	mu      sync.Mutex
+	calls   []P
+	onCall  map[int]stubbedResult[R]
+	handler func(P) (R, error)
+	result  *stubbedResult[R]
+}
+
+func newMethod[P any, R any](operation, method, path string) *Method[P, R] {
+	return &Method[P, R]{operation: operation, method: method, path: path}
+}
+
+// Returns makes every call return result and err.
+func (m *Method[P, R]) Returns(result R, err error) *Method[P, R] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.result = &stubbedResult[R]{result: result, err: err}
+	return m
+}
+
+// ReturnsOnCall makes the call with the given 0-based index return result and err.
+func (m *Method[P, R]) ReturnsOnCall(call int, result R, err error) *Method[P, R] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.onCall == nil {
+		m.onCall = make(map[int]stubbedResult[R])
+	}
+	m.onCall[call] = stubbedResult[R]{result: result, err: err}
+	return m
+}
+
+// Fails makes every call return err.
+func (m *Method[P, R]) Fails(err error) *Method[P, R] {
+	var zero R
+	return m.Returns(zero, err)
+}
+
+// FailsWithStatus makes every call return an *apierrors.APIError with the
+// given status, as a client created with transport.WithUnifiedErrors would.
+func (m *Method[P, R]) FailsWithStatus(status int, message string) *Method[P, R] {
+	return m.Fails(&apierrors.APIError{
+		StatusCode: status,
+		Operation:  m.operation,
+		Method:     m.method,
+		Path:       m.path,
+		Message:    message,
+	})
+}
+
+// Handle computes the response of each call from its params.
+func (m *Method[P, R]) Handle(handler func(params P) (R, error)) *Method[P, R] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handler = handler
+	return m
+}
+
+// CallCount returns the number of calls made so far.
+func (m *Method[P, R]) CallCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.calls)
+}
+
+// Params returns the params of the call with the given 0-based index.
+func (m *Method[P, R]) Params(call int) P {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[call]
+}
+
+// Calls returns the params of every call made so far.
+func (m *Method[P, R]) Calls() []P {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]P(nil), m.calls...)
+}
+
+// Reset forgets the recorded calls and programmed responses.
+func (m *Method[P, R]) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+	m.onCall = nil
+	m.handler = nil
+	m.result = nil
+}
+
+// AssertCallCount reports an error unless the operation was called exactly want times.
+func (m *Method[P, R]) AssertCallCount(t TB, want int) {
+	t.Helper()
+	if got := m.CallCount(); got != want {
+		t.Errorf("%s: expected %d calls, got %d", m.operation, want, got)
+	}
+}
+
+// AssertCalledWith reports an error unless at least one call's params satisfy match.
+func (m *Method[P, R]) AssertCalledWith(t TB, match func(params P) bool) {
+	t.Helper()
+	calls := m.Calls()
+	for _, params := range calls {
+		if match(params) {
+			return
+		}
+	}
+	t.Errorf("%s: none of the %d calls matched", m.operation, len(calls))
+}
+
+// AssertNotCalled reports an error if the operation was called.
+func (m *Method[P, R]) AssertNotCalled(t TB) {
+	t.Helper()
+	m.AssertCallCount(t, 0)
+}
+
+// call records params and returns the programmed response.
+func (m *Method[P, R]) call(params P) (R, error) {
+	m.mu.Lock()
+	index := len(m.calls)
+	m.calls = append(m.calls, params)
+	onCall, hasOnCall := m.onCall[index]
+	handler, result := m.handler, m.result
+	m.mu.Unlock()
+
+	switch {
+	case hasOnCall:
+		return onCall.result, onCall.err
+	case handler != nil:
+		return handler(params)
+	case result != nil:
+		return result.result, result.err
+	default:
+		var zero R
+		return zero, fmt.Errorf("%w: %s", ErrNotStubbed, m.operation)
+	}
+}
@@ -0,0 +1,104 @@
+package fakes_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/apierrors"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/logs_pipeline"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/monitors"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/fakes"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/groundcover"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+type recordingTB struct {
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, format)
+}
+
+func TestFakeGroundcoverAPI(t *testing.T) {
+	api, fake := fakes.NewFakeGroundcoverAPI()
+	fake.Monitors.GetMonitor.Returns(&monitors.GetMonitorOK{Payload: []byte("title: High CPU\n")}, nil)
+	fake.Policies.DeletePolicy.FailsWithStatus(http.StatusNotFound, "policy not found")
+
+	gc := groundcover.NewFromAPI(api)
+
+	monitor, err := gc.Monitors.Get(context.Background(), "m-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if *monitor.Title != "High CPU" {
+		t.Errorf("unexpected title %s", *monitor.Title)
+	}
+	fake.Monitors.GetMonitor.AssertCallCount(t, 1)
+	fake.Monitors.GetMonitor.AssertCalledWith(t, func(params *monitors.GetMonitorParams) bool {
+		return params.ID == "m-1"
+	})
+
+	err = gc.Policies.Delete(context.Background(), "p-1")
+	if !errors.Is(err, apierrors.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	apiErr, _ := apierrors.As(err)
+	if apiErr.Operation != "policies.DeletePolicy" || apiErr.Method != http.MethodDelete || apiErr.Path != "/api/rbac/policy/{id}" {
+		t.Errorf("unexpected error details: %+v", apiErr)
+	}
+
+	if _, err := gc.Silences.List(context.Background(), groundcover.SilenceListOptions{}); !errors.Is(err, fakes.ErrNotStubbed) {
+		t.Errorf("expected ErrNotStubbed for an unprogrammed operation, got %v", err)
+	}
+}
+
+func TestMethodResponses(t *testing.T) {
+	fake := fakes.NewMonitors()
+	client := fake.Client()
+
+	fake.DeleteMonitor.
+		Returns(&monitors.DeleteMonitorOK{}, nil).
+		ReturnsOnCall(1, nil, errors.New("boom"))
+
+	for i, wantErr := range []bool{false, true, false} {
+		_, err := client.DeleteMonitor(monitors.NewDeleteMonitorParams().WithID("m"), nil)
+		if (err != nil) != wantErr {
+			t.Errorf("call %d: expected error %v, got %v", i, wantErr, err)
+		}
+	}
+
+	fake.ListMonitors.Handle(func(params *monitors.ListMonitorsParams) (*monitors.ListMonitorsOK, error) {
+		return &monitors.ListMonitorsOK{Payload: &models.MonitorListResponse{
+			Monitors: []*models.MonitorListItem{{Title: "filtered"}},
+		}}, nil
+	})
+	resp, err := client.ListMonitors(monitors.NewListMonitorsParams(), nil)
+	if err != nil || resp.Payload.Monitors[0].Title != "filtered" {
+		t.Errorf("unexpected handler response: %+v, %v", resp, err)
+	}
+
+	fake.DeleteMonitor.Reset()
+	fake.DeleteMonitor.AssertNotCalled(t)
+
+	tb := &recordingTB{}
+	fake.ListMonitors.AssertCallCount(tb, 2)
+	fake.ListMonitors.AssertCalledWith(tb, func(*monitors.ListMonitorsParams) bool { return false })
+	if len(tb.errors) != 2 {
+		t.Errorf("expected both assertions to fail, got %d failures", len(tb.errors))
+	}
+}
+
+func TestMultiResultOperation(t *testing.T) {
+	fake := fakes.NewLogsPipeline()
+	fake.GetConfig.Returns(fakes.LogsPipelineGetConfigResult{NoContent: &logs_pipeline.GetConfigNoContent{}}, nil)
+
+	ok, noContent, err := fake.Client().GetConfig(logs_pipeline.NewGetConfigParams(), nil)
+	if err != nil || ok != nil || noContent == nil {
+		t.Errorf("unexpected responses: %v, %v, %v", ok, noContent, err)
+	}
+}
@@ -0,0 +1,134 @@
+package groundcover
+
+import (
+	"context"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/apikeys"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/ingestionkeys"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/serviceaccounts"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// ServiceAccountsService manages service accounts.
+type ServiceAccountsService struct {
+	api serviceaccounts.ClientService
+}
+
+// List returns all service accounts.
+func (s *ServiceAccountsService) List(ctx context.Context) ([]*models.ServiceAccountsWithPolicy, error) {
+	resp, err := s.api.ListServiceAccounts(serviceaccounts.NewListServiceAccountsParamsWithContext(ctx), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Get returns a service account.
+func (s *ServiceAccountsService) Get(ctx context.Context, id string) (*models.ServiceAccountsWithPolicy, error) {
+	resp, err := s.api.GetServiceAccount(serviceaccounts.NewGetServiceAccountParamsWithContext(ctx).WithID(id), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Create creates a service account.
+func (s *ServiceAccountsService) Create(ctx context.Context, account *models.CreateServiceAccountRequest) (*models.ServiceAccountCreatePayload, error) {
+	resp, err := s.api.CreateServiceAccount(serviceaccounts.NewCreateServiceAccountParamsWithContext(ctx).WithBody(account), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Update updates the service account identified by account.ServiceAccountID.
+func (s *ServiceAccountsService) Update(ctx context.Context, account *models.UpdateServiceAccountRequest) (*models.UpdateServiceAccountResponse, error) {
+	resp, err := s.api.UpdateServiceAccount(serviceaccounts.NewUpdateServiceAccountParamsWithContext(ctx).WithBody(account), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Delete deletes a service account.
+func (s *ServiceAccountsService) Delete(ctx context.Context, id string) error {
+	_, err := s.api.DeleteServiceAccount(serviceaccounts.NewDeleteServiceAccountParamsWithContext(ctx).WithID(id), nil)
+	return wrapError(err)
+}
+
+// APIKeysService manages service account API keys.
+type APIKeysService struct {
+	api apikeys.ClientService
+}
+
+// APIKeyListOptions selects the keys returned by List in addition to the active ones.
+type APIKeyListOptions struct {
+	WithExpired bool
+	WithRevoked bool
+}
+
+// List returns the API keys selected by opts.
+func (s *APIKeysService) List(ctx context.Context, opts APIKeyListOptions) ([]*models.ListAPIKeysResponseItem, error) {
+	params := apikeys.NewListAPIKeysParamsWithContext(ctx)
+	if opts.WithExpired {
+		params.SetWithExpired(&opts.WithExpired)
+	}
+	if opts.WithRevoked {
+		params.SetWithRevoked(&opts.WithRevoked)
+	}
+
+	resp, err := s.api.ListAPIKeys(params, nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Create creates an API key. The key itself is only returned once.
+func (s *APIKeysService) Create(ctx context.Context, key *models.CreateAPIKeyRequest) (*models.CreateAPIKeyResponse, error) {
+	resp, err := s.api.CreateAPIKey(apikeys.NewCreateAPIKeyParamsWithContext(ctx).WithBody(key), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Revoke revokes an API key.
+func (s *APIKeysService) Revoke(ctx context.Context, id string) error {
+	_, err := s.api.DeleteAPIKey(apikeys.NewDeleteAPIKeyParamsWithContext(ctx).WithID(id), nil)
+	return wrapError(err)
+}
+
+// IngestionKeysService manages ingestion keys.
+type IngestionKeysService struct {
+	api ingestionkeys.ClientService
+}
+
+// List returns the ingestion keys matching filter. A nil filter returns all keys.
+func (s *IngestionKeysService) List(ctx context.Context, filter *models.ListIngestionKeysRequest) ([]*models.IngestionKeyResult, error) {
+	if filter == nil {
+		filter = &models.ListIngestionKeysRequest{}
+	}
+
+	resp, err := s.api.ListIngestionKeys(ingestionkeys.NewListIngestionKeysParamsWithContext(ctx).WithBody(filter), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Create creates an ingestion key.
+func (s *IngestionKeysService) Create(ctx context.Context, key *models.CreateIngestionKeyRequest) (*models.IngestionKeyResult, error) {
+	resp, err := s.api.CreateIngestionKey(ingestionkeys.NewCreateIngestionKeyParamsWithContext(ctx).WithBody(key), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Delete deletes the ingestion key with the given name.
+func (s *IngestionKeysService) Delete(ctx context.Context, name string) error {
+	params := ingestionkeys.NewDeleteIngestionKeyParamsWithContext(ctx).WithBody(&models.DeleteIngestionKeyRequest{Name: &name})
+	_, err := s.api.DeleteIngestionKey(params, nil)
+	return wrapError(err)
+}
@@ -0,0 +1,78 @@
+// Package groundcover is a hand-written facade over the generated API
+// clients. It hides the params builders and response wrappers:
+//
+//	gc, err := groundcover.New(apiKey, backendID, baseURL)
+//	...
+//	monitor, err := gc.Monitors.Get(ctx, id)
+//	policies, err := gc.Policies.List(ctx)
+//	silence, err := gc.Silences.Create(ctx, spec)
+//
+// Every method returns models types and *apierrors.APIError on failure.
+// The generated client stays reachable through Client.API.
+package groundcover
+
+import (
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/apierrors"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/transport"
+)
+
+// Client groups the groundcover API services.
+type Client struct {
+	// API is the generated client, for operations or options the facade does not cover.
+	API *client.GroundcoverAPI
+
+	Monitors        *MonitorsService
+	Silences        *SilencesService
+	Policies        *PoliciesService
+	ServiceAccounts *ServiceAccountsService
+	APIKeys         *APIKeysService
+	IngestionKeys   *IngestionKeysService
+	Workflows       *WorkflowsService
+	LogsPipeline    *LogsPipelineService
+	Logs            *LogsService
+	Traces          *TracesService
+	Events          *EventsService
+	Metrics         *MetricsService
+	Search          *SearchService
+	K8s             *K8sService
+}
+
+// New creates a Client with a fully configured SDK transport. Options are
+// passed to transport.NewSDKClient; unified errors are always enabled.
+func New(apiKey, backendID, baseURL string, options ...transport.ClientOption) (*Client, error) {
+	options = append(options, transport.WithUnifiedErrors())
+
+	api, err := transport.NewSDKClient(apiKey, backendID, baseURL, options...)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromAPI(api), nil
+}
+
+// NewFromAPI wraps an existing generated client, e.g. one built with custom
+// transport settings or a fake.
+func NewFromAPI(api *client.GroundcoverAPI) *Client {
+	return &Client{
+		API:             api,
+		Monitors:        &MonitorsService{api: api.Monitors},
+		Silences:        &SilencesService{api: api.Monitors},
+		Policies:        &PoliciesService{api: api.Policies},
+		ServiceAccounts: &ServiceAccountsService{api: api.Serviceaccounts},
+		APIKeys:         &APIKeysService{api: api.Apikeys},
+		IngestionKeys:   &IngestionKeysService{api: api.Ingestionkeys},
+		Workflows:       &WorkflowsService{api: api.Workflows},
+		LogsPipeline:    &LogsPipelineService{api: api.LogsPipeline},
+		Logs:            &LogsService{api: api.Logs},
+		Traces:          &TracesService{api: api.Traces},
+		Events:          &EventsService{api: api.Events},
+		Metrics:         &MetricsService{api: api.Metrics},
+		Search:          &SearchService{api: api.Search},
+		K8s:             &K8sService{api: api.K8s},
+	}
+}
+
+// wrapError adapts generated errors to *apierrors.APIError.
+func wrapError(err error) error {
+	return apierrors.From(err)
+}
@@ -0,0 +1,142 @@
+package groundcover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/apierrors"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/logspipeline"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	gc, err := New("token", "backend-1", server.URL)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return gc
+}
+
+func TestMonitorsGet(t *testing.T) {
+	gc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/monitors/m-1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/x-yaml")
+		w.Write([]byte("title: High CPU\nseverity: S1\n"))
+	})
+
+	monitor, err := gc.Monitors.Get(context.Background(), "m-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if monitor.Title == nil || *monitor.Title != "High CPU" {
+		t.Errorf("Title: expected High CPU, got %v", monitor.Title)
+	}
+	if monitor.Severity != "S1" {
+		t.Errorf("Severity: expected S1, got %s", monitor.Severity)
+	}
+}
+
+func TestMonitorsCreateSendsYAML(t *testing.T) {
+	gc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-yaml" {
+			t.Errorf("Content-Type: expected application/x-yaml, got %s", ct)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "title: High CPU") {
+			t.Errorf("expected YAML body, got %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"monitorId":"m-2"}`))
+	})
+
+	title := "High CPU"
+	id, err := gc.Monitors.Create(context.Background(), &models.CreateMonitorRequest{Title: &title})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if id != "m-2" {
+		t.Errorf("expected monitor ID m-2, got %s", id)
+	}
+}
+
+func TestPoliciesList(t *testing.T) {
+	gc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"uuid": "p-1", "name": "admins", "entityCount": 3},
+		})
+	})
+
+	policies, err := gc.Policies.List(context.Background())
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(policies) != 1 || policies[0].Name == nil || *policies[0].Name != "admins" || policies[0].EntityCount != 3 {
+		t.Errorf("unexpected policies: %+v", policies)
+	}
+}
+
+func TestSilencesCreateError(t *testing.T) {
+	gc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"endsAt must be after startsAt"}`))
+	})
+
+	_, err := gc.Silences.Create(context.Background(), &models.CreateSilenceRequest{})
+	if !errors.Is(err, apierrors.ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+
+	apiErr, ok := apierrors.As(err)
+	if !ok {
+		t.Fatalf("expected *apierrors.APIError, got %T", err)
+	}
+	if apiErr.Operation != "monitors.CreateSilence" || apiErr.Message != "endsAt must be after startsAt" {
+		t.Errorf("unexpected error details: %+v", apiErr)
+	}
+}
+
+func TestLogsPipelineCreateConfigValidates(t *testing.T) {
+	var body string
+	gc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(data)
+	})
+
+	invalid := &logspipeline.Config{Rules: []*logspipeline.Rule{{Name: "a"}}}
+	var validationErr *logspipeline.ValidationError
+	if _, err := gc.LogsPipeline.CreateConfig(context.Background(), invalid); !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if body != "" {
+		t.Fatalf("expected no request for an invalid config, got %s", body)
+	}
+
+	config, err := logspipeline.NewBuilder().Rule("a").Do(`set(attributes["x"], "y")`).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gc.LogsPipeline.CreateConfig(context.Background(), config); err != nil {
+		t.Fatalf("CreateConfig failed: %v", err)
+	}
+	if !strings.Contains(body, `- ruleName: a`) {
+		t.Errorf("expected the config YAML in the request, got %s", body)
+	}
+}
@@ -0,0 +1,145 @@
+package groundcover
+
+import (
+	"context"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/monitors"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// MonitorsService manages monitors.
+type MonitorsService struct {
+	api monitors.ClientService
+}
+
+// List returns the monitors matching the given conditions (all monitors if none are given).
+func (s *MonitorsService) List(ctx context.Context, conditions ...*models.Condition) ([]*models.MonitorListItem, error) {
+	if conditions == nil {
+		conditions = []*models.Condition{}
+	}
+
+	params := monitors.NewListMonitorsParamsWithContext(ctx).WithBody(&models.MonitorListRequest{Conditions: conditions})
+	resp, err := s.api.ListMonitors(params, nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if resp.Payload == nil {
+		return nil, nil
+	}
+	return resp.Payload.Monitors, nil
+}
+
+// Get returns the definition of a monitor.
+func (s *MonitorsService) Get(ctx context.Context, id string) (*models.MonitorModel, error) {
+	raw, err := s.GetYAML(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	monitor := &models.MonitorModel{}
+	if err := yaml.Unmarshal(raw, monitor); err != nil {
+		return nil, fmt.Errorf("error decoding monitor %s: %w", id, err)
+	}
+	return monitor, nil
+}
+
+// GetYAML returns the definition of a monitor as the YAML document stored by the API.
+func (s *MonitorsService) GetYAML(ctx context.Context, id string) ([]byte, error) {
+	params := monitors.NewGetMonitorParamsWithContext(ctx).WithID(id)
+	resp, err := s.api.GetMonitor(params, nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Create creates a monitor and returns its ID.
+func (s *MonitorsService) Create(ctx context.Context, monitor *models.CreateMonitorRequest) (string, error) {
+	params := monitors.NewCreateMonitorParamsWithContext(ctx).WithBody(monitor)
+	resp, err := s.api.CreateMonitor(params, nil)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return resp.Payload.MonitorID, nil
+}
+
+// Update replaces the definition of a monitor.
+func (s *MonitorsService) Update(ctx context.Context, id string, monitor *models.UpdateMonitorRequest) error {
+	params := monitors.NewUpdateMonitorParamsWithContext(ctx).WithID(id).WithBody(monitor)
+	_, err := s.api.UpdateMonitor(params, nil)
+	return wrapError(err)
+}
+
+// Delete deletes a monitor.
+func (s *MonitorsService) Delete(ctx context.Context, id string) error {
+	_, err := s.api.DeleteMonitor(monitors.NewDeleteMonitorParamsWithContext(ctx).WithID(id), nil)
+	return wrapError(err)
+}
+
+// SilencesService manages alert silences.
+type SilencesService struct {
+	api monitors.ClientService
+}
+
+// SilenceListOptions filters the silences returned by List.
+type SilenceListOptions struct {
+	ActiveOnly bool
+	Limit      int64
+	Skip       int64
+}
+
+// List returns the silences matching opts.
+func (s *SilencesService) List(ctx context.Context, opts SilenceListOptions) ([]*models.Silence, error) {
+	params := monitors.NewGetAllSilencesParamsWithContext(ctx)
+	if opts.ActiveOnly {
+		params.SetActive(&opts.ActiveOnly)
+	}
+	if opts.Limit > 0 {
+		params.SetLimit(&opts.Limit)
+	}
+	if opts.Skip > 0 {
+		params.SetSkip(&opts.Skip)
+	}
+
+	resp, err := s.api.GetAllSilences(params, nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Get returns a silence.
+func (s *SilencesService) Get(ctx context.Context, id string) (*models.Silence, error) {
+	resp, err := s.api.GetSilence(monitors.NewGetSilenceParamsWithContext(ctx).WithID(id), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Create creates a silence.
+func (s *SilencesService) Create(ctx context.Context, spec *models.CreateSilenceRequest) (*models.Silence, error) {
+	resp, err := s.api.CreateSilence(monitors.NewCreateSilenceParamsWithContext(ctx).WithBody(spec), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Update updates a silence.
+func (s *SilencesService) Update(ctx context.Context, id string, spec *models.UpdateSilenceRequest) (*models.Silence, error) {
+	resp, err := s.api.UpdateSilence(monitors.NewUpdateSilenceParamsWithContext(ctx).WithID(id).WithBody(spec), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Delete deletes a silence.
+func (s *SilencesService) Delete(ctx context.Context, id string) error {
+	_, err := s.api.DeleteSilence(monitors.NewDeleteSilenceParamsWithContext(ctx).WithID(id), nil)
+	return wrapError(err)
+}
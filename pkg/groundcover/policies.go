@@ -0,0 +1,70 @@
+package groundcover
+
+import (
+	"context"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/policies"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// PoliciesService manages RBAC policies.
+type PoliciesService struct {
+	api policies.ClientService
+}
+
+// List returns all policies.
+func (s *PoliciesService) List(ctx context.Context) ([]*models.PolicyWithEntityCount, error) {
+	resp, err := s.api.ListPolicies(policies.NewListPoliciesParamsWithContext(ctx), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Get returns a policy.
+func (s *PoliciesService) Get(ctx context.Context, id string) (*models.Policy, error) {
+	resp, err := s.api.GetPolicy(policies.NewGetPolicyParamsWithContext(ctx).WithID(id), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Create creates a policy.
+func (s *PoliciesService) Create(ctx context.Context, policy *models.CreatePolicyRequest) (*models.Policy, error) {
+	resp, err := s.api.CreatePolicy(policies.NewCreatePolicyParamsWithContext(ctx).WithBody(policy), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Update updates a policy.
+func (s *PoliciesService) Update(ctx context.Context, id string, policy *models.UpdatePolicyRequest) (*models.Policy, error) {
+	resp, err := s.api.UpdatePolicy(policies.NewUpdatePolicyParamsWithContext(ctx).WithID(id).WithBody(policy), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Delete deletes a policy.
+func (s *PoliciesService) Delete(ctx context.Context, id string) error {
+	_, err := s.api.DeletePolicy(policies.NewDeletePolicyParamsWithContext(ctx).WithID(id), nil)
+	return wrapError(err)
+}
+
+// Apply applies policies to service accounts.
+func (s *PoliciesService) Apply(ctx context.Context, request *models.ApplyPolicyRequest) error {
+	_, err := s.api.ApplyPolicy(policies.NewApplyPolicyParamsWithContext(ctx).WithBody(request), nil)
+	return wrapError(err)
+}
+
+// AuditTrail returns the revisions of a policy.
+func (s *PoliciesService) AuditTrail(ctx context.Context, id string) ([]*models.Policy, error) {
+	resp, err := s.api.GetPolicyAuditTrail(policies.NewGetPolicyAuditTrailParamsWithContext(ctx).WithID(id), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
@@ -0,0 +1,160 @@
+package groundcover
+
+import (
+	"context"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/events"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/k8s"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/logs"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/metrics"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/search"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/traces"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// LogsService searches logs.
+type LogsService struct {
+	api logs.ClientService
+}
+
+// Search runs a logs search and returns the decoded JSON response.
+func (s *LogsService) Search(ctx context.Context, request *models.LogsSearchRequest) (interface{}, error) {
+	resp, err := s.api.SearchLogs(logs.NewSearchLogsParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// TracesService searches traces.
+type TracesService struct {
+	api traces.ClientService
+}
+
+// Search runs a traces search and returns the decoded JSON response.
+func (s *TracesService) Search(ctx context.Context, request *models.TracesSearchRequest) (interface{}, error) {
+	resp, err := s.api.SearchTraces(traces.NewSearchTracesParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// EventsService searches events.
+type EventsService struct {
+	api events.ClientService
+}
+
+// Search runs an events search and returns the decoded JSON response.
+func (s *EventsService) Search(ctx context.Context, request *models.EventsSearchRequest) (interface{}, error) {
+	resp, err := s.api.SearchEvents(events.NewSearchEventsParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// MetricsService queries metrics.
+type MetricsService struct {
+	api metrics.ClientService
+}
+
+// Query runs a metrics query and returns the decoded JSON response.
+func (s *MetricsService) Query(ctx context.Context, request *models.QueryRequest) (interface{}, error) {
+	resp, err := s.api.MetricsQuery(metrics.NewMetricsQueryParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Names returns metric names.
+func (s *MetricsService) Names(ctx context.Context, request *models.MetricsNamesRequest) (*models.MetricsNamesResponse, error) {
+	resp, err := s.api.GetMetricNames(metrics.NewGetMetricNamesParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Keys returns metric label keys.
+func (s *MetricsService) Keys(ctx context.Context, request *models.MetricsKeysRequest) (*models.MetricsKeysResponse, error) {
+	resp, err := s.api.GetMetricKeys(metrics.NewGetMetricKeysParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Values returns metric label values.
+func (s *MetricsService) Values(ctx context.Context, request *models.MetricsValuesRequest) (*models.MetricsValuesResponse, error) {
+	resp, err := s.api.GetMetricValues(metrics.NewGetMetricValuesParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// SearchService discovers searchable keys and values.
+type SearchService struct {
+	api search.ClientService
+}
+
+// Keys returns searchable keys.
+func (s *SearchService) Keys(ctx context.Context, request *models.KeysRequest) (*models.KeysResponse, error) {
+	resp, err := s.api.GetKeys(search.NewGetKeysParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Values returns the values of a searchable key.
+func (s *SearchService) Values(ctx context.Context, request *models.ValuesRequest) (*models.ValuesResponse, error) {
+	resp, err := s.api.GetValues(search.NewGetValuesParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Discovery returns the discovery data for a search.
+func (s *SearchService) Discovery(ctx context.Context, request *models.DiscoveryRequest) (*models.DiscoveryResponse, error) {
+	resp, err := s.api.GetDiscovery(search.NewGetDiscoveryParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// K8sService reads Kubernetes inventory and events.
+type K8sService struct {
+	api k8s.ClientService
+}
+
+// Clusters lists clusters.
+func (s *K8sService) Clusters(ctx context.Context, request *models.ClustersListRequest) (*models.ClustersListResponse, error) {
+	resp, err := s.api.ClustersList(k8s.NewClustersListParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Workloads lists workloads.
+func (s *K8sService) Workloads(ctx context.Context, request *models.WorkloadsListRequest) (*models.WorkloadsListResponse, error) {
+	resp, err := s.api.WorkloadsList(k8s.NewWorkloadsListParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// EventsOverTime returns Kubernetes events over time.
+func (s *K8sService) EventsOverTime(ctx context.Context, request *models.GetEventsOverTimeRequest) (*models.GetEventsOverTimeResponse, error) {
+	resp, err := s.api.GetEventsOverTime(k8s.NewGetEventsOverTimeParamsWithContext(ctx).WithBody(request), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
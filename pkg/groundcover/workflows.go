@@ -0,0 +1,115 @@
+package groundcover
+
+import (
+	"context"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/logs_pipeline"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/workflows"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/logspipeline"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// WorkflowsService manages workflows.
+type WorkflowsService struct {
+	api workflows.ClientService
+}
+
+// List returns all workflows.
+func (s *WorkflowsService) List(ctx context.Context) ([]*models.Workflow, error) {
+	resp, err := s.api.ListWorkflows(workflows.NewListWorkflowsParamsWithContext(ctx), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if resp.Payload == nil {
+		return nil, nil
+	}
+	return resp.Payload.Workflows, nil
+}
+
+// Create creates a workflow from its YAML definition.
+func (s *WorkflowsService) Create(ctx context.Context, definition string) (*models.CreateWorkflowResponse, error) {
+	params := workflows.NewCreateWorkflowParamsWithContext(ctx).WithBody(definition)
+	resp, err := s.api.CreateWorkflow(params, nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Delete deletes a workflow.
+func (s *WorkflowsService) Delete(ctx context.Context, id string) error {
+	_, err := s.api.DeleteWorkflow(workflows.NewDeleteWorkflowParamsWithContext(ctx).WithID(id), nil)
+	return wrapError(err)
+}
+
+// LogsPipelineService manages the logs pipeline configuration.
+type LogsPipelineService struct {
+	api logs_pipeline.ClientService
+}
+
+// Get returns the current configuration, or nil if none is set.
+func (s *LogsPipelineService) Get(ctx context.Context) (*models.LogsPipelineConfig, error) {
+	ok, _, err := s.api.GetConfig(logs_pipeline.NewGetConfigParamsWithContext(ctx), nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	if ok == nil {
+		return nil, nil
+	}
+	return ok.Payload, nil
+}
+
+// Create sets the configuration from its YAML value.
+func (s *LogsPipelineService) Create(ctx context.Context, value string) (*models.LogsPipelineConfig, error) {
+	params := logs_pipeline.NewCreateConfigParamsWithContext(ctx).WithBody(&models.CreateOrUpdateLogsPipelineConfigRequest{Value: value})
+	resp, err := s.api.CreateConfig(params, nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// Update replaces the configuration with the given YAML value.
+func (s *LogsPipelineService) Update(ctx context.Context, value string) (*models.LogsPipelineConfig, error) {
+	params := logs_pipeline.NewUpdateConfigParamsWithContext(ctx).WithBody(&models.CreateOrUpdateLogsPipelineConfigRequest{Value: value})
+	resp, err := s.api.UpdateConfig(params, nil)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return resp.Payload, nil
+}
+
+// GetConfig returns the current configuration decoded, or nil if none is set.
+func (s *LogsPipelineService) GetConfig(ctx context.Context) (*logspipeline.Config, error) {
+	model, err := s.Get(ctx)
+	if err != nil || model == nil {
+		return nil, err
+	}
+	return logspipeline.FromModel(model)
+}
+
+// CreateConfig validates config and sets it as the configuration. An invalid
+// config returns a *logspipeline.ValidationError without calling the API.
+func (s *LogsPipelineService) CreateConfig(ctx context.Context, config *logspipeline.Config) (*models.LogsPipelineConfig, error) {
+	request, err := config.Request()
+	if err != nil {
+		return nil, err
+	}
+	return s.Create(ctx, request.Value)
+}
+
+// UpdateConfig validates config and replaces the configuration with it. An
+// invalid config returns a *logspipeline.ValidationError without calling the API.
+func (s *LogsPipelineService) UpdateConfig(ctx context.Context, config *logspipeline.Config) (*models.LogsPipelineConfig, error) {
+	request, err := config.Request()
+	if err != nil {
+		return nil, err
+	}
+	return s.Update(ctx, request.Value)
+}
+
+// Delete removes the configuration.
+func (s *LogsPipelineService) Delete(ctx context.Context) error {
+	_, err := s.api.DeleteConfig(logs_pipeline.NewDeleteConfigParamsWithContext(ctx), nil)
+	return wrapError(err)
+}
@@ -0,0 +1,348 @@
+// Package incidents summarizes container crashes, OOM kills, restart
+// back-offs and evictions per workload.
+//
+// An Analyzer lists the crash, OOM, back-off and eviction events of a time
+// range, filtered by the server, groups them into one Incident per workload
+// and attaches the workload's memory usage and limit:
+//
+//	analyzer := incidents.NewAnalyzer(client.K8s, incidents.WithNamespace("shop"))
+//	report, err := analyzer.Analyze(ctx, time.Now().Add(-24*time.Hour), time.Now())
+//	if err != nil {
+//		...
+//	}
+//	for _, incident := range report.Incidents {
+//		fmt.Println(incident.Workload, incident.OOMKills, incident.Crashes, incident.Pods)
+//	}
+//	fmt.Print(report.Markdown())
+package incidents
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/workloads"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/k8sevents"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/types"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/utils"
+)
+
+// Kinds of incident events.
+const (
+	KindOOMKilled = "OOMKilled"
+	KindCrash     = "Crash"
+	KindBackOff   = "BackOff"
+	KindEvicted   = "Evicted"
+)
+
+// Client lists Kubernetes events and workloads. *groundcover.K8sService
+// implements it.
+type Client interface {
+	k8sevents.Lister
+	workloads.Lister
+}
+
+// Classify returns the kind of incident event, or "" if the event does not
+// signal a failing workload. An exit code of 137 is taken as an OOM kill;
+// see k8sevents.InterpretExitCode.
+func Classify(event *models.EventsOverTimeResponse) string {
+	exit, _ := k8sevents.ParseExitCode(event.ExitCode)
+	switch {
+	case event.Reason == types.ConditionValueOOMKilled || event.Reason == "OOMKilling" || (exit != nil && exit.OOMKilled()):
+		return KindOOMKilled
+	case event.Reason == "Evicted":
+		return KindEvicted
+	case event.Reason == "BackOff" || event.Reason == "CrashLoopBackOff":
+		return KindBackOff
+	case event.Type == types.ConditionValueTypeContainerCrash || (exit != nil && exit.Code != 0):
+		return KindCrash
+	}
+	return ""
+}
+
+// Memory is the memory usage of a workload against its limit, in bytes.
+type Memory struct {
+	Usage float64 `json:"usage"`
+	Limit float64 `json:"limit,omitempty"`
+}
+
+// Utilization returns usage as a fraction of the limit, or 0 without a
+// limit.
+func (m Memory) Utilization() float64 {
+	if m.Limit <= 0 {
+		return 0
+	}
+	return m.Usage / m.Limit
+}
+
+// ExitCount is the number of crashes with an exit code.
+type ExitCount struct {
+	Exit  k8sevents.Exit `json:"exit"`
+	Count int            `json:"count"`
+}
+
+// Incident summarizes the incident events of a workload.
+type Incident struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload"`
+
+	// The counts are occurrences: an event that recurred counts as many
+	// times as its Count.
+	OOMKills  int `json:"oomKills"`
+	Crashes   int `json:"crashes"`
+	BackOffs  int `json:"backOffs"`
+	Evictions int `json:"evictions"`
+
+	// FirstSeen is when the earliest event first occurred, which may be
+	// before the analyzed range.
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	// Pods are the names of the affected pods, sorted.
+	Pods []string `json:"pods"`
+	// Exits are the exit codes of the crashed containers, most frequent
+	// first.
+	Exits []ExitCount `json:"exits,omitempty"`
+	// LastMessage is the message of the latest event.
+	LastMessage string `json:"lastMessage,omitempty"`
+	// Memory is nil when the workload was not found in the workloads list.
+	Memory *Memory `json:"memory,omitempty"`
+}
+
+// Total returns the number of incident events of the workload.
+func (i *Incident) Total() int {
+	return i.OOMKills + i.Crashes + i.BackOffs + i.Evictions
+}
+
+// Report is the result of Analyze.
+type Report struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Incidents are sorted by number of events, most first, then by last
+	// occurrence, latest first.
+	Incidents []*Incident `json:"incidents"`
+}
+
+// Option customizes an Analyzer.
+type Option func(*Analyzer)
+
+// WithNamespace restricts the analysis to a namespace.
+func WithNamespace(namespace string) Option {
+	return func(a *Analyzer) {
+		a.conditions.Add(types.ConditionKeyNamespace, namespace)
+	}
+}
+
+// WithWorkload restricts the analysis to a workload.
+func WithWorkload(workload string) Option {
+	return func(a *Analyzer) {
+		a.conditions.Add(types.ConditionKeyWorkload, workload)
+	}
+}
+
+// WithSources restricts the analysis to sources such as a cluster. The
+// conditions are sent as the request Sources.
+func WithSources(sources *utils.ConditionSet) Option {
+	return func(a *Analyzer) {
+		a.sources = sources.Build()
+	}
+}
+
+// Analyzer builds incident reports.
+type Analyzer struct {
+	client     Client
+	conditions *utils.ConditionSet
+	sources    []*models.Condition
+}
+
+// NewAnalyzer creates an Analyzer reading events and workloads through
+// client.
+func NewAnalyzer(client Client, options ...Option) *Analyzer {
+	a := &Analyzer{
+		client:     client,
+		conditions: utils.NewConditionSet(),
+		sources:    []*models.Condition{},
+	}
+	for _, option := range options {
+		option(a)
+	}
+	return a
+}
+
+// key identifies a workload.
+type key struct {
+	cluster, namespace, workload string
+}
+
+// queries are the event conditions Analyze lists events by, one request
+// each: crash events and the reasons of the other incident kinds. Events
+// matching none of them are not downloaded.
+var queries = []struct {
+	key, value string
+}{
+	{types.ConditionKeyType, types.ConditionValueTypeContainerCrash},
+	{types.ConditionKeyReason, types.ConditionValueOOMKilled},
+	{types.ConditionKeyReason, "OOMKilling"},
+	{types.ConditionKeyReason, "BackOff"},
+	{types.ConditionKeyReason, "CrashLoopBackOff"},
+	{types.ConditionKeyReason, "Evicted"},
+}
+
+// eventID identifies an event across its recurrences: by UID, or by the
+// object UID and reason when it has no UID.
+func eventID(event *models.EventsOverTimeResponse) string {
+	switch {
+	case event.UID != "":
+		return event.UID
+	case event.ObjectUID != "":
+		return event.ObjectUID + "/" + event.Reason
+	}
+	return k8sevents.Key(event)
+}
+
+// Analyze reports the incidents between start and end. It lists crash
+// events and the events of the other incident reasons, each event with its
+// raw Kubernetes event. An event counts as many times as it occurred, per
+// its Count, and the incident starts when its earliest event first
+// occurred. Events of the same occurrence returned more than once are
+// counted once; see k8sevents.Key.
+func (a *Analyzer) Analyze(ctx context.Context, start, end time.Time) (*Report, error) {
+	from, to := strfmt.DateTime(start.UTC()), strfmt.DateTime(end.UTC())
+
+	incidents := map[key]*Incident{}
+	pods := map[key]map[string]bool{}
+	exits := map[key]map[int]*ExitCount{}
+	seen := map[string]bool{}
+	// counted is the number of occurrences counted so far per event: a
+	// recurring event is reported again with a higher Count.
+	counted := map[string]int{}
+	for _, query := range queries {
+		request := &models.GetEventsOverTimeRequest{
+			Start:         &from,
+			End:           &to,
+			Conditions:    append(slices.Clip(a.conditions.Build()), utils.NewConditionSet().Add(query.key, query.value).Build()...),
+			Sources:       a.sources,
+			WithRawEvents: true,
+		}
+		for event, err := range k8sevents.All(ctx, a.client, request) {
+			if err != nil {
+				return nil, err
+			}
+			kind := Classify(event)
+			if kind == "" || seen[k8sevents.Key(event)] {
+				continue
+			}
+			seen[k8sevents.Key(event)] = true
+
+			workload := event.Workload
+			if workload == "" {
+				workload = event.Instance
+			}
+			k := key{event.Cluster, event.Namespace, workload}
+			incident := incidents[k]
+			if incident == nil {
+				incident = &Incident{Cluster: k.cluster, Namespace: k.namespace, Workload: k.workload}
+				incidents[k], pods[k], exits[k] = incident, map[string]bool{}, map[int]*ExitCount{}
+			}
+
+			lastSeen := k8sevents.LastSeen(event)
+			firstSeen, occurrences := lastSeen, 1
+			if decoded, err := k8sevents.Decode(event); err == nil {
+				occurrences = int(decoded.Occurrences())
+				if at := decoded.FirstSeen(); !at.IsZero() {
+					firstSeen = at.UTC()
+				}
+			}
+			id := eventID(event)
+			count := max(occurrences-counted[id], 0)
+			counted[id] = max(counted[id], occurrences)
+
+			switch kind {
+			case KindOOMKilled:
+				incident.OOMKills += count
+			case KindCrash:
+				incident.Crashes += count
+			case KindBackOff:
+				incident.BackOffs += count
+			case KindEvicted:
+				incident.Evictions += count
+			}
+			if incident.FirstSeen.IsZero() || firstSeen.Before(incident.FirstSeen) {
+				incident.FirstSeen = firstSeen
+			}
+			if !lastSeen.Before(incident.LastSeen) {
+				incident.LastSeen, incident.LastMessage = lastSeen, event.Message
+			}
+			if event.Instance != "" && (strings.EqualFold(event.ObjectKind, "Pod") || event.ObjectKind == "") {
+				pods[k][event.Instance] = true
+			}
+			if exit, _ := k8sevents.ParseExitCode(event.ExitCode); exit != nil && exit.Code != 0 {
+				if exits[k][exit.Code] == nil {
+					exits[k][exit.Code] = &ExitCount{Exit: *exit}
+				}
+				exits[k][exit.Code].Count += count
+			}
+		}
+	}
+
+	if len(incidents) > 0 {
+		memory, err := a.memory(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for k, incident := range incidents {
+			incident.Memory = memory[k]
+			if incident.Memory == nil {
+				// Events may carry no cluster.
+				incident.Memory = memory[key{"", k.namespace, k.workload}]
+			}
+		}
+	}
+
+	report := &Report{Start: start, End: end, Incidents: []*Incident{}}
+	for k, incident := range incidents {
+		incident.Pods = sortedKeys(pods[k])
+		for _, exit := range exits[k] {
+			incident.Exits = append(incident.Exits, *exit)
+		}
+		slices.SortFunc(incident.Exits, func(a, b ExitCount) int {
+			return cmp.Or(b.Count-a.Count, a.Exit.Code-b.Exit.Code)
+		})
+		report.Incidents = append(report.Incidents, incident)
+	}
+	slices.SortFunc(report.Incidents, func(a, b *Incident) int {
+		return cmp.Or(b.Total()-a.Total(), b.LastSeen.Compare(a.LastSeen),
+			cmp.Compare(a.Cluster, b.Cluster), cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Workload, b.Workload))
+	})
+	return report, nil
+}
+
+// memory returns the memory of the analyzed workloads, by cluster,
+// namespace and workload, and by namespace and workload alone.
+func (a *Analyzer) memory(ctx context.Context) (map[key]*Memory, error) {
+	memory := map[key]*Memory{}
+	request := &models.WorkloadsListRequest{Conditions: a.conditions.Build(), Sources: a.sources}
+	for workload, err := range workloads.All(ctx, a.client, request) {
+		if err != nil {
+			return nil, err
+		}
+		m := &Memory{Usage: workload.MemoryUsage, Limit: workload.MemoryLimit}
+		memory[key{workload.Cluster, workload.Namespace, workload.Workload}] = m
+		memory[key{"", workload.Namespace, workload.Workload}] = m
+	}
+	return memory, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
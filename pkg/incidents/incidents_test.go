@@ -0,0 +1,186 @@
+package incidents
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/types"
+)
+
+// fakeClient serves fixed events, filtered by the request conditions, and
+// workloads, two workloads per page.
+type fakeClient struct {
+	events       []*models.EventsOverTimeResponse
+	workloads    []*models.WorkloadsListItem
+	workloadsErr error
+
+	eventRequests    []models.GetEventsOverTimeRequest
+	workloadRequests []models.WorkloadsListRequest
+}
+
+func (c *fakeClient) EventsOverTime(ctx context.Context, request *models.GetEventsOverTimeRequest) (*models.GetEventsOverTimeResponse, error) {
+	c.eventRequests = append(c.eventRequests, *request)
+	events := slices.DeleteFunc(slices.Clone(c.events), func(event *models.EventsOverTimeResponse) bool {
+		return !matches(event, request.Conditions)
+	})
+	start := min(int(request.Skip), len(events))
+	end := min(start+int(request.Limit), len(events))
+	return &models.GetEventsOverTimeResponse{Events: events[start:end]}, nil
+}
+
+// matches reports whether event meets every condition.
+func matches(event *models.EventsOverTimeResponse, conditions []*models.Condition) bool {
+	fields := map[string]string{
+		types.ConditionKeyNamespace: event.Namespace,
+		types.ConditionKeyWorkload:  event.Workload,
+		types.ConditionKeyReason:    event.Reason,
+		types.ConditionKeyType:      event.Type,
+	}
+	for _, condition := range conditions {
+		if fields[condition.Key] != condition.Filters[0].Value {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *fakeClient) Workloads(ctx context.Context, request *models.WorkloadsListRequest) (*models.WorkloadsListResponse, error) {
+	c.workloadRequests = append(c.workloadRequests, *request)
+	if c.workloadsErr != nil {
+		return nil, c.workloadsErr
+	}
+	start := min(int(request.Skip), len(c.workloads))
+	end := min(start+2, len(c.workloads))
+	return &models.WorkloadsListResponse{Workloads: c.workloads[start:end], Total: uint32(len(c.workloads))}, nil
+}
+
+func at(minute int) strfmt.DateTime {
+	return strfmt.DateTime(time.Date(2026, 10, 18, 12, minute, 0, 0, time.UTC))
+}
+
+func fixture() *fakeClient {
+	return &fakeClient{
+		events: []*models.EventsOverTimeResponse{
+			{UID: "1", Namespace: "shop", Workload: "cart", Instance: "cart-1", ObjectKind: "Pod", Type: "container_crash", Reason: "OOMKilled", ExitCode: "137", Message: "OOM", Timestamp: at(1),
+				Raw: `{"count":2,"firstTimestamp":"2026-10-18T11:40:00Z","lastTimestamp":"2026-10-18T12:01:00Z"}`},
+			{UID: "1", Namespace: "shop", Workload: "cart", Instance: "cart-1", ObjectKind: "Pod", Type: "container_crash", Reason: "OOMKilled", ExitCode: "137", Message: "OOM", Timestamp: at(2),
+				Raw: `{"count":3,"firstTimestamp":"2026-10-18T11:40:00Z","lastTimestamp":"2026-10-18T12:02:00Z"}`},
+			{UID: "2", Namespace: "shop", Workload: "cart", Instance: "cart-2", ObjectKind: "Pod", Type: "container_crash", Reason: "Error", ExitCode: "137", Message: "killed", Timestamp: at(3)},
+			{UID: "3", Namespace: "shop", Workload: "cart", Instance: "cart-1", ObjectKind: "Pod", Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Timestamp: at(4)},
+			{UID: "3", Namespace: "shop", Workload: "cart", Instance: "cart-1", ObjectKind: "Pod", Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Timestamp: at(4)},
+			{UID: "4", Namespace: "shop", Workload: "api", Instance: "api-1", ObjectKind: "Pod", Type: "container_crash", Reason: "Error", ExitCode: "1", Message: "panic", Timestamp: at(2)},
+			{UID: "5", Namespace: "shop", Workload: "api", Instance: "api-2", ObjectKind: "Pod", Type: "Warning", Reason: "Evicted", Message: "The node was low on resource: memory.", Timestamp: at(5)},
+			{UID: "6", Namespace: "shop", Workload: "api", Instance: "api-1", ObjectKind: "Pod", Type: "Normal", Reason: "Pulled", Timestamp: at(6)},
+		},
+		workloads: []*models.WorkloadsListItem{
+			{Namespace: "shop", Workload: "web", MemoryUsage: 1 << 20},
+			{Namespace: "shop", Workload: "api", MemoryUsage: 256 << 20},
+			{Namespace: "shop", Workload: "cart", MemoryUsage: 1843 << 20, MemoryLimit: 2 << 30},
+		},
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		event *models.EventsOverTimeResponse
+		want  string
+	}{
+		{&models.EventsOverTimeResponse{Reason: "OOMKilled"}, KindOOMKilled},
+		{&models.EventsOverTimeResponse{Reason: "Error", ExitCode: "137"}, KindOOMKilled},
+		{&models.EventsOverTimeResponse{Reason: "Error", ExitCode: "143", Type: "container_crash"}, KindCrash},
+		{&models.EventsOverTimeResponse{Reason: "Error", ExitCode: "1"}, KindCrash},
+		{&models.EventsOverTimeResponse{Reason: "BackOff"}, KindBackOff},
+		{&models.EventsOverTimeResponse{Reason: "Evicted"}, KindEvicted},
+		{&models.EventsOverTimeResponse{Reason: "Completed", ExitCode: "0"}, ""},
+		{&models.EventsOverTimeResponse{Reason: "Scheduled"}, ""},
+	}
+	for _, test := range tests {
+		if got := Classify(test.event); got != test.want {
+			t.Errorf("Classify(%+v) = %q, want %q", test.event, got, test.want)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	client := fixture()
+	analyzer := NewAnalyzer(client, WithNamespace("shop"))
+	start, end := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC), time.Date(2026, 10, 18, 13, 0, 0, 0, time.UTC)
+	report, err := analyzer.Analyze(context.Background(), start, end)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if len(report.Incidents) != 2 {
+		t.Fatalf("Expected 2 incidents, got %d", len(report.Incidents))
+	}
+	cart, api := report.Incidents[0], report.Incidents[1]
+	if cart.Workload != "cart" || cart.OOMKills != 4 || cart.BackOffs != 1 || cart.Crashes != 0 || cart.Total() != 5 {
+		t.Errorf("Unexpected cart incident %+v", cart)
+	}
+	if !reflect.DeepEqual(cart.Pods, []string{"cart-1", "cart-2"}) || len(cart.Exits) != 1 || cart.Exits[0].Count != 4 {
+		t.Errorf("Unexpected cart pods %v and exits %v", cart.Pods, cart.Exits)
+	}
+	// The first OOM kill was before the range; see its firstTimestamp.
+	if !cart.FirstSeen.Equal(time.Date(2026, 10, 18, 11, 40, 0, 0, time.UTC)) || !cart.LastSeen.Equal(time.Time(at(4))) || cart.LastMessage != "Back-off restarting failed container" {
+		t.Errorf("Unexpected cart times %v to %v", cart.FirstSeen, cart.LastSeen)
+	}
+	if cart.Memory == nil || cart.Memory.Utilization() < 0.89 || cart.Memory.Utilization() > 0.91 {
+		t.Errorf("Unexpected cart memory %+v", cart.Memory)
+	}
+	if api.Workload != "api" || api.Crashes != 1 || api.Evictions != 1 || api.Memory == nil || api.Memory.Limit != 0 {
+		t.Errorf("Unexpected api incident %+v", api)
+	}
+
+	if len(client.eventRequests) != len(queries) {
+		t.Fatalf("Expected one event request per query, got %+v", client.eventRequests)
+	}
+	for i, request := range client.eventRequests {
+		conditions := request.Conditions
+		if len(conditions) != 2 || conditions[0].Key != types.ConditionKeyNamespace || conditions[1].Key != queries[i].key ||
+			conditions[1].Filters[0].Value != queries[i].value || !request.WithRawEvents || !request.Start.Equal(strfmt.DateTime(start)) {
+			t.Errorf("Unexpected event request %d %+v", i, request)
+		}
+	}
+	if len(client.workloadRequests) != 2 || client.workloadRequests[1].Skip != 2 || len(client.workloadRequests[0].Conditions) != 1 {
+		t.Errorf("Expected the workloads to be paged, got %+v", client.workloadRequests)
+	}
+
+	want := "*Workload incidents* 2026-10-18T12:00:00Z to 2026-10-18T13:00:00Z\n" +
+		"• *shop/cart*: 4 OOM kills, 1 back-off, 2026-10-18T11:40:00Z to 2026-10-18T12:04:00Z\n" +
+		"  pods: `cart-1`, `cart-2`\n" +
+		"  exit codes: 137 (OOMKilled, SIGKILL) ×4\n" +
+		"  memory: 1.8GiB of 2.0GiB (90%)\n" +
+		"  last: Back-off restarting failed container\n" +
+		"• *shop/api*: 1 crash, 1 eviction, 2026-10-18T12:02:00Z to 2026-10-18T12:05:00Z\n" +
+		"  pods: `api-1`, `api-2`\n" +
+		"  exit codes: 1 (Error) ×1\n" +
+		"  memory: 256.0MiB, no limit\n" +
+		"  last: The node was low on resource: memory.\n"
+	if got := report.Markdown(); got != want {
+		t.Errorf("Unexpected Markdown:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAnalyzeEmpty(t *testing.T) {
+	client := &fakeClient{workloadsErr: errors.New("unavailable")}
+	report, err := NewAnalyzer(client).Analyze(context.Background(), time.Time(at(0)), time.Time(at(30)))
+	if err != nil {
+		t.Fatalf("Expected workloads not to be listed without incidents, got %v", err)
+	}
+	if len(report.Incidents) != 0 || report.Markdown() != "*Workload incidents* 2026-10-18T12:00:00Z to 2026-10-18T12:30:00Z\nNo crashes, OOM kills, back-offs or evictions.\n" {
+		t.Errorf("Unexpected report %q", report.Markdown())
+	}
+
+	client = fixture()
+	client.workloadsErr = errors.New("unavailable")
+	if _, err := NewAnalyzer(client).Analyze(context.Background(), time.Time(at(0)), time.Time(at(30))); err == nil || err.Error() != "error listing workloads from 0: unavailable" {
+		t.Errorf("Expected the workloads error, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package incidents
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/quantity"
+)
+
+// maxPods is the number of pods listed per incident by Markdown.
+const maxPods = 5
+
+// Markdown renders the report as Markdown for posting to chat: one bullet
+// per incident with its counts, time span, pods and memory.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*Workload incidents* %s to %s\n", r.Start.UTC().Format(time.RFC3339), r.End.UTC().Format(time.RFC3339))
+	if len(r.Incidents) == 0 {
+		b.WriteString("No crashes, OOM kills, back-offs or evictions.\n")
+		return b.String()
+	}
+	for _, incident := range r.Incidents {
+		name := incident.Namespace + "/" + incident.Workload
+		if incident.Cluster != "" {
+			name = incident.Cluster + "/" + name
+		}
+		fmt.Fprintf(&b, "• *%s*: %s, %s to %s\n", name, incident.counts(),
+			incident.FirstSeen.UTC().Format(time.RFC3339), incident.LastSeen.UTC().Format(time.RFC3339))
+		if len(incident.Pods) > 0 {
+			pods := incident.Pods[:min(len(incident.Pods), maxPods)]
+			more := ""
+			if len(incident.Pods) > maxPods {
+				more = fmt.Sprintf(" and %d more", len(incident.Pods)-maxPods)
+			}
+			fmt.Fprintf(&b, "  pods: `%s`%s\n", strings.Join(pods, "`, `"), more)
+		}
+		if len(incident.Exits) > 0 {
+			exits := make([]string, len(incident.Exits))
+			for i, exit := range incident.Exits {
+				exits[i] = fmt.Sprintf("%s ×%d", exit.Exit, exit.Count)
+			}
+			fmt.Fprintf(&b, "  exit codes: %s\n", strings.Join(exits, ", "))
+		}
+		if incident.Memory != nil {
+			fmt.Fprintf(&b, "  memory: %s\n", incident.Memory)
+		}
+		if incident.LastMessage != "" {
+			fmt.Fprintf(&b, "  last: %s\n", incident.LastMessage)
+		}
+	}
+	return b.String()
+}
+
+// counts renders the non-zero counts of an incident, e.g. "3 OOM kills, 1
+// back-off".
+func (i *Incident) counts() string {
+	var parts []string
+	for _, count := range []struct {
+		n                int
+		singular, plural string
+	}{
+		{i.OOMKills, "OOM kill", "OOM kills"},
+		{i.Crashes, "crash", "crashes"},
+		{i.BackOffs, "back-off", "back-offs"},
+		{i.Evictions, "eviction", "evictions"},
+	} {
+		switch {
+		case count.n == 1:
+			parts = append(parts, "1 "+count.singular)
+		case count.n > 1:
+			parts = append(parts, fmt.Sprintf("%d %s", count.n, count.plural))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// String renders the memory, e.g. "1.8GiB of 2.0GiB (90%)".
+func (m Memory) String() string {
+	if m.Limit <= 0 {
+		return quantity.Bytes(m.Usage) + ", no limit"
+	}
+	return fmt.Sprintf("%s of %s (%.0f%%)", quantity.Bytes(m.Usage), quantity.Bytes(m.Limit), m.Utilization()*100)
+}
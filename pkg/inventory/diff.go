@@ -0,0 +1,159 @@
+package inventory
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/quantity"
+)
+
+// Fields of a workload compared by Compare.
+const (
+	FieldPods        = "pods"
+	FieldReady       = "ready"
+	FieldCPULimit    = "cpuLimit"
+	FieldMemoryLimit = "memoryLimit"
+	// FieldUID marks a workload that was deleted and created again under
+	// the same name.
+	FieldUID = "uid"
+)
+
+// Change is a workload present in both compared snapshots whose state
+// changed.
+type Change struct {
+	Key Key       `json:"key"`
+	Old *Workload `json:"old"`
+	New *Workload `json:"new"`
+	// Fields are the changed fields, in the order of the Field constants.
+	Fields []string `json:"fields"`
+}
+
+// PodsDelta returns the change of the pod count.
+func (c Change) PodsDelta() int {
+	return int(c.New.Pods) - int(c.Old.Pods)
+}
+
+// SnapshotDiff is the difference between two snapshots.
+type SnapshotDiff struct {
+	From    time.Time   `json:"from"`
+	To      time.Time   `json:"to"`
+	Added   []*Workload `json:"added"`
+	Removed []*Workload `json:"removed"`
+	Changed []Change    `json:"changed"`
+}
+
+// Empty reports whether the snapshots have the same workloads in the same
+// state.
+func (d *SnapshotDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Compare returns the difference from old to new. Workloads are matched by
+// key. A nil snapshot has no workloads.
+func Compare(old, new *Snapshot) *SnapshotDiff {
+	if old == nil {
+		old = &Snapshot{}
+	}
+	if new == nil {
+		new = &Snapshot{}
+	}
+	diff := &SnapshotDiff{From: old.TakenAt, To: new.TakenAt, Added: []*Workload{}, Removed: []*Workload{}, Changed: []Change{}}
+
+	oldWorkloads := map[Key]*Workload{}
+	for _, workload := range old.Workloads {
+		oldWorkloads[workload.Key()] = workload
+	}
+	newWorkloads := map[Key]*Workload{}
+	for _, workload := range new.Workloads {
+		newWorkloads[workload.Key()] = workload
+		previous := oldWorkloads[workload.Key()]
+		if previous == nil {
+			diff.Added = append(diff.Added, workload)
+			continue
+		}
+		if fields := changedFields(previous, workload); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, Change{Key: workload.Key(), Old: previous, New: workload, Fields: fields})
+		}
+	}
+	for _, workload := range old.Workloads {
+		if newWorkloads[workload.Key()] == nil {
+			diff.Removed = append(diff.Removed, workload)
+		}
+	}
+	return diff
+}
+
+func changedFields(old, new *Workload) []string {
+	var fields []string
+	if old.Pods != new.Pods {
+		fields = append(fields, FieldPods)
+	}
+	if old.Ready != new.Ready {
+		fields = append(fields, FieldReady)
+	}
+	if old.CPULimit != new.CPULimit {
+		fields = append(fields, FieldCPULimit)
+	}
+	if old.MemoryLimit != new.MemoryLimit {
+		fields = append(fields, FieldMemoryLimit)
+	}
+	// Snapshots written without UIDs do not mark recreated workloads.
+	if old.UID != "" && new.UID != "" && old.UID != new.UID {
+		fields = append(fields, FieldUID)
+	}
+	return fields
+}
+
+// String renders the diff with a line per workload: "+ KEY" for added
+// workloads, "- KEY" for removed ones and "~ KEY" for changed ones, followed
+// by their changed fields as "old -> new".
+func (d *SnapshotDiff) String() string {
+	var b strings.Builder
+	for _, workload := range d.Added {
+		fmt.Fprintf(&b, "+ %s (pods %d, %s)\n", workload.Key(), workload.Pods, readiness(workload.Ready))
+	}
+	for _, workload := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", workload.Key())
+	}
+	for _, change := range d.Changed {
+		var fields []string
+		for _, field := range change.Fields {
+			switch field {
+			case FieldPods:
+				fields = append(fields, fmt.Sprintf("pods %d -> %d", change.Old.Pods, change.New.Pods))
+			case FieldReady:
+				fields = append(fields, fmt.Sprintf("%s -> %s", readiness(change.Old.Ready), readiness(change.New.Ready)))
+			case FieldCPULimit:
+				fields = append(fields, fmt.Sprintf("cpu limit %s -> %s", cpuLimit(change.Old.CPULimit), cpuLimit(change.New.CPULimit)))
+			case FieldMemoryLimit:
+				fields = append(fields, fmt.Sprintf("memory limit %s -> %s", memoryLimit(change.Old.MemoryLimit), memoryLimit(change.New.MemoryLimit)))
+			case FieldUID:
+				fields = append(fields, "recreated")
+			}
+		}
+		fmt.Fprintf(&b, "~ %s: %s\n", change.Key, strings.Join(fields, ", "))
+	}
+	return b.String()
+}
+
+func readiness(ready bool) string {
+	if ready {
+		return "ready"
+	}
+	return "not ready"
+}
+
+func cpuLimit(millicores float64) string {
+	if millicores <= 0 {
+		return "none"
+	}
+	return quantity.CPU(millicores)
+}
+
+func memoryLimit(bytes float64) string {
+	if bytes <= 0 {
+		return "none"
+	}
+	return quantity.Memory(bytes)
+}
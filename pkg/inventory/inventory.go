@@ -0,0 +1,261 @@
+// Package inventory takes snapshots of the workloads of the fleet and
+// reports how they drifted between two snapshots.
+//
+// A Snapshotter pages through WorkloadsList in a stable order and records
+// each workload's kind, resource version, pod count, readiness and limits.
+// Snapshots serialize to JSON, so one taken on Monday can be compared with
+// one taken today:
+//
+//	snapshotter := inventory.NewSnapshotter(client.K8s, inventory.WithNamespace("shop"))
+//	snapshot, err := snapshotter.Take(ctx)
+//	if err != nil {
+//		...
+//	}
+//	monday, err := inventory.ReadSnapshot(file)
+//	if err != nil {
+//		...
+//	}
+//	diff := inventory.Compare(monday, snapshot)
+//	if !diff.Empty() {
+//		fmt.Print(diff)
+//	}
+package inventory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/workloads"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/types"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/utils"
+)
+
+// Default WorkloadsList sorting of a Snapshotter. Paging needs an order that
+// does not change between requests, which the default sorting by RPS does
+// not provide.
+const (
+	DefaultSortBy = "workload"
+	DefaultOrder  = "asc"
+)
+
+// Key identifies a workload across snapshots.
+type Key struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name"`
+}
+
+// String renders the key as cluster/namespace/kind/name, with the kind in
+// lower case as kubectl does and without the cluster when it is unknown.
+func (k Key) String() string {
+	name := k.Name
+	if k.Kind != "" {
+		name = strings.ToLower(k.Kind) + "/" + name
+	}
+	name = k.Namespace + "/" + name
+	if k.Cluster != "" {
+		name = k.Cluster + "/" + name
+	}
+	return name
+}
+
+func (k Key) compare(other Key) int {
+	return cmp.Or(
+		cmp.Compare(k.Cluster, other.Cluster),
+		cmp.Compare(k.Namespace, other.Namespace),
+		cmp.Compare(k.Name, other.Name),
+		cmp.Compare(k.Kind, other.Kind),
+	)
+}
+
+// Workload is the state of a workload in a snapshot. CPU is in millicores
+// and memory in bytes.
+type Workload struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Env       string `json:"env,omitempty"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind,omitempty"`
+	UID       string `json:"uid,omitempty"`
+	// ResourceVersion is recorded but not compared: it changes with every
+	// status update of the workload.
+	ResourceVersion int64   `json:"resourceVersion,omitempty"`
+	Pods            uint32  `json:"pods"`
+	Ready           bool    `json:"ready"`
+	CPULimit        float64 `json:"cpuLimit,omitempty"`
+	MemoryLimit     float64 `json:"memoryLimit,omitempty"`
+}
+
+// NewWorkload returns the state of a WorkloadsList item.
+func NewWorkload(item *models.WorkloadsListItem) *Workload {
+	return &Workload{
+		Cluster:         item.Cluster,
+		Env:             item.Env,
+		Namespace:       item.Namespace,
+		Name:            item.Workload,
+		Kind:            item.Kind,
+		UID:             item.UID,
+		ResourceVersion: item.ResourceVersion,
+		Pods:            item.PodsCount,
+		Ready:           item.Ready,
+		CPULimit:        item.CPULimit,
+		MemoryLimit:     item.MemoryLimit,
+	}
+}
+
+// Key returns the key of the workload.
+func (w *Workload) Key() Key {
+	return Key{Cluster: w.Cluster, Namespace: w.Namespace, Kind: w.Kind, Name: w.Name}
+}
+
+// Snapshot is the inventory of workloads at a point in time.
+type Snapshot struct {
+	TakenAt time.Time `json:"takenAt"`
+	// Workloads are sorted by cluster, namespace, name and kind, with one
+	// workload per key.
+	Workloads []*Workload `json:"workloads"`
+}
+
+// NewSnapshot returns the snapshot of WorkloadsList items taken at takenAt.
+func NewSnapshot(items []*models.WorkloadsListItem, takenAt time.Time) *Snapshot {
+	list := make([]*Workload, 0, len(items))
+	for _, item := range items {
+		list = append(list, NewWorkload(item))
+	}
+	return newSnapshot(list, takenAt)
+}
+
+// newSnapshot sorts list by key into a snapshot. Of workloads with the same
+// key, which a listing can return twice when workloads change while it
+// pages, the one with the highest resource version is kept.
+func newSnapshot(list []*Workload, takenAt time.Time) *Snapshot {
+	byKey := map[Key]*Workload{}
+	for _, workload := range list {
+		if seen := byKey[workload.Key()]; seen != nil && seen.ResourceVersion > workload.ResourceVersion {
+			continue
+		}
+		byKey[workload.Key()] = workload
+	}
+	snapshot := &Snapshot{TakenAt: takenAt, Workloads: make([]*Workload, 0, len(byKey))}
+	for _, workload := range byKey {
+		snapshot.Workloads = append(snapshot.Workloads, workload)
+	}
+	slices.SortFunc(snapshot.Workloads, func(a, b *Workload) int {
+		return a.Key().compare(b.Key())
+	})
+	return snapshot
+}
+
+// Write writes the snapshot as indented JSON.
+func (s *Snapshot) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(s); err != nil {
+		return fmt.Errorf("error writing snapshot: %w", err)
+	}
+	return nil
+}
+
+// ReadSnapshot reads a snapshot written by Write.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("error reading snapshot: %w", err)
+	}
+	// Snapshots may have been edited or written by another tool.
+	list := slices.DeleteFunc(snapshot.Workloads, func(w *Workload) bool { return w == nil })
+	return newSnapshot(list, snapshot.TakenAt), nil
+}
+
+// Snapshotter takes snapshots of the workloads listed through WorkloadsList.
+type Snapshotter struct {
+	workloads workloads.Lister
+	namespace string
+	sources   []*models.Condition
+	sortBy    string
+	order     string
+	pageSize  uint32
+	now       func() time.Time
+}
+
+// Option customizes a Snapshotter.
+type Option func(*Snapshotter)
+
+// WithNamespace restricts snapshots to a namespace.
+func WithNamespace(namespace string) Option {
+	return func(s *Snapshotter) {
+		s.namespace = namespace
+	}
+}
+
+// WithSources restricts snapshots to sources such as a cluster. The
+// conditions are sent as the WorkloadsList Sources.
+func WithSources(sources *utils.ConditionSet) Option {
+	return func(s *Snapshotter) {
+		s.sources = sources.Build()
+	}
+}
+
+// WithSortBy sets the WorkloadsList sorting used for paging. Defaults to
+// DefaultSortBy and DefaultOrder.
+func WithSortBy(sortBy, order string) Option {
+	return func(s *Snapshotter) {
+		s.sortBy, s.order = sortBy, order
+	}
+}
+
+// WithPageSize sets the number of workloads fetched per request. Defaults
+// to 500.
+func WithPageSize(size uint32) Option {
+	return func(s *Snapshotter) {
+		s.pageSize = size
+	}
+}
+
+// NewSnapshotter creates a Snapshotter listing workloads through k8s.
+func NewSnapshotter(k8s workloads.Lister, options ...Option) *Snapshotter {
+	s := &Snapshotter{
+		workloads: k8s,
+		sources:   []*models.Condition{},
+		sortBy:    DefaultSortBy,
+		order:     DefaultOrder,
+		pageSize:  workloads.DefaultPageSize,
+		now:       time.Now,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Take lists all workloads and returns their snapshot.
+func (s *Snapshotter) Take(ctx context.Context) (*Snapshot, error) {
+	takenAt := s.now().UTC()
+	conditions := utils.NewConditionSet()
+	if s.namespace != "" {
+		conditions.Add(types.ConditionKeyNamespace, s.namespace)
+	}
+	request := &models.WorkloadsListRequest{
+		Conditions: conditions.Build(),
+		Sources:    s.sources,
+		Limit:      s.pageSize,
+		SortBy:     s.sortBy,
+		Order:      s.order,
+	}
+	var items []*models.WorkloadsListItem
+	for workload, err := range workloads.All(ctx, s.workloads, request) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, workload)
+	}
+	return NewSnapshot(items, takenAt), nil
+}
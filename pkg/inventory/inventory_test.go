@@ -0,0 +1,117 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+type fakeLister struct {
+	workloads []*models.WorkloadsListItem
+	err       error
+	requests  []models.WorkloadsListRequest
+}
+
+func (l *fakeLister) Workloads(ctx context.Context, request *models.WorkloadsListRequest) (*models.WorkloadsListResponse, error) {
+	l.requests = append(l.requests, *request)
+	if l.err != nil {
+		return nil, l.err
+	}
+	start := min(int(request.Skip), len(l.workloads))
+	end := min(start+int(request.Limit), len(l.workloads))
+	return &models.WorkloadsListResponse{Workloads: l.workloads[start:end], Total: uint32(len(l.workloads))}, nil
+}
+
+func TestTake(t *testing.T) {
+	lister := &fakeLister{workloads: []*models.WorkloadsListItem{
+		{Cluster: "prod", Namespace: "shop", Workload: "cart", Kind: "Deployment", UID: "u1", ResourceVersion: 10, PodsCount: 3, Ready: true, CPULimit: 500, MemoryLimit: 256 << 20, CPUUsage: 120},
+		{Cluster: "prod", Namespace: "shop", Workload: "api", Kind: "Deployment", ResourceVersion: 4, PodsCount: 2, Ready: true},
+		// Listed twice while paging, the newer one wins.
+		{Cluster: "prod", Namespace: "shop", Workload: "cart", Kind: "Deployment", UID: "u1", ResourceVersion: 12, PodsCount: 4, Ready: true, CPULimit: 500, MemoryLimit: 256 << 20},
+		{Cluster: "prod", Namespace: "shop", Workload: "api", Kind: "StatefulSet", PodsCount: 1},
+	}}
+	snapshotter := NewSnapshotter(lister, WithNamespace("shop"), WithPageSize(2))
+	snapshotter.now = func() time.Time { return time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC) }
+	snapshot, err := snapshotter.Take(context.Background())
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if len(lister.requests) != 2 || lister.requests[1].Skip != 2 || lister.requests[0].Limit != 2 ||
+		lister.requests[0].SortBy != DefaultSortBy || lister.requests[0].Order != DefaultOrder || len(lister.requests[0].Conditions) != 1 {
+		t.Errorf("Unexpected requests %+v", lister.requests)
+	}
+
+	var keys []string
+	for _, workload := range snapshot.Workloads {
+		keys = append(keys, workload.Key().String())
+	}
+	if got := strings.Join(keys, " "); got != "prod/shop/deployment/api prod/shop/statefulset/api prod/shop/deployment/cart" {
+		t.Errorf("Unexpected workloads %s", got)
+	}
+	if cart := snapshot.Workloads[2]; cart.ResourceVersion != 12 || cart.Pods != 4 || cart.CPULimit != 500 {
+		t.Errorf("Unexpected cart %+v", cart)
+	}
+
+	var buf bytes.Buffer
+	if err := snapshot.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "cpuUsage") || !strings.Contains(buf.String(), `"takenAt": "2026-10-12T09:00:00Z"`) {
+		t.Errorf("Unexpected JSON %s", buf.String())
+	}
+	read, err := ReadSnapshot(&buf)
+	if err != nil || !read.TakenAt.Equal(snapshot.TakenAt) || len(read.Workloads) != 3 || *read.Workloads[2] != *snapshot.Workloads[2] {
+		t.Errorf("Unexpected snapshot %+v: %v", read, err)
+	}
+	if _, err := ReadSnapshot(strings.NewReader("{")); err == nil || !strings.HasPrefix(err.Error(), "error reading snapshot: ") {
+		t.Errorf("Expected a read error, got %v", err)
+	}
+
+	if _, err := NewSnapshotter(&fakeLister{err: errors.New("unavailable")}).Take(context.Background()); err == nil || err.Error() != "error listing workloads from 0: unavailable" {
+		t.Errorf("Expected the listing error, got %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	monday := NewSnapshot([]*models.WorkloadsListItem{
+		{Cluster: "prod", Namespace: "shop", Workload: "cart", Kind: "Deployment", UID: "u1", ResourceVersion: 10, PodsCount: 3, Ready: true, CPULimit: 500, MemoryLimit: 256 << 20},
+		{Cluster: "prod", Namespace: "shop", Workload: "legacy", Kind: "Deployment", PodsCount: 1, Ready: true},
+		{Cluster: "prod", Namespace: "shop", Workload: "db", Kind: "StatefulSet", UID: "u2", PodsCount: 1, Ready: true},
+		{Cluster: "prod", Namespace: "shop", Workload: "api", Kind: "Deployment", ResourceVersion: 4, PodsCount: 2, Ready: true},
+	}, time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC))
+	today := NewSnapshot([]*models.WorkloadsListItem{
+		{Cluster: "prod", Namespace: "shop", Workload: "cart", Kind: "Deployment", UID: "u1", ResourceVersion: 20, PodsCount: 5, Ready: false, CPULimit: 1000, MemoryLimit: 512 << 20},
+		{Cluster: "prod", Namespace: "shop", Workload: "db", Kind: "StatefulSet", UID: "u3", PodsCount: 1, Ready: true},
+		// Only the resource version changed.
+		{Cluster: "prod", Namespace: "shop", Workload: "api", Kind: "Deployment", ResourceVersion: 9, PodsCount: 2, Ready: true},
+		{Cluster: "prod", Namespace: "shop", Workload: "search", Kind: "Deployment", PodsCount: 2},
+	}, time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC))
+
+	diff := Compare(monday, today)
+	if diff.Empty() || len(diff.Added) != 1 || len(diff.Removed) != 1 || len(diff.Changed) != 2 {
+		t.Fatalf("Unexpected diff %+v", diff)
+	}
+	if cart := diff.Changed[0]; strings.Join(cart.Fields, " ") != "pods ready cpuLimit memoryLimit" || cart.PodsDelta() != 2 {
+		t.Errorf("Unexpected cart change %+v", cart)
+	}
+	want := `+ prod/shop/deployment/search (pods 2, not ready)
+- prod/shop/deployment/legacy
+~ prod/shop/deployment/cart: pods 3 -> 5, ready -> not ready, cpu limit 500m -> 1, memory limit 256Mi -> 512Mi
+~ prod/shop/statefulset/db: recreated
+`
+	if got := diff.String(); got != want {
+		t.Errorf("Unexpected diff:\n%s", got)
+	}
+
+	if diff := Compare(today, today); !diff.Empty() || diff.String() != "" {
+		t.Errorf("Expected no changes, got %s", diff)
+	}
+	if diff := Compare(nil, today); len(diff.Added) != 4 || !diff.From.IsZero() {
+		t.Errorf("Expected all workloads added, got %+v", diff)
+	}
+}
@@ -0,0 +1,279 @@
+package k8sevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// ErrNoRaw is returned by Decode for events fetched without WithRawEvents.
+var ErrNoRaw = errors.New("event has no raw data")
+
+// Event is a Kubernetes event as returned in EventsOverTimeResponse.Raw. It
+// follows the core/v1 Event; events.k8s.io/v1 events are decoded into the
+// same fields.
+type Event struct {
+	Metadata            ObjectMeta       `json:"metadata"`
+	InvolvedObject      ObjectReference  `json:"involvedObject"`
+	Related             *ObjectReference `json:"related,omitempty"`
+	Type                string           `json:"type,omitempty"`
+	Reason              string           `json:"reason,omitempty"`
+	Message             string           `json:"message,omitempty"`
+	Action              string           `json:"action,omitempty"`
+	Count               int32            `json:"count,omitempty"`
+	Source              EventSource      `json:"source"`
+	FirstTimestamp      Time             `json:"firstTimestamp"`
+	LastTimestamp       Time             `json:"lastTimestamp"`
+	EventTime           Time             `json:"eventTime"`
+	Series              *EventSeries     `json:"series,omitempty"`
+	ReportingController string           `json:"reportingComponent,omitempty"`
+	ReportingInstance   string           `json:"reportingInstance,omitempty"`
+
+	// Exit is the exit of the container of a crash event, from the
+	// response ExitCode.
+	Exit *Exit `json:"exit,omitempty"`
+}
+
+// ObjectMeta is the metadata of an Event.
+type ObjectMeta struct {
+	Name              string `json:"name,omitempty"`
+	Namespace         string `json:"namespace,omitempty"`
+	UID               string `json:"uid,omitempty"`
+	ResourceVersion   string `json:"resourceVersion,omitempty"`
+	CreationTimestamp Time   `json:"creationTimestamp"`
+}
+
+// ObjectReference identifies the object an Event is about.
+type ObjectReference struct {
+	Kind            string `json:"kind,omitempty"`
+	Namespace       string `json:"namespace,omitempty"`
+	Name            string `json:"name,omitempty"`
+	UID             string `json:"uid,omitempty"`
+	APIVersion      string `json:"apiVersion,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+	FieldPath       string `json:"fieldPath,omitempty"`
+}
+
+// Container returns the name of the container the reference points into,
+// e.g. "app" for the field path "spec.containers{app}", or "" if it does not
+// point into a container.
+func (r ObjectReference) Container() string {
+	for _, prefix := range []string{"spec.containers{", "spec.initContainers{", "spec.ephemeralContainers{"} {
+		if name, ok := strings.CutPrefix(r.FieldPath, prefix); ok {
+			if name, _, ok := strings.Cut(name, "}"); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// EventSource is the component that reported an Event.
+type EventSource struct {
+	Component string `json:"component,omitempty"`
+	Host      string `json:"host,omitempty"`
+}
+
+// EventSeries records the recurrences of an Event reported as a series.
+type EventSeries struct {
+	Count            int32 `json:"count,omitempty"`
+	LastObservedTime Time  `json:"lastObservedTime"`
+}
+
+// Time is a timestamp of an Event. Unlike time.Time, it decodes an empty
+// string or null as the zero time, which exporters write for unset
+// timestamps.
+type Time struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes an RFC 3339 timestamp, "" or null.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" || string(data) == `""` {
+		t.Time = time.Time{}
+		return nil
+	}
+	return t.Time.UnmarshalJSON(data)
+}
+
+// FirstSeen returns the time the event first occurred.
+func (e *Event) FirstSeen() time.Time {
+	if !e.FirstTimestamp.IsZero() {
+		return e.FirstTimestamp.Time
+	}
+	return e.EventTime.Time
+}
+
+// LastSeen returns the time the event last occurred.
+func (e *Event) LastSeen() time.Time {
+	if e.Series != nil && !e.Series.LastObservedTime.IsZero() {
+		return e.Series.LastObservedTime.Time
+	}
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	return e.FirstSeen()
+}
+
+// Occurrences returns the number of times the event occurred, at least 1.
+func (e *Event) Occurrences() int32 {
+	if e.Series != nil && e.Series.Count > 0 {
+		return e.Series.Count
+	}
+	return max(e.Count, 1)
+}
+
+// eventV1 holds the events.k8s.io/v1 names of Event fields.
+type eventV1 struct {
+	Event
+	Regarding                *ObjectReference `json:"regarding"`
+	Note                     string           `json:"note"`
+	DeprecatedSource         *EventSource     `json:"deprecatedSource"`
+	DeprecatedFirstTimestamp Time             `json:"deprecatedFirstTimestamp"`
+	DeprecatedLastTimestamp  Time             `json:"deprecatedLastTimestamp"`
+	DeprecatedCount          int32            `json:"deprecatedCount"`
+	ReportingControllerV1    string           `json:"reportingController"`
+	// LastSeen is written instead of lastTimestamp by some exporters.
+	LastSeenTime Time `json:"lastSeen"`
+}
+
+// DecodeRaw decodes a raw Kubernetes event.
+func DecodeRaw(raw string) (*Event, error) {
+	var decoded eventV1
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return nil, fmt.Errorf("error decoding raw event: %w", err)
+	}
+	event := decoded.Event
+	if decoded.Regarding != nil && event.InvolvedObject == (ObjectReference{}) {
+		event.InvolvedObject = *decoded.Regarding
+	}
+	if event.Message == "" {
+		event.Message = decoded.Note
+	}
+	if decoded.DeprecatedSource != nil && event.Source == (EventSource{}) {
+		event.Source = *decoded.DeprecatedSource
+	}
+	if event.FirstTimestamp.IsZero() {
+		event.FirstTimestamp = decoded.DeprecatedFirstTimestamp
+	}
+	if event.LastTimestamp.IsZero() {
+		event.LastTimestamp = decoded.DeprecatedLastTimestamp
+	}
+	if event.LastTimestamp.IsZero() {
+		event.LastTimestamp = decoded.LastSeenTime
+	}
+	if event.Count == 0 {
+		event.Count = decoded.DeprecatedCount
+	}
+	if event.ReportingController == "" {
+		event.ReportingController = decoded.ReportingControllerV1
+	}
+	return &event, nil
+}
+
+// Decode decodes the raw Kubernetes event of a response and interprets its
+// exit code. An exit code that does not parse is unknown and leaves Exit
+// nil. It returns ErrNoRaw if the event was fetched without raw data.
+func Decode(event *models.EventsOverTimeResponse) (*Event, error) {
+	if event.Raw == "" {
+		return nil, ErrNoRaw
+	}
+	decoded, err := DecodeRaw(event.Raw)
+	if err != nil {
+		return nil, err
+	}
+	decoded.Exit, _ = ParseExitCode(event.ExitCode)
+	return decoded, nil
+}
+
+// Reasons of a container exit.
+const (
+	ExitCompleted       = "Completed"
+	ExitError           = "Error"
+	ExitCannotExecute   = "CannotExecute"
+	ExitCommandNotFound = "CommandNotFound"
+	ExitOOMKilled       = "OOMKilled"
+	ExitTerminated      = "Terminated"
+	ExitSignaled        = "Signaled"
+)
+
+// signals are the names of the Linux signals, by number.
+var signals = map[int]string{
+	1: "SIGHUP", 2: "SIGINT", 3: "SIGQUIT", 4: "SIGILL", 5: "SIGTRAP", 6: "SIGABRT", 7: "SIGBUS",
+	8: "SIGFPE", 9: "SIGKILL", 10: "SIGUSR1", 11: "SIGSEGV", 12: "SIGUSR2", 13: "SIGPIPE",
+	14: "SIGALRM", 15: "SIGTERM", 24: "SIGXCPU", 25: "SIGXFSZ", 31: "SIGSYS",
+}
+
+// Exit is the interpreted exit code of a container.
+type Exit struct {
+	Code int `json:"code"`
+	// Signal is the name of the signal that killed the container, for codes
+	// above 128.
+	Signal string `json:"signal,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// InterpretExitCode interprets the exit code of a container. Codes above 128
+// are deaths by signal 128 less the code. 137, SIGKILL, is reported as
+// ExitOOMKilled since in Kubernetes it almost always comes from the OOM
+// killer, though it may also be a kill after the termination grace period.
+// 143, SIGTERM, is ExitTerminated.
+func InterpretExitCode(code int) Exit {
+	exit := Exit{Code: code, Reason: ExitError}
+	switch {
+	case code == 0:
+		exit.Reason = ExitCompleted
+	case code == 126:
+		exit.Reason = ExitCannotExecute
+	case code == 127:
+		exit.Reason = ExitCommandNotFound
+	case code > 128 && code < 160:
+		exit.Signal = signals[code-128]
+		if exit.Signal == "" {
+			exit.Signal = "signal " + strconv.Itoa(code-128)
+		}
+		switch code - 128 {
+		case 9:
+			exit.Reason = ExitOOMKilled
+		case 15:
+			exit.Reason = ExitTerminated
+		default:
+			exit.Reason = ExitSignaled
+		}
+	}
+	return exit
+}
+
+// ParseExitCode interprets an exit code as returned in
+// EventsOverTimeResponse.ExitCode. It returns nil for an empty code.
+func ParseExitCode(code string) (*Exit, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing exit code %q: %w", code, err)
+	}
+	exit := InterpretExitCode(n)
+	return &exit, nil
+}
+
+// OOMKilled reports whether the container was likely killed for running out
+// of memory.
+func (e Exit) OOMKilled() bool {
+	return e.Reason == ExitOOMKilled
+}
+
+// String renders the exit as e.g. "137 (OOMKilled, SIGKILL)".
+func (e Exit) String() string {
+	if e.Signal == "" {
+		return fmt.Sprintf("%d (%s)", e.Code, e.Reason)
+	}
+	return fmt.Sprintf("%d (%s, %s)", e.Code, e.Reason, e.Signal)
+}
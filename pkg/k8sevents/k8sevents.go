@@ -0,0 +1,137 @@
+// Package k8sevents reads Kubernetes events through GetEventsOverTime.
+//
+// All pages through the events of a request until none are left:
+//
+//	request := &models.GetEventsOverTimeRequest{Start: &start, End: &end}
+//	for event, err := range k8sevents.All(ctx, client.K8s, request) {
+//		if err != nil {
+//			...
+//		}
+//		fmt.Println(event.Namespace, event.Reason, event.Message)
+//	}
+//
+// A Watcher polls forward in time and emits only events it has not emitted
+// before, like a Kubernetes watch:
+//
+//	watcher := k8sevents.NewWatcher(client.K8s)
+//	for event := range watcher.Watch(ctx) {
+//		...
+//	}
+//	if err := watcher.Err(); err != nil {
+//		...
+//	}
+//
+// Decode turns the raw Kubernetes event of a response fetched with raw
+// events into an Event, with the container exit code interpreted:
+//
+//	decoded, err := k8sevents.Decode(event)
+//	if err == nil && decoded.Exit != nil && decoded.Exit.OOMKilled() {
+//		fmt.Println(decoded.InvolvedObject.Name, decoded.InvolvedObject.Container(), decoded.Occurrences())
+//	}
+package k8sevents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// DefaultPageSize is the number of events fetched per request when the
+// request has no Limit.
+const DefaultPageSize = 500
+
+// Default sorting of requests without one.
+const (
+	DefaultSortBy    = "timestamp"
+	DefaultSortOrder = "asc"
+)
+
+// Lister fetches events over time. *groundcover.K8sService implements it.
+type Lister interface {
+	EventsOverTime(ctx context.Context, request *models.GetEventsOverTimeRequest) (*models.GetEventsOverTimeResponse, error)
+}
+
+// All returns the events of request, fetching them in pages of
+// request.Limit events, DefaultPageSize if unset, from request.Skip on.
+// Requests without sorting are sorted by DefaultSortBy and DefaultSortOrder.
+// Iteration stops at the first error, which is yielded.
+func All(ctx context.Context, lister Lister, request *models.GetEventsOverTimeRequest) iter.Seq2[*models.EventsOverTimeResponse, error] {
+	return func(yield func(*models.EventsOverTimeResponse, error) bool) {
+		page := *request
+		if page.Limit == 0 {
+			page.Limit = DefaultPageSize
+		}
+		if page.SortBy == nil {
+			sortBy := DefaultSortBy
+			page.SortBy = &sortBy
+		}
+		if page.SortOrder == nil {
+			sortOrder := DefaultSortOrder
+			page.SortOrder = &sortOrder
+		}
+		if page.Conditions == nil {
+			page.Conditions = []*models.Condition{}
+		}
+		if page.Sources == nil {
+			page.Sources = []*models.Condition{}
+		}
+
+		for {
+			request := page
+			resp, err := lister.EventsOverTime(ctx, &request)
+			if err != nil {
+				yield(nil, fmt.Errorf("error listing events from %d: %w", page.Skip, err))
+				return
+			}
+			for _, event := range resp.Events {
+				if event == nil {
+					continue
+				}
+				if !yield(event, nil) {
+					return
+				}
+			}
+			// A short page is the last, unless the API says the limit was
+			// reached.
+			if len(resp.Events) == 0 || (uint32(len(resp.Events)) < page.Limit && !resp.IsLimitReached) {
+				return
+			}
+			page.Skip += uint32(len(resp.Events))
+		}
+	}
+}
+
+// Key identifies an occurrence of an event: the event, by UID, or by the
+// object UID and reason when it has no UID, and the time it was last seen.
+// Kubernetes updates an event in place when it recurs, so each recurrence
+// has a new key.
+func Key(event *models.EventsOverTimeResponse) string {
+	id := event.UID
+	if id == "" && event.ObjectUID != "" {
+		id = event.ObjectUID + "/" + event.Reason
+	}
+	if id == "" {
+		data, _ := json.Marshal(event)
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}
+	return id + "@" + strconv.FormatInt(LastSeen(event).UnixNano(), 10)
+}
+
+// LastSeen returns the time the event last occurred: that of the raw
+// Kubernetes event when the response includes it, else the event timestamp.
+func LastSeen(event *models.EventsOverTimeResponse) time.Time {
+	if event.Raw != "" {
+		if decoded, err := DecodeRaw(event.Raw); err == nil && !decoded.LastSeen().IsZero() {
+			return decoded.LastSeen().UTC()
+		}
+	}
+	return time.Time(event.Timestamp).UTC()
+}
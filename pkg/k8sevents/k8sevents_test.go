@@ -0,0 +1,320 @@
+package k8sevents
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// pagedLister serves events by Skip and Limit and records the requests.
+type pagedLister struct {
+	events []*models.EventsOverTimeResponse
+	// limitReached is reported on short pages too.
+	limitReached bool
+	failAt       uint32
+	requests     []models.GetEventsOverTimeRequest
+}
+
+func (l *pagedLister) EventsOverTime(ctx context.Context, request *models.GetEventsOverTimeRequest) (*models.GetEventsOverTimeResponse, error) {
+	l.requests = append(l.requests, *request)
+	if l.failAt != 0 && request.Skip >= l.failAt {
+		return nil, errors.New("unavailable")
+	}
+	start := min(int(request.Skip), len(l.events))
+	end := min(start+int(request.Limit), len(l.events))
+	return &models.GetEventsOverTimeResponse{Events: l.events[start:end], IsLimitReached: l.limitReached && end > start}, nil
+}
+
+func event(uid, reason string) *models.EventsOverTimeResponse {
+	return &models.EventsOverTimeResponse{
+		UID:       uid,
+		ObjectUID: "pod-" + uid,
+		Reason:    reason,
+		Timestamp: strfmt.DateTime(time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)),
+	}
+}
+
+func uids(events []*models.EventsOverTimeResponse) []string {
+	var ids []string
+	for _, event := range events {
+		ids = append(ids, event.UID)
+	}
+	return ids
+}
+
+func TestAll(t *testing.T) {
+	lister := &pagedLister{events: []*models.EventsOverTimeResponse{
+		event("a", "Pulled"), event("b", "Started"), event("c", "BackOff"), event("d", "Killing"), event("e", "Pulled"),
+	}}
+	request := &models.GetEventsOverTimeRequest{Limit: 2}
+
+	var got []*models.EventsOverTimeResponse
+	for event, err := range All(context.Background(), lister, request) {
+		if err != nil {
+			t.Fatalf("All failed: %v", err)
+		}
+		got = append(got, event)
+	}
+	if ids := uids(got); !reflect.DeepEqual(ids, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("Expected all events, got %v", ids)
+	}
+	if len(lister.requests) != 3 || lister.requests[2].Skip != 4 || *lister.requests[0].SortBy != DefaultSortBy ||
+		*lister.requests[0].SortOrder != DefaultSortOrder || lister.requests[0].Conditions == nil {
+		t.Errorf("Unexpected requests %+v", lister.requests)
+	}
+	if request.Skip != 0 || request.SortBy != nil {
+		t.Errorf("Expected the request to be left unchanged, got %+v", request)
+	}
+
+	// A short page with the limit reached is not the last; an empty page is.
+	lister = &pagedLister{events: lister.events[:3], limitReached: true}
+	count := 0
+	for _, err := range All(context.Background(), lister, &models.GetEventsOverTimeRequest{Limit: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	if count != 3 || len(lister.requests) != 3 {
+		t.Errorf("Expected 3 events in 3 requests, got %d in %d", count, len(lister.requests))
+	}
+
+	// Breaking stops paging.
+	lister = &pagedLister{events: lister.events}
+	for range All(context.Background(), lister, &models.GetEventsOverTimeRequest{Limit: 2}) {
+		break
+	}
+	if len(lister.requests) != 1 {
+		t.Errorf("Expected a single request, got %d", len(lister.requests))
+	}
+
+	lister = &pagedLister{events: lister.events, failAt: 2}
+	var err error
+	count = 0
+	for _, err = range All(context.Background(), lister, &models.GetEventsOverTimeRequest{Limit: 2}) {
+		if err != nil {
+			break
+		}
+		count++
+	}
+	if count != 2 || err == nil || err.Error() != "error listing events from 2: unavailable" {
+		t.Errorf("Expected an error after 2 events, got %d events and %v", count, err)
+	}
+}
+
+func TestKey(t *testing.T) {
+	first := event("a", "BackOff")
+	first.Raw = `{"count":1,"lastTimestamp":"2026-10-18T12:00:00Z"}`
+	again := event("a", "BackOff")
+	again.Raw = `{"count":2,"lastTimestamp":"2026-10-18T12:00:30Z"}`
+
+	if !LastSeen(again).Equal(time.Date(2026, 10, 18, 12, 0, 30, 0, time.UTC)) {
+		t.Errorf("Expected the raw last timestamp, got %v", LastSeen(again))
+	}
+	if Key(first) == Key(again) {
+		t.Error("Expected a recurrence to have a new key")
+	}
+	// Some exporters write lastSeen and leave lastTimestamp empty.
+	exported := event("a", "BackOff")
+	exported.Raw = `{"count":2,"lastTimestamp":"","eventTime":"","lastSeen":"2026-10-18T12:00:30Z"}`
+	if !LastSeen(exported).Equal(LastSeen(again)) || Key(exported) != Key(again) {
+		t.Errorf("Expected the raw lastSeen, got %v", LastSeen(exported))
+	}
+	if Key(first) != Key(event("a", "BackOff")) {
+		t.Error("Expected the timestamp to be the last seen time of events without raw data")
+	}
+
+	noUID := event("", "OOMKilling")
+	noUID.ObjectUID = "node-1"
+	if key := Key(noUID); key != "node-1/OOMKilling@1792324800000000000" {
+		t.Errorf("Unexpected key %q", key)
+	}
+	anonymous := &models.EventsOverTimeResponse{Message: "x"}
+	if key := Key(anonymous); len(key) != 64 || key == Key(&models.EventsOverTimeResponse{Message: "y"}) {
+		t.Errorf("Expected a content hash, got %q", key)
+	}
+}
+
+// scriptedLister returns one list of events per poll, then nothing.
+type scriptedLister struct {
+	mu    sync.Mutex
+	polls [][]*models.EventsOverTimeResponse
+	err   error
+	calls int
+}
+
+func (l *scriptedLister) EventsOverTime(ctx context.Context, request *models.GetEventsOverTimeRequest) (*models.GetEventsOverTimeResponse, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls++
+	if len(l.polls) == 0 {
+		if l.err != nil {
+			return nil, l.err
+		}
+		return &models.GetEventsOverTimeResponse{}, nil
+	}
+	events := l.polls[0]
+	l.polls = l.polls[1:]
+	return &models.GetEventsOverTimeResponse{Events: events}, nil
+}
+
+func TestWatcher(t *testing.T) {
+	backOff := event("a", "BackOff")
+	backOff.Raw = `{"lastTimestamp":"2026-10-18T12:00:00Z"}`
+	recurred := event("a", "BackOff")
+	recurred.Raw = `{"lastTimestamp":"2026-10-18T12:00:30Z"}`
+
+	lister := &scriptedLister{
+		polls: [][]*models.EventsOverTimeResponse{
+			{backOff, event("b", "Started")},
+			{backOff, event("b", "Started"), recurred, event("c", "Killing")},
+		},
+		err: errors.New("unavailable"),
+	}
+	watcher := NewWatcher(lister, WithInterval(time.Millisecond))
+
+	var got []*models.EventsOverTimeResponse
+	for event := range watcher.Watch(context.Background()) {
+		got = append(got, event)
+	}
+	if len(got) != 4 || got[0] != backOff || got[1].UID != "b" || got[2] != recurred || got[3].UID != "c" {
+		t.Errorf("Expected each event once and the recurrence, got %v", uids(got))
+	}
+	if err := watcher.Err(); err == nil || err.Error() != "error listing events from 0: unavailable" {
+		t.Errorf("Expected the listing error, got %v", err)
+	}
+}
+
+func TestWatcherStops(t *testing.T) {
+	lister := &scriptedLister{}
+	watcher := NewWatcher(lister, WithInterval(time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	events := watcher.Watch(ctx)
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	for range events {
+	}
+	if err := watcher.Err(); err != nil {
+		t.Errorf("Expected no error after cancellation, got %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	crash := event("a", "BackOff")
+	crash.ExitCode = "137"
+	crash.Raw = `{
+		"metadata": {"name": "cart-1.17f", "namespace": "shop", "uid": "a", "creationTimestamp": "2026-10-18T11:50:00Z"},
+		"involvedObject": {"kind": "Pod", "namespace": "shop", "name": "cart-1", "uid": "pod-a", "apiVersion": "v1", "fieldPath": "spec.containers{app}"},
+		"reason": "BackOff",
+		"message": "Back-off restarting failed container app",
+		"source": {"component": "kubelet", "host": "node-1"},
+		"firstTimestamp": "2026-10-18T11:50:00Z",
+		"lastTimestamp": "2026-10-18T12:00:00Z",
+		"eventTime": null,
+		"count": 7,
+		"type": "Warning",
+		"reportingComponent": "kubelet",
+		"reportingInstance": "node-1"
+	}`
+	decoded, err := Decode(crash)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if decoded.InvolvedObject.Name != "cart-1" || decoded.InvolvedObject.Container() != "app" || decoded.Source.Host != "node-1" ||
+		decoded.Occurrences() != 7 || decoded.Metadata.UID != "a" || decoded.ReportingController != "kubelet" {
+		t.Errorf("Unexpected event %+v", decoded)
+	}
+	if !decoded.FirstSeen().Equal(time.Date(2026, 10, 18, 11, 50, 0, 0, time.UTC)) ||
+		!decoded.LastSeen().Equal(time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected times %v and %v", decoded.FirstSeen(), decoded.LastSeen())
+	}
+	if decoded.Exit == nil || !decoded.Exit.OOMKilled() || decoded.Exit.String() != "137 (OOMKilled, SIGKILL)" {
+		t.Errorf("Unexpected exit %v", decoded.Exit)
+	}
+
+	// events.k8s.io/v1 names are decoded into the same fields.
+	decoded, err = DecodeRaw(`{
+		"regarding": {"kind": "Node", "name": "node-1"},
+		"note": "System OOM encountered",
+		"reason": "SystemOOM",
+		"deprecatedSource": {"component": "kubelet"},
+		"deprecatedCount": 2,
+		"eventTime": "2026-10-18T11:59:00.123456Z",
+		"series": {"count": 3, "lastObservedTime": "2026-10-18T12:00:30.000001Z"},
+		"reportingController": "kubelet"
+	}`)
+	if err != nil {
+		t.Fatalf("DecodeRaw failed: %v", err)
+	}
+	if decoded.InvolvedObject.Kind != "Node" || decoded.Message != "System OOM encountered" || decoded.Source.Component != "kubelet" ||
+		decoded.Count != 2 || decoded.Occurrences() != 3 || decoded.ReportingController != "kubelet" || decoded.InvolvedObject.Container() != "" {
+		t.Errorf("Unexpected event %+v", decoded)
+	}
+	if !decoded.FirstSeen().Equal(time.Date(2026, 10, 18, 11, 59, 0, 123456000, time.UTC)) ||
+		!decoded.LastSeen().Equal(time.Date(2026, 10, 18, 12, 0, 30, 1000, time.UTC)) {
+		t.Errorf("Unexpected times %v and %v", decoded.FirstSeen(), decoded.LastSeen())
+	}
+
+	// Unset timestamps may be written as empty strings.
+	decoded, err = DecodeRaw(`{"reason": "Pulled", "metadata": {"creationTimestamp": ""}, "firstTimestamp": null, "lastTimestamp": "", "eventTime": "", "series": {"lastObservedTime": ""}}`)
+	if err != nil {
+		t.Fatalf("DecodeRaw failed: %v", err)
+	}
+	if decoded.Reason != "Pulled" || !decoded.FirstSeen().IsZero() || !decoded.LastSeen().IsZero() || !decoded.Metadata.CreationTimestamp.IsZero() {
+		t.Errorf("Expected zero times, got %+v", decoded)
+	}
+	if _, err := DecodeRaw(`{"lastTimestamp": "yesterday"}`); err == nil {
+		t.Error("Expected an error for an invalid timestamp")
+	}
+
+	if _, err := Decode(event("b", "Pulled")); !errors.Is(err, ErrNoRaw) {
+		t.Errorf("Expected ErrNoRaw, got %v", err)
+	}
+	broken := event("c", "Failed")
+	broken.Raw = `{"reason":`
+	if _, err := Decode(broken); err == nil {
+		t.Error("Expected an error for invalid raw data")
+	}
+	broken.Raw, broken.ExitCode = `{"reason": "Failed"}`, "oom"
+	if decoded, err := Decode(broken); err != nil || decoded.Reason != "Failed" || decoded.Exit != nil {
+		t.Errorf("Expected the event with an unknown exit, got %+v, %v", decoded, err)
+	}
+	if _, err := ParseExitCode("oom"); err == nil || err.Error() != `error parsing exit code "oom": strconv.Atoi: parsing "oom": invalid syntax` {
+		t.Errorf("Expected an exit code error, got %v", err)
+	}
+}
+
+func TestInterpretExitCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"0", "0 (Completed)"},
+		{"1", "1 (Error)"},
+		{"127", "127 (CommandNotFound)"},
+		{" 137 ", "137 (OOMKilled, SIGKILL)"},
+		{"139", "139 (Signaled, SIGSEGV)"},
+		{"143", "143 (Terminated, SIGTERM)"},
+		{"162", "162 (Error)"},
+		{"158", "158 (Signaled, signal 30)"},
+	}
+	for _, test := range tests {
+		exit, err := ParseExitCode(test.code)
+		if err != nil || exit.String() != test.want {
+			t.Errorf("ParseExitCode(%q) = %v, %v; want %s", test.code, exit, err, test.want)
+		}
+	}
+	if exit, err := ParseExitCode(""); exit != nil || err != nil {
+		t.Errorf("Expected no exit for an empty code, got %v, %v", exit, err)
+	}
+	if exit := InterpretExitCode(143); exit.OOMKilled() || exit.Signal != "SIGTERM" {
+		t.Errorf("Unexpected exit %+v", exit)
+	}
+}
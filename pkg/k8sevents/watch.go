@@ -0,0 +1,174 @@
+package k8sevents
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/poller"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/utils"
+)
+
+// Defaults of a Watcher.
+const (
+	DefaultInterval = 10 * time.Second
+	DefaultOverlap  = time.Minute
+	DefaultLookback = 5 * time.Minute
+)
+
+// Option customizes a Watcher.
+type Option func(*Watcher)
+
+// WithConditions filters the watched events, e.g. to a namespace or a
+// reason. The conditions are sent as the request Conditions.
+func WithConditions(conditions *utils.ConditionSet) Option {
+	return func(w *Watcher) {
+		w.conditions = conditions.Build()
+	}
+}
+
+// WithSources restricts the watched events to sources such as a cluster.
+// The conditions are sent as the request Sources.
+func WithSources(sources *utils.ConditionSet) Option {
+	return func(w *Watcher) {
+		w.sources = sources.Build()
+	}
+}
+
+// WithInterval sets the time between polls. Defaults to DefaultInterval.
+func WithInterval(interval time.Duration) Option {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+// WithOverlap sets how far each poll reaches back into the previous one to
+// cover ingestion lag. Defaults to DefaultOverlap.
+func WithOverlap(overlap time.Duration) Option {
+	return func(w *Watcher) {
+		w.overlap = overlap
+	}
+}
+
+// WithLookback sets how far before the start of Watch the first poll
+// begins. Defaults to DefaultLookback.
+func WithLookback(lookback time.Duration) Option {
+	return func(w *Watcher) {
+		w.lookback = lookback
+	}
+}
+
+// WithPageSize sets the number of events fetched per request. Defaults to
+// DefaultPageSize.
+func WithPageSize(size uint32) Option {
+	return func(w *Watcher) {
+		w.pageSize = size
+	}
+}
+
+// WithRawEvents includes the raw Kubernetes event in the emitted events,
+// which also gives the exact last seen time of recurring events.
+func WithRawEvents() Option {
+	return func(w *Watcher) {
+		w.rawEvents = true
+	}
+}
+
+// WithBufferSize sets the capacity of the channel returned by Watch.
+func WithBufferSize(size int) Option {
+	return func(w *Watcher) {
+		w.bufferSize = size
+	}
+}
+
+// Watcher emits new Kubernetes events as they occur.
+type Watcher struct {
+	lister     Lister
+	conditions []*models.Condition
+	sources    []*models.Condition
+	interval   time.Duration
+	overlap    time.Duration
+	lookback   time.Duration
+	pageSize   uint32
+	rawEvents  bool
+	bufferSize int
+	now        func() time.Time
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewWatcher creates a Watcher listing events through lister.
+func NewWatcher(lister Lister, options ...Option) *Watcher {
+	w := &Watcher{
+		lister:     lister,
+		conditions: []*models.Condition{},
+		sources:    []*models.Condition{},
+		interval:   DefaultInterval,
+		overlap:    DefaultOverlap,
+		lookback:   DefaultLookback,
+		pageSize:   DefaultPageSize,
+		now:        time.Now,
+	}
+	for _, option := range options {
+		option(w)
+	}
+	return w
+}
+
+// Watch starts polling and returns the channel of new events, in timestamp
+// order within each poll. An event is emitted again when it recurs, which
+// Kubernetes records by updating its last seen time; see Key. The channel
+// is closed when ctx is done or a request fails, after which Err reports
+// the failure.
+func (w *Watcher) Watch(ctx context.Context) <-chan *models.EventsOverTimeResponse {
+	events := make(chan *models.EventsOverTimeResponse, w.bufferSize)
+	go func() {
+		defer close(events)
+		w.setErr(w.watch(ctx, events))
+	}()
+	return events
+}
+
+// Err returns the error that stopped the watcher, or nil if it stopped
+// because its context was done.
+func (w *Watcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+func (w *Watcher) setErr(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.err = err
+}
+
+func (w *Watcher) watch(ctx context.Context, events chan<- *models.EventsOverTimeResponse) error {
+	p := &poller.Poller[*models.EventsOverTimeResponse]{
+		Interval: w.interval,
+		Overlap:  w.overlap,
+		Lookback: w.lookback,
+		Now:      w.now,
+		Key:      Key,
+		Poll: func(ctx context.Context, start, end time.Time) iter.Seq2[*models.EventsOverTimeResponse, error] {
+			startTime, endTime := strfmt.DateTime(start.UTC()), strfmt.DateTime(end.UTC())
+			sortBy, sortOrder := DefaultSortBy, DefaultSortOrder
+			return All(ctx, w.lister, &models.GetEventsOverTimeRequest{
+				Start:         &startTime,
+				End:           &endTime,
+				Conditions:    w.conditions,
+				Sources:       w.sources,
+				Limit:         w.pageSize,
+				SortBy:        &sortBy,
+				SortOrder:     &sortOrder,
+				WithRawEvents: w.rawEvents,
+			})
+		},
+	}
+	return p.Run(ctx, events)
+}
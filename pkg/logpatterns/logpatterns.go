@@ -0,0 +1,416 @@
+// Package logpatterns groups log lines into templates such as
+// "connection to <*> timed out after <*>ms", using the Drain algorithm
+// (He et al., "Drain: An Online Log Parsing Approach with Fixed Depth Tree").
+//
+// A Miner is fed log search results, or single messages, and can be fed more
+// at any time; clusters are merged incrementally as new lines arrive:
+//
+//	miner := logpatterns.NewMiner()
+//	result, err := gc.Logs.Search(ctx, request)
+//	...
+//	if err := miner.AddResults(result); err != nil {
+//		...
+//	}
+//	for _, pattern := range miner.Patterns() {
+//		fmt.Println(pattern.Count, pattern.Template)
+//	}
+//
+// Mining is deterministic: the same lines in the same order give the same
+// patterns, IDs and samples.
+package logpatterns
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/logrecord"
+)
+
+// Wildcard replaces the variable parts of a template.
+const Wildcard = "<*>"
+
+// Defaults of a Miner.
+const (
+	DefaultSimilarityThreshold = 0.4
+	DefaultDepth               = 4
+	DefaultMaxChildren         = 100
+	DefaultMaxSamples          = 3
+)
+
+// DefaultMasks replace variable values before clustering, in order: UUIDs,
+// IPv4 addresses with optional port, hexadecimal values of at least 8
+// digits or with a 0x prefix, and numbers, also within tokens ("250ms").
+var DefaultMasks = []*regexp.Regexp{
+	regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`),
+	regexp.MustCompile(`\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?`),
+	regexp.MustCompile(`\b0[xX][0-9a-fA-F]+\b|\b[0-9a-fA-F]{8,}\b`),
+	regexp.MustCompile(`\d+(?:\.\d+)?`),
+}
+
+// Pattern is a cluster of log lines sharing a template.
+type Pattern struct {
+	// ID identifies the pattern within its Miner, in order of creation.
+	ID int
+	// Template is the common form of the lines, with Wildcard for variable tokens.
+	Template string
+	// Count is the number of lines in the cluster.
+	Count int64
+	// FirstSeen and LastSeen are the earliest and latest record times, zero
+	// when no record had a timestamp.
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Samples are the first records of the cluster, up to the Miner's maximum.
+	Samples []map[string]interface{}
+}
+
+// Option customizes a Miner.
+type Option func(*Miner)
+
+// WithSimilarityThreshold sets the share of equal tokens, between 0 and 1,
+// for a line to join a cluster. Defaults to DefaultSimilarityThreshold.
+func WithSimilarityThreshold(threshold float64) Option {
+	return func(m *Miner) {
+		m.threshold = threshold
+	}
+}
+
+// WithDepth sets the depth of the prefix tree, which must be at least 3.
+// Lines are routed by their first depth-2 tokens. Defaults to DefaultDepth.
+func WithDepth(depth int) Option {
+	return func(m *Miner) {
+		m.depth = depth
+	}
+}
+
+// WithMaxChildren caps the children of a tree node; further tokens share a
+// wildcard child. Defaults to DefaultMaxChildren.
+func WithMaxChildren(maxChildren int) Option {
+	return func(m *Miner) {
+		m.maxChildren = maxChildren
+	}
+}
+
+// WithMaxSamples sets the number of sample records kept per pattern.
+// Defaults to DefaultMaxSamples.
+func WithMaxSamples(maxSamples int) Option {
+	return func(m *Miner) {
+		m.maxSamples = maxSamples
+	}
+}
+
+// WithMasks replaces the regular expressions whose matches are masked with
+// Wildcard before clustering. Defaults to DefaultMasks.
+func WithMasks(masks ...*regexp.Regexp) Option {
+	return func(m *Miner) {
+		m.masks = masks
+	}
+}
+
+// WithMessageFields sets the record fields searched, in order, for the log
+// line. Defaults to "content", "message", "msg", "body" and "log".
+func WithMessageFields(fields ...string) Option {
+	return func(m *Miner) {
+		m.messageFields = fields
+	}
+}
+
+// WithTimestampFields sets the record fields searched, in order, for the
+// record time. Defaults to "timestamp", "time" and "@timestamp".
+func WithTimestampFields(fields ...string) Option {
+	return func(m *Miner) {
+		m.timestampFields = fields
+	}
+}
+
+// Miner clusters log lines. It is not safe for concurrent use.
+type Miner struct {
+	threshold       float64
+	depth           int
+	maxChildren     int
+	maxSamples      int
+	masks           []*regexp.Regexp
+	messageFields   []string
+	timestampFields []string
+
+	root     *node
+	clusters []*cluster
+	skipped  int64
+}
+
+type cluster struct {
+	tokens []string
+	Pattern
+}
+
+// node is a node of the prefix tree. Leaves hold clusters.
+type node struct {
+	children map[string]*node
+	clusters []*cluster
+}
+
+func newNode() *node {
+	return &node{children: map[string]*node{}}
+}
+
+// NewMiner creates an empty Miner.
+func NewMiner(options ...Option) *Miner {
+	m := &Miner{
+		threshold:       DefaultSimilarityThreshold,
+		depth:           DefaultDepth,
+		maxChildren:     DefaultMaxChildren,
+		maxSamples:      DefaultMaxSamples,
+		masks:           DefaultMasks,
+		messageFields:   logrecord.MessageFields,
+		timestampFields: logrecord.TimestampFields,
+		root:            newNode(),
+	}
+	for _, option := range options {
+		option(m)
+	}
+	if m.depth < 3 {
+		m.depth = 3
+	}
+	return m
+}
+
+// AddResults adds the records of a logs search payload, a list of objects.
+// Records without a message are skipped, see Skipped.
+func (m *Miner) AddResults(payload interface{}) error {
+	var rows []interface{}
+	switch v := payload.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		rows = v
+	case map[string]interface{}:
+		logs, ok := v["logs"].([]interface{})
+		if !ok && v["logs"] != nil {
+			return fmt.Errorf("unexpected logs search response: logs of type %T", v["logs"])
+		}
+		rows = logs
+	default:
+		return fmt.Errorf("unexpected logs search response of type %T", payload)
+	}
+
+	for i, row := range rows {
+		record, ok := row.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("record %d: unexpected value of type %T", i, row)
+		}
+		m.AddRecord(record)
+	}
+	return nil
+}
+
+// AddRecord adds a log search record and returns the ID of its pattern. It
+// returns false for records without a message.
+func (m *Miner) AddRecord(record map[string]interface{}) (int, bool) {
+	message, ok := logrecord.Message(record, m.messageFields)
+	if !ok {
+		m.skipped++
+		return 0, false
+	}
+	timestamp, _ := logrecord.Timestamp(record, m.timestampFields)
+	return m.add(m.tokenize(message), 1, timestamp, timestamp, []map[string]interface{}{record}), true
+}
+
+// AddMessage adds a log line seen at timestamp, which may be zero, and
+// returns the ID of its pattern.
+func (m *Miner) AddMessage(message string, timestamp time.Time) int {
+	return m.add(m.tokenize(message), 1, timestamp, timestamp, nil)
+}
+
+// Merge adds the patterns of other, as if its lines had been added to m.
+// Patterns of other are merged in ID order.
+func (m *Miner) Merge(other *Miner) {
+	for _, c := range other.clusters {
+		m.add(append([]string{}, c.tokens...), c.Count, c.FirstSeen, c.LastSeen, c.Samples)
+	}
+	m.skipped += other.skipped
+}
+
+// Skipped returns the number of records skipped for lacking a message.
+func (m *Miner) Skipped() int64 {
+	return m.skipped
+}
+
+// Patterns returns the patterns by decreasing count, then by ID.
+func (m *Miner) Patterns() []Pattern {
+	patterns := make([]Pattern, len(m.clusters))
+	for i, c := range m.clusters {
+		patterns[i] = c.Pattern
+		patterns[i].Samples = append([]map[string]interface{}{}, c.Samples...)
+	}
+	sort.SliceStable(patterns, func(i, j int) bool {
+		if patterns[i].Count != patterns[j].Count {
+			return patterns[i].Count > patterns[j].Count
+		}
+		return patterns[i].ID < patterns[j].ID
+	})
+	return patterns
+}
+
+// Match returns the pattern message would join, without adding it.
+func (m *Miner) Match(message string) (Pattern, bool) {
+	tokens := m.tokenize(message)
+	leaf := m.search(tokens)
+	if leaf == nil {
+		return Pattern{}, false
+	}
+	c := m.bestMatch(leaf.clusters, tokens)
+	if c == nil {
+		return Pattern{}, false
+	}
+	return c.Pattern, true
+}
+
+func (m *Miner) tokenize(message string) []string {
+	for _, mask := range m.masks {
+		message = mask.ReplaceAllString(message, Wildcard)
+	}
+	return strings.Fields(message)
+}
+
+func (m *Miner) add(tokens []string, count int64, first, last time.Time, samples []map[string]interface{}) int {
+	leaf := m.search(tokens)
+	var c *cluster
+	if leaf != nil {
+		c = m.bestMatch(leaf.clusters, tokens)
+	}
+
+	if c == nil {
+		c = &cluster{tokens: tokens, Pattern: Pattern{ID: len(m.clusters) + 1}}
+		m.clusters = append(m.clusters, c)
+		m.insert(c)
+	} else {
+		for i, token := range c.tokens {
+			if token != tokens[i] {
+				c.tokens[i] = Wildcard
+			}
+		}
+	}
+
+	c.Template = strings.Join(c.tokens, " ")
+	c.Count += count
+	if !first.IsZero() && (c.FirstSeen.IsZero() || first.Before(c.FirstSeen)) {
+		c.FirstSeen = first
+	}
+	if !last.IsZero() && last.After(c.LastSeen) {
+		c.LastSeen = last
+	}
+	for _, sample := range samples {
+		if len(c.Samples) >= m.maxSamples {
+			break
+		}
+		c.Samples = append(c.Samples, sample)
+	}
+	return c.ID
+}
+
+// search returns the leaf tokens are routed to, or nil.
+func (m *Miner) search(tokens []string) *node {
+	current, ok := m.root.children[lengthKey(tokens)]
+	if !ok {
+		return nil
+	}
+	for _, token := range m.prefix(tokens) {
+		next, ok := current.children[token]
+		if !ok {
+			if next, ok = current.children[Wildcard]; !ok {
+				return nil
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// insert adds c to the tree, creating the path of its tokens.
+func (m *Miner) insert(c *cluster) {
+	current := child(m.root, lengthKey(c.tokens))
+	for _, token := range m.prefix(c.tokens) {
+		if next, ok := current.children[token]; ok {
+			current = next
+			continue
+		}
+
+		_, hasWildcard := current.children[Wildcard]
+		switch {
+		case hasDigit(token) || token == Wildcard:
+			current = child(current, Wildcard)
+		case hasWildcard && len(current.children) < m.maxChildren:
+			current = child(current, token)
+		case hasWildcard:
+			current = current.children[Wildcard]
+		case len(current.children)+1 < m.maxChildren:
+			current = child(current, token)
+		default:
+			current = child(current, Wildcard)
+		}
+	}
+	current.clusters = append(current.clusters, c)
+}
+
+// prefix returns the tokens lines are routed by.
+func (m *Miner) prefix(tokens []string) []string {
+	if n := m.depth - 2; len(tokens) > n {
+		return tokens[:n]
+	}
+	return tokens
+}
+
+// bestMatch returns the most similar cluster reaching the threshold,
+// preferring more wildcards and then older clusters on ties.
+func (m *Miner) bestMatch(clusters []*cluster, tokens []string) *cluster {
+	var best *cluster
+	bestSimilarity, bestWildcards := -1.0, -1
+	for _, c := range clusters {
+		similarity, wildcards := similarity(c.tokens, tokens)
+		if similarity > bestSimilarity || (similarity == bestSimilarity && wildcards > bestWildcards) {
+			best, bestSimilarity, bestWildcards = c, similarity, wildcards
+		}
+	}
+	if best == nil || bestSimilarity < m.threshold {
+		return nil
+	}
+	return best
+}
+
+// similarity returns the share of tokens equal to the template's, ignoring
+// wildcards, and the number of wildcards of the template.
+func similarity(template, tokens []string) (float64, int) {
+	if len(template) == 0 {
+		return 1, 0
+	}
+	equal, wildcards := 0, 0
+	for i, token := range template {
+		if token == Wildcard {
+			wildcards++
+			continue
+		}
+		if token == tokens[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(template)), wildcards
+}
+
+func child(n *node, key string) *node {
+	next, ok := n.children[key]
+	if !ok {
+		next = newNode()
+		n.children[key] = next
+	}
+	return next
+}
+
+func lengthKey(tokens []string) string {
+	return fmt.Sprint(len(tokens))
+}
+
+func hasDigit(token string) bool {
+	return strings.ContainsAny(token, "0123456789")
+}
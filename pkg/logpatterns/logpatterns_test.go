@@ -0,0 +1,135 @@
+package logpatterns
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func loadFixture(t *testing.T, path string) []interface{} {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open fixture: %v", err)
+	}
+	defer file.Close()
+
+	var records []interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Invalid fixture line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestMinerFixture(t *testing.T) {
+	miner := NewMiner(WithMaxSamples(2))
+	if err := miner.AddResults(loadFixture(t, "testdata/incident.ndjson")); err != nil {
+		t.Fatalf("AddResults failed: %v", err)
+	}
+
+	want := []struct {
+		template string
+		count    int64
+	}{
+		{"connection to <*> timed out after <*>ms", 6},
+		{"GET /api/orders/<*> returned <*> in <*>ms", 6},
+		{"retrying payment <*> for user u<*> attempt <*>", 3},
+		{"worker pool exhausted, queue length <*>", 2},
+		{"shutting down", 1},
+	}
+	patterns := miner.Patterns()
+	if len(patterns) != len(want) {
+		t.Fatalf("Expected %d patterns, got %d: %+v", len(want), len(patterns), patterns)
+	}
+	for i, w := range want {
+		if patterns[i].Template != w.template || patterns[i].Count != w.count {
+			t.Errorf("Pattern %d: expected %q x%d, got %q x%d", i, w.template, w.count, patterns[i].Template, patterns[i].Count)
+		}
+	}
+
+	connections := patterns[0]
+	if got := connections.FirstSeen.Format(time.RFC3339); got != "2024-03-05T10:00:01Z" {
+		t.Errorf("Unexpected first seen %s", got)
+	}
+	if got := connections.LastSeen.Format(time.RFC3339); got != "2024-03-05T10:00:14Z" {
+		t.Errorf("Unexpected last seen %s", got)
+	}
+	if len(connections.Samples) != 2 || connections.Samples[0]["content"] != "connection to 10.0.1.12:5432 timed out after 250ms" {
+		t.Errorf("Expected the first 2 records as samples, got %v", connections.Samples)
+	}
+
+	if miner.Skipped() != 1 {
+		t.Errorf("Expected the record without content to be skipped, got %d", miner.Skipped())
+	}
+}
+
+func TestMinerIsDeterministic(t *testing.T) {
+	records := loadFixture(t, "testdata/incident.ndjson")
+	first, second := NewMiner(), NewMiner()
+	first.AddResults(records)
+	second.AddResults(records)
+
+	if !reflect.DeepEqual(first.Patterns(), second.Patterns()) {
+		t.Error("Expected identical patterns for identical input")
+	}
+}
+
+func TestMinerMerge(t *testing.T) {
+	records := loadFixture(t, "testdata/incident.ndjson")
+
+	whole := NewMiner()
+	whole.AddResults(records)
+
+	// Mining two halves separately and merging gives the same clusters.
+	left, right := NewMiner(), NewMiner()
+	left.AddResults(records[:len(records)/2])
+	right.AddResults(records[len(records)/2:])
+	left.Merge(right)
+
+	summarize := func(patterns []Pattern) map[string]int64 {
+		counts := map[string]int64{}
+		for _, p := range patterns {
+			counts[p.Template] = p.Count
+		}
+		return counts
+	}
+	if got, want := summarize(left.Patterns()), summarize(whole.Patterns()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected merged patterns %v, got %v", want, got)
+	}
+	if left.Skipped() != whole.Skipped() {
+		t.Errorf("Expected %d skipped, got %d", whole.Skipped(), left.Skipped())
+	}
+}
+
+func TestMinerGeneralizesTokens(t *testing.T) {
+	miner := NewMiner()
+	first := miner.AddMessage("login failed for user alice from web", time.Time{})
+	second := miner.AddMessage("login failed for user bob from web", time.Time{})
+	other := miner.AddMessage("cache warmed", time.Time{})
+
+	if first != second {
+		t.Errorf("Expected both logins in one pattern, got IDs %d and %d", first, second)
+	}
+	if first == other {
+		t.Error("Expected unrelated line in its own pattern")
+	}
+
+	pattern, ok := miner.Match("login failed for user carol from web")
+	if !ok || pattern.ID != first || pattern.Template != "login failed for user <*> from web" {
+		t.Errorf("Unexpected match %+v, %v", pattern, ok)
+	}
+	if _, ok := miner.Match("login succeeded after two attempts today"); ok {
+		t.Error("Expected no match")
+	}
+	if pattern.Count != 2 {
+		t.Errorf("Expected Match not to add the line, got count %d", pattern.Count)
+	}
+}
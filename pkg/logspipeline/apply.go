@@ -0,0 +1,179 @@
+package logspipeline
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/ottl"
+)
+
+// Result is the outcome of applying a Config to one log record.
+type Result struct {
+	// Input is the record as given; Output is the record after all rules.
+	Input  map[string]interface{}
+	Output map[string]interface{}
+	// Trace has an entry per rule, in config order.
+	Trace []RuleTrace
+}
+
+// RuleTrace records how a rule applied to a record.
+type RuleTrace struct {
+	Rule string
+	// Matched reports whether the rule's conditions held.
+	Matched bool
+	// Err is the error evaluating the conditions, if any.
+	Err error
+	// Statements has an entry per statement when the rule matched.
+	Statements []StatementTrace
+}
+
+// StatementTrace records how a statement applied to a record.
+type StatementTrace struct {
+	Statement string
+	// Applied reports whether the editor ran, i.e. the where clause held.
+	Applied bool
+	// Err is the error running the statement; it does not stop the
+	// following statements, as with the pipeline's ignore error mode.
+	Err error
+}
+
+// Errors returns the errors recorded in the trace.
+func (r *Result) Errors() []error {
+	var errs []error
+	for _, rule := range r.Trace {
+		if rule.Err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", rule.Rule, rule.Err))
+		}
+		for _, statement := range rule.Statements {
+			if statement.Err != nil {
+				errs = append(errs, fmt.Errorf("rule %s: %s: %w", rule.Rule, statement.Statement, statement.Err))
+			}
+		}
+	}
+	return errs
+}
+
+type compiledRule struct {
+	name       string
+	conditions []ottl.Expr
+	statements []*ottl.Statement
+	sources    []string
+}
+
+// Apply runs the configuration locally against sample log records, so rules
+// can be tested before they are applied. Records are JSON-like maps, e.g.
+//
+//	{"body": "...", "container_name": "nginx",
+//	 "attributes": {...}, "resource": {"attributes": {...}}}
+//
+// A rule applies when any of its conditions holds, or always when it has
+// none; its statements then run in order with a cache shared by the rule.
+// The input records are not modified.
+//
+// Apply supports the editors and converters listed by ottl.Functions and
+// returns a *ValidationError when the configuration is invalid or uses
+// anything else.
+func (c *Config) Apply(records ...map[string]interface{}) ([]*Result, error) {
+	rules, err := c.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*Result, len(records))
+	for i, record := range records {
+		output, _ := copyValue(record).(map[string]interface{})
+		if output == nil {
+			output = map[string]interface{}{}
+		}
+		result := &Result{Input: record, Output: output}
+		for _, rule := range rules {
+			result.Trace = append(result.Trace, rule.apply(output))
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+func (c *Config) compile() ([]*compiledRule, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	rules := make([]*compiledRule, len(c.Rules))
+	for i, rule := range c.Rules {
+		compiled := &compiledRule{name: rule.Name, sources: rule.Statements}
+		for j, source := range rule.Conditions {
+			condition, _ := ottl.ParseCondition(source)
+			if err := ottl.CheckCondition(condition); err != nil {
+				problems = append(problems, Problem{Rule: rule.Name, Field: fmt.Sprintf("%s[%d]", keyConditions, j), Msg: err.Error(), Err: err})
+			}
+			compiled.conditions = append(compiled.conditions, condition)
+		}
+		for j, source := range rule.Statements {
+			statement, _ := ottl.ParseStatement(source)
+			if err := ottl.Check(statement); err != nil {
+				problems = append(problems, Problem{Rule: rule.Name, Field: fmt.Sprintf("%s[%d]", keyStatements, j), Msg: err.Error(), Err: err})
+			}
+			compiled.statements = append(compiled.statements, statement)
+		}
+		rules[i] = compiled
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return rules, nil
+}
+
+func (r *compiledRule) apply(record map[string]interface{}) RuleTrace {
+	env := &ottl.Env{Record: record}
+	trace := RuleTrace{Rule: r.name, Matched: len(r.conditions) == 0}
+
+	var errs []error
+	for _, condition := range r.conditions {
+		ok, err := ottl.EvaluateCondition(condition, env)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			trace.Matched = true
+			break
+		}
+	}
+	if !trace.Matched {
+		trace.Err = errors.Join(errs...)
+		return trace
+	}
+
+	for i, statement := range r.statements {
+		applied, err := statement.Execute(env)
+		trace.Statements = append(trace.Statements, StatementTrace{
+			Statement: r.sources[i],
+			Applied:   applied,
+			Err:       err,
+		})
+	}
+	return trace
+}
+
+// copyValue deep-copies maps and lists so rules never modify the input.
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = copyValue(item)
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = copyValue(item)
+		}
+		return items
+	default:
+		return v
+	}
+}
@@ -0,0 +1,58 @@
+package logspipeline
+
+// Builder builds a Config rule by rule:
+//
+//	config, err := logspipeline.NewBuilder().
+//		Rule("tag-nginx").
+//		When(`container_name == "nginx"`).
+//		Do(`set(attributes["team"], "edge")`).
+//		Rule("drop-passwords").
+//		Do(`delete_key(attributes, "password")`).
+//		Build()
+type Builder struct {
+	config  *Config
+	current *Rule
+}
+
+// NewBuilder creates a Builder for an empty Config.
+func NewBuilder() *Builder {
+	return &Builder{config: &Config{}}
+}
+
+// Rule starts a new rule. Following When and Do calls add to it.
+func (b *Builder) Rule(name string) *Builder {
+	b.current = &Rule{Name: name}
+	b.config.Rules = append(b.config.Rules, b.current)
+	return b
+}
+
+// When adds conditions to the current rule.
+func (b *Builder) When(conditions ...string) *Builder {
+	rule := b.rule()
+	rule.Conditions = append(rule.Conditions, conditions...)
+	return b
+}
+
+// Do adds statements to the current rule.
+func (b *Builder) Do(statements ...string) *Builder {
+	rule := b.rule()
+	rule.Statements = append(rule.Statements, statements...)
+	return b
+}
+
+// rule returns the current rule, starting an unnamed one if needed so that
+// Validate reports it.
+func (b *Builder) rule() *Rule {
+	if b.current == nil {
+		b.Rule("")
+	}
+	return b.current
+}
+
+// Build validates and returns the Config.
+func (b *Builder) Build() (*Config, error) {
+	if err := b.config.Validate(); err != nil {
+		return nil, err
+	}
+	return b.config, nil
+}
@@ -0,0 +1,273 @@
+// Package logspipeline models the logs pipeline configuration, the YAML
+// document stored in models.LogsPipelineConfig.Value:
+//
+//	ottlRules:
+//	- ruleName: example-rule
+//	  conditions:
+//	    - container_name == "nginx"
+//	  statements:
+//	    - set(attributes["test.key"], "test-value")
+//
+// Parse decodes the YAML into a Config and Config.YAML encodes it again;
+// a parsed Config that was not modified encodes to its exact source. Configs
+// are built with NewBuilder and checked with Validate before they are sent.
+package logspipeline
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// YAML keys of the configuration.
+const (
+	keyOTTLRules  = "ottlRules"
+	keyRuleName   = "ruleName"
+	keyConditions = "conditions"
+	keyStatements = "statements"
+)
+
+// Config is a logs pipeline configuration.
+type Config struct {
+	Rules []*Rule
+	// Extra holds top-level keys other than ottlRules, in source order.
+	Extra yaml.MapSlice
+
+	source   string
+	snapshot *Config
+}
+
+// Rule is an OTTL rule: its statements apply to the logs matching its
+// conditions.
+type Rule struct {
+	Name       string
+	Conditions []string
+	Statements []string
+	// Extra holds rule keys other than ruleName, conditions and statements,
+	// in source order.
+	Extra yaml.MapSlice
+}
+
+// Parse decodes a configuration value. It checks the document structure
+// only; see Validate for the rules themselves.
+func Parse(value string) (*Config, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, fmt.Errorf("error parsing logs pipeline config: %w", err)
+	}
+
+	config := &Config{}
+	for _, item := range doc {
+		key, ok := item.Key.(string)
+		if !ok || key != keyOTTLRules {
+			config.Extra = append(config.Extra, item)
+			continue
+		}
+
+		rules, ok := item.Value.([]interface{})
+		if !ok && item.Value != nil {
+			return nil, fmt.Errorf("error parsing logs pipeline config: %s must be a list", keyOTTLRules)
+		}
+		for i, value := range rules {
+			rule, err := parseRule(value)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing logs pipeline config: %s[%d]: %w", keyOTTLRules, i, err)
+			}
+			config.Rules = append(config.Rules, rule)
+		}
+	}
+
+	config.source = value
+	config.snapshot = config.clone()
+	return config, nil
+}
+
+// FromModel decodes the value of a stored configuration.
+func FromModel(model *models.LogsPipelineConfig) (*Config, error) {
+	if model == nil {
+		return &Config{}, nil
+	}
+	return Parse(model.Value)
+}
+
+func parseRule(value interface{}) (*Rule, error) {
+	fields, ok := value.(yaml.MapSlice)
+	if !ok {
+		return nil, fmt.Errorf("rule must be a mapping")
+	}
+
+	rule := &Rule{}
+	for _, field := range fields {
+		key, _ := field.Key.(string)
+		var err error
+		switch key {
+		case keyRuleName:
+			name, ok := field.Value.(string)
+			if !ok && field.Value != nil {
+				return nil, fmt.Errorf("%s must be a string", keyRuleName)
+			}
+			rule.Name = name
+		case keyConditions:
+			rule.Conditions, err = parseStrings(keyConditions, field.Value)
+		case keyStatements:
+			rule.Statements, err = parseStrings(keyStatements, field.Value)
+		default:
+			rule.Extra = append(rule.Extra, field)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rule, nil
+}
+
+func parseStrings(key string, value interface{}) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", key)
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s[%d] must be a string, got %v", key, i, item)
+		}
+		values[i] = s
+	}
+	return values, nil
+}
+
+// YAML encodes the configuration. A Config returned by Parse encodes to its
+// source until it is modified; other configs use the layout of the example
+// above.
+func (c *Config) YAML() (string, error) {
+	if c.snapshot != nil && reflect.DeepEqual(c.clone(), c.snapshot) {
+		return c.source, nil
+	}
+
+	var b strings.Builder
+	if len(c.Rules) == 0 {
+		b.WriteString(keyOTTLRules + ": []\n")
+	} else {
+		b.WriteString(keyOTTLRules + ":\n")
+	}
+	for _, rule := range c.Rules {
+		b.WriteString("- " + keyRuleName + ": " + scalar(rule.Name) + "\n")
+		writeStrings(&b, keyConditions, rule.Conditions, true)
+		writeStrings(&b, keyStatements, rule.Statements, false)
+		if err := writeExtra(&b, rule.Extra, "  "); err != nil {
+			return "", err
+		}
+	}
+	if err := writeExtra(&b, c.Extra, ""); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// Request returns the create or update request for the configuration,
+// after validating it.
+func (c *Config) Request() (*models.CreateOrUpdateLogsPipelineConfigRequest, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	value, err := c.YAML()
+	if err != nil {
+		return nil, err
+	}
+	return &models.CreateOrUpdateLogsPipelineConfigRequest{Value: value}, nil
+}
+
+// Rule returns the rule named name, or nil.
+func (c *Config) Rule(name string) *Rule {
+	for _, rule := range c.Rules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+func writeStrings(b *strings.Builder, key string, values []string, omitEmpty bool) {
+	if len(values) == 0 {
+		if !omitEmpty {
+			b.WriteString("  " + key + ": []\n")
+		}
+		return
+	}
+	b.WriteString("  " + key + ":\n")
+	for _, value := range values {
+		b.WriteString("    - " + scalar(value) + "\n")
+	}
+}
+
+func writeExtra(b *strings.Builder, extra yaml.MapSlice, indent string) error {
+	for _, item := range extra {
+		data, err := yaml.Marshal(yaml.MapSlice{item})
+		if err != nil {
+			return fmt.Errorf("error encoding logs pipeline config key %v: %w", item.Key, err)
+		}
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(string(data), "\n"), "\n") {
+			b.WriteString(indent + strings.TrimSuffix(line, "\n") + "\n")
+		}
+	}
+	return nil
+}
+
+// scalar renders s plain when YAML reads it back unchanged, and
+// double-quoted otherwise.
+func scalar(s string) string {
+	if s != "" && !strings.ContainsAny(s, "\n\t") && strings.TrimSpace(s) == s {
+		var decoded map[string]interface{}
+		if err := yaml.Unmarshal([]byte("v: "+s), &decoded); err == nil && decoded["v"] == s {
+			return s
+		}
+	}
+	var b bytes.Buffer
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// clone returns a deep copy of the configuration content.
+func (c *Config) clone() *Config {
+	clone := &Config{Extra: append(yaml.MapSlice{}, c.Extra...)}
+	for _, rule := range c.Rules {
+		clone.Rules = append(clone.Rules, rule.clone())
+	}
+	return clone
+}
+
+func (r *Rule) clone() *Rule {
+	if r == nil {
+		return nil
+	}
+	return &Rule{
+		Name:       r.Name,
+		Conditions: append([]string{}, r.Conditions...),
+		Statements: append([]string{}, r.Statements...),
+		Extra:      append(yaml.MapSlice{}, r.Extra...),
+	}
+}
@@ -0,0 +1,267 @@
+package logspipeline
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/ottl"
+)
+
+const exampleConfig = `ottlRules:
+- ruleName: example-rule
+  conditions:
+    - container_name == "nginx"
+  statements:
+    - set(attributes["test.key"], "test-value")`
+
+func TestParse(t *testing.T) {
+	config, err := Parse(exampleConfig)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := []*Rule{{
+		Name:       "example-rule",
+		Conditions: []string{`container_name == "nginx"`},
+		Statements: []string{`set(attributes["test.key"], "test-value")`},
+	}}
+	if !reflect.DeepEqual(config.Rules, want) {
+		t.Errorf("Expected rules %+v, got %+v", want[0], config.Rules[0])
+	}
+
+	for _, invalid := range []string{
+		"ottlRules: {}",
+		"ottlRules:\n- just a string",
+		"ottlRules:\n- ruleName: a\n  statements: set(x, 1)",
+		"ottlRules:\n- ruleName: a\n  statements:\n  - 42",
+		"ottlRules: [",
+	} {
+		if _, err := Parse(invalid); err == nil {
+			t.Errorf("Parse(%q): expected an error", invalid)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	sources := []string{
+		exampleConfig,
+		// Unusual layout, quoting and comments survive while unmodified.
+		"# managed by platform\nottlRules:\n  - ruleName: \"quoted\"   # trailing\n    statements: ['set(attributes[\"a\"], \"b\")']\nversion: 2\n",
+	}
+	for _, source := range sources {
+		config, err := Parse(source)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		got, err := config.YAML()
+		if err != nil {
+			t.Fatalf("YAML failed: %v", err)
+		}
+		if got != source {
+			t.Errorf("Expected exact round trip of\n%s\ngot\n%s", source, got)
+		}
+	}
+}
+
+func TestYAMLAfterChanges(t *testing.T) {
+	config, err := Parse(exampleConfig + "\nversion: 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Rules[0].Statements = append(config.Rules[0].Statements, `delete_key(attributes, "password")`)
+	config.Rules = append(config.Rules, &Rule{
+		Name:       "flag: true",
+		Statements: []string{`set(attributes["x"], "y") # not a comment`},
+		Extra:      nil,
+	})
+
+	got, err := config.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `ottlRules:
+- ruleName: example-rule
+  conditions:
+    - container_name == "nginx"
+  statements:
+    - set(attributes["test.key"], "test-value")
+    - delete_key(attributes, "password")
+- ruleName: "flag: true"
+  statements:
+    - "set(attributes[\"x\"], \"y\") # not a comment"
+version: 2`
+	if got != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got)
+	}
+
+	reparsed, err := Parse(got)
+	if err != nil {
+		t.Fatalf("Encoded config does not parse: %v", err)
+	}
+	if !reflect.DeepEqual(reparsed.clone(), config.clone()) {
+		t.Errorf("Expected the encoded config to decode to the same rules, got %+v", reparsed.Rules)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	config, err := NewBuilder().
+		Rule("example-rule").
+		When(`container_name == "nginx"`).
+		Do(`set(attributes["test.key"], "test-value")`).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	got, err := config.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != exampleConfig {
+		t.Errorf("Expected\n%s\ngot\n%s", exampleConfig, got)
+	}
+
+	request, err := config.Request()
+	if err != nil || request.Value != exampleConfig {
+		t.Errorf("Unexpected request %+v, %v", request, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	_, err := NewBuilder().
+		Rule("a").Do(`set(attributes["x"], 1)`).
+		Rule("a").Do(`set(attributes["y"], 2)`).
+		Rule("no-statements").When(`x == 1`).
+		Rule("blank").Do("  ").
+		Rule("bad-condition").When(`x ==`).Do(`set(attributes["x"], 1)`).
+		Rule("bad-statement").Do(`set(attributes["x"], 1`).
+		Rule("").Do(`set(attributes["x"], 1)`).
+		Build()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a *ValidationError, got %v", err)
+	}
+
+	want := []string{
+		"rule a ruleName: duplicate rule name",
+		"rule no-statements statements: rule has no statements",
+		"rule blank statements[0]: statement is empty",
+		"rule bad-condition conditions[0]: ottl: syntax error at column 5: unexpected end of input",
+		"rule bad-statement statements[0]: ottl: syntax error at column 23: expected \",\", got end of input",
+		"rule #7 ruleName: rule name is empty",
+	}
+	var got []string
+	for _, problem := range validationErr.Problems {
+		got = append(got, problem.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected problems\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	var syntaxErr *ottl.SyntaxError
+	if !errors.As(validationErr.Problems[3].Err, &syntaxErr) {
+		t.Errorf("Expected the OTTL syntax error to be kept, got %v", validationErr.Problems[3].Err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	config, err := Parse(`ottlRules:
+- ruleName: parse-json
+  conditions:
+    - container_name == "api"
+    - resource.attributes["format"] == "json"
+  statements:
+    - set(cache, ParseJSON(body))
+    - merge_maps(attributes, cache, "upsert")
+    - delete_key(attributes, "password")
+- ruleName: nginx-access
+  conditions:
+    - container_name == "nginx"
+  statements:
+    - merge_maps(attributes, ExtractPatterns(body, "^(?P<method>[A-Z]+) (?P<path>\\S+) (?P<status>\\d+)"), "insert")
+    - replace_pattern(body, "token=\\w+", "token=***")
+    - set(attributes["error"], true) where attributes["status"] == "500"
+- ruleName: tag-all
+  statements:
+    - set(resource.attributes["team"], "platform")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := []map[string]interface{}{
+		{"container_name": "api", "body": `{"level":"info","password":"x"}`},
+		{"container_name": "nginx", "body": "GET /login?token=abc 500"},
+		{"container_name": "api", "body": "not json"},
+	}
+	results, err := config.Apply(records...)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	wantOutputs := []map[string]interface{}{
+		{
+			"container_name": "api", "body": `{"level":"info","password":"x"}`,
+			"attributes": map[string]interface{}{"level": "info"},
+			"resource":   map[string]interface{}{"attributes": map[string]interface{}{"team": "platform"}},
+		},
+		{
+			"container_name": "nginx", "body": "GET /login?token=*** 500",
+			"attributes": map[string]interface{}{"method": "GET", "path": "/login?token=abc", "status": "500", "error": true},
+			"resource":   map[string]interface{}{"attributes": map[string]interface{}{"team": "platform"}},
+		},
+		{
+			"container_name": "api", "body": "not json",
+			"resource": map[string]interface{}{"attributes": map[string]interface{}{"team": "platform"}},
+		},
+	}
+	for i, result := range results {
+		if !reflect.DeepEqual(result.Output, wantOutputs[i]) {
+			t.Errorf("Record %d: expected %v, got %v", i, wantOutputs[i], result.Output)
+		}
+	}
+	if _, ok := records[0]["attributes"]; ok {
+		t.Error("Expected the input records to be left unchanged")
+	}
+
+	nginx := results[1].Trace
+	if nginx[0].Matched || !nginx[1].Matched || !nginx[2].Matched {
+		t.Errorf("Unexpected matches %+v", nginx)
+	}
+	if len(nginx[1].Statements) != 3 || !nginx[1].Statements[2].Applied {
+		t.Errorf("Expected all nginx statements to apply, got %+v", nginx[1].Statements)
+	}
+	if errs := results[1].Errors(); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+
+	errs := results[2].Errors()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "rule parse-json: set(cache, ParseJSON(body)): ottl: set: ParseJSON") {
+		t.Errorf("Expected a ParseJSON error, got %v", errs)
+	}
+	if statements := results[2].Trace[0].Statements; len(statements) != 3 || statements[1].Err != nil {
+		t.Errorf("Expected the statements after an error to run, got %+v", statements)
+	}
+}
+
+func TestApplyUnsupported(t *testing.T) {
+	config, err := NewBuilder().
+		Rule("keep").
+		When(`IsString(body)`).
+		Do(`keep_keys(attributes, ["a"])`).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = config.Apply(map[string]interface{}{})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 2 {
+		t.Fatalf("Expected two problems, got %v", err)
+	}
+	if got := validationErr.Problems[1].String(); got != "rule keep statements[0]: ottl: unsupported function keep_keys" {
+		t.Errorf("Unexpected problem %q", got)
+	}
+}
@@ -0,0 +1,263 @@
+package logspipeline
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ChangeKind is the kind of a rule change.
+type ChangeKind string
+
+const (
+	RuleAdded    ChangeKind = "added"
+	RuleRemoved  ChangeKind = "removed"
+	RuleModified ChangeKind = "modified"
+	// RuleMoved is a rule whose content is unchanged but whose position
+	// relative to the other rules changed. Rules apply in order.
+	RuleMoved ChangeKind = "moved"
+)
+
+// LineOp marks a line of a rule diff.
+type LineOp string
+
+const (
+	LineKept    LineOp = " "
+	LineAdded   LineOp = "+"
+	LineRemoved LineOp = "-"
+)
+
+// Line is a condition or statement in a rule diff.
+type Line struct {
+	Op   LineOp
+	Text string
+}
+
+// RuleChange describes how a rule differs between two configurations.
+// Rules are matched by name.
+type RuleChange struct {
+	Kind ChangeKind
+	Rule string
+	// Old and New are the rule in each configuration, nil when absent.
+	Old, New *Rule
+	// Moved is set on a modified rule that also changed position.
+	Moved bool
+	// Conditions and Statements are line diffs of a modified rule; they are
+	// empty when those lines did not change.
+	Conditions []Line
+	Statements []Line
+	// ExtraChanged reports changes to the other keys of a modified rule.
+	ExtraChanged bool
+}
+
+// ConfigDiff is the rule-level difference between two configurations.
+type ConfigDiff struct {
+	// Changes are ordered as the rules of the new configuration, with
+	// removed rules at their old position.
+	Changes []RuleChange
+	// ExtraChanged reports changes to top-level keys other than ottlRules.
+	ExtraChanged bool
+}
+
+// Empty reports whether the configurations have the same rules and keys.
+func (d *ConfigDiff) Empty() bool {
+	return len(d.Changes) == 0 && !d.ExtraChanged
+}
+
+// Diff compares two configurations rule by rule. A nil config has no rules.
+func Diff(old, new *Config) *ConfigDiff {
+	if old == nil {
+		old = &Config{}
+	}
+	if new == nil {
+		new = &Config{}
+	}
+
+	oldRules, oldIndex := indexRules(old.Rules)
+	newRules, newIndex := indexRules(new.Rules)
+
+	// Rules kept in both configs keep their order unless they are outside the
+	// longest common subsequence of names.
+	var oldKept, newKept []string
+	for _, rule := range oldRules {
+		if _, ok := newIndex[rule.Name]; ok {
+			oldKept = append(oldKept, rule.Name)
+		}
+	}
+	for _, rule := range newRules {
+		if _, ok := oldIndex[rule.Name]; ok {
+			newKept = append(newKept, rule.Name)
+		}
+	}
+	inOrder := map[string]bool{}
+	for _, pair := range lcs(oldKept, newKept) {
+		inOrder[oldKept[pair[0]]] = true
+	}
+
+	diff := &ConfigDiff{ExtraChanged: !extraEqual(old.Extra, new.Extra)}
+	next := 0 // the first old rule not yet checked for removal
+	removed := func(upTo int) {
+		for ; next < upTo; next++ {
+			rule := oldRules[next]
+			if _, ok := newIndex[rule.Name]; !ok {
+				diff.Changes = append(diff.Changes, RuleChange{Kind: RuleRemoved, Rule: rule.Name, Old: rule})
+			}
+		}
+	}
+
+	for _, rule := range newRules {
+		i, ok := oldIndex[rule.Name]
+		if !ok {
+			diff.Changes = append(diff.Changes, RuleChange{Kind: RuleAdded, Rule: rule.Name, New: rule})
+			continue
+		}
+		if inOrder[rule.Name] {
+			removed(i)
+		}
+
+		change := RuleChange{Kind: RuleModified, Rule: rule.Name, Old: oldRules[i], New: rule, Moved: !inOrder[rule.Name]}
+		if !reflect.DeepEqual(change.Old.Conditions, rule.Conditions) {
+			change.Conditions = diffLines(change.Old.Conditions, rule.Conditions)
+		}
+		if !reflect.DeepEqual(change.Old.Statements, rule.Statements) {
+			change.Statements = diffLines(change.Old.Statements, rule.Statements)
+		}
+		change.ExtraChanged = !extraEqual(change.Old.Extra, rule.Extra)
+
+		switch {
+		case change.Conditions != nil || change.Statements != nil || change.ExtraChanged:
+			diff.Changes = append(diff.Changes, change)
+		case change.Moved:
+			change.Kind, change.Moved = RuleMoved, false
+			diff.Changes = append(diff.Changes, change)
+		}
+	}
+	removed(len(oldRules))
+	return diff
+}
+
+// indexRules returns the non-nil rules, ignoring later rules with a name
+// already seen, and the position of each name among them.
+func indexRules(rules []*Rule) ([]*Rule, map[string]int) {
+	var kept []*Rule
+	index := map[string]int{}
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		if _, ok := index[rule.Name]; ok {
+			continue
+		}
+		index[rule.Name] = len(kept)
+		kept = append(kept, rule)
+	}
+	return kept, index
+}
+
+func extraEqual(a, b yaml.MapSlice) bool {
+	var sa, sb strings.Builder
+	errA := writeExtra(&sa, a, "")
+	errB := writeExtra(&sb, b, "")
+	return errA == nil && errB == nil && sa.String() == sb.String()
+}
+
+// String renders the diff with a line per change: "+ rule NAME" for added
+// rules, "- rule NAME" for removed ones and "~ rule NAME" for modified or
+// moved ones, each followed by its conditions and statements prefixed with
+// "+", "-" or, for unchanged lines, a space.
+func (d *ConfigDiff) String() string {
+	var b strings.Builder
+	for _, change := range d.Changes {
+		switch change.Kind {
+		case RuleAdded:
+			fmt.Fprintf(&b, "+ rule %s\n", change.Rule)
+			writeLines(&b, keyConditions, diffLines(nil, change.New.Conditions))
+			writeLines(&b, keyStatements, diffLines(nil, change.New.Statements))
+		case RuleRemoved:
+			fmt.Fprintf(&b, "- rule %s\n", change.Rule)
+		case RuleMoved:
+			fmt.Fprintf(&b, "~ rule %s (moved)\n", change.Rule)
+		case RuleModified:
+			suffix := ""
+			if change.Moved {
+				suffix = " (moved)"
+			}
+			fmt.Fprintf(&b, "~ rule %s%s\n", change.Rule, suffix)
+			writeLines(&b, keyConditions, change.Conditions)
+			writeLines(&b, keyStatements, change.Statements)
+			if change.ExtraChanged {
+				b.WriteString("    other keys changed\n")
+			}
+		}
+	}
+	if d.ExtraChanged {
+		b.WriteString("~ top-level keys changed\n")
+	}
+	return b.String()
+}
+
+func writeLines(b *strings.Builder, key string, lines []Line) {
+	if len(lines) == 0 {
+		return
+	}
+	b.WriteString("    " + key + ":\n")
+	for _, line := range lines {
+		b.WriteString("    " + string(line.Op) + " " + line.Text + "\n")
+	}
+}
+
+// diffLines returns the line diff turning a into b.
+func diffLines(a, b []string) []Line {
+	var lines []Line
+	i, j := 0, 0
+	for _, pair := range lcs(a, b) {
+		for ; i < pair[0]; i++ {
+			lines = append(lines, Line{Op: LineRemoved, Text: a[i]})
+		}
+		for ; j < pair[1]; j++ {
+			lines = append(lines, Line{Op: LineAdded, Text: b[j]})
+		}
+		lines = append(lines, Line{Op: LineKept, Text: a[i]})
+		i, j = i+1, j+1
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Op: LineRemoved, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Op: LineAdded, Text: b[j]})
+	}
+	return lines
+}
+
+// lcs returns the index pairs of a longest common subsequence of a and b.
+func lcs(a, b []string) [][2]int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			pairs = append(pairs, [2]int{i, j})
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
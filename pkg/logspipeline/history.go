@@ -0,0 +1,345 @@
+package logspipeline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+// ErrVersionNotFound is returned for versions missing from a Store.
+var ErrVersionNotFound = errors.New("logs pipeline config version not found")
+
+// Version is a snapshot of a stored configuration.
+type Version struct {
+	// ID identifies the value: the first 12 hex digits of its SHA-256. The
+	// API may keep the UUID across updates, so it is not used.
+	ID               string    `json:"id"`
+	UUID             string    `json:"uuid,omitempty"`
+	CreatedBy        string    `json:"createdBy,omitempty"`
+	CreatedTimestamp time.Time `json:"createdTimestamp,omitempty"`
+	Value            string    `json:"value"`
+	// SavedAt is when the snapshot was taken.
+	SavedAt time.Time `json:"savedAt"`
+}
+
+// NewVersion snapshots a stored configuration.
+func NewVersion(model *models.LogsPipelineConfig, savedAt time.Time) *Version {
+	sum := sha256.Sum256([]byte(model.Value))
+	return &Version{
+		ID:               hex.EncodeToString(sum[:])[:12],
+		UUID:             model.UUID,
+		CreatedBy:        model.CreatedBy,
+		CreatedTimestamp: time.Time(model.CreatedTimestamp),
+		Value:            model.Value,
+		SavedAt:          savedAt,
+	}
+}
+
+// Config decodes the version's value.
+func (v *Version) Config() (*Config, error) {
+	return Parse(v.Value)
+}
+
+// Store keeps configuration versions.
+type Store interface {
+	// Save adds a version, replacing any version with the same ID.
+	Save(ctx context.Context, version *Version) error
+	// List returns the versions, oldest first.
+	List(ctx context.Context) ([]*Version, error)
+	// Get returns a version by ID, or ErrVersionNotFound.
+	Get(ctx context.Context, id string) (*Version, error)
+}
+
+// MemoryStore is a Store in memory, for tests and short-lived processes.
+type MemoryStore struct {
+	mu       sync.Mutex
+	versions []*Version
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) Save(ctx context.Context, version *Version) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions = saveVersion(s.versions, version)
+	return nil
+}
+
+func (s *MemoryStore) List(ctx context.Context) ([]*Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Version(nil), s.versions...), nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return findVersion(s.versions, id)
+}
+
+// FileStore is a Store in a JSON file, replaced atomically on every save.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore creates a FileStore at path. The file and its directory are
+// created on the first save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) Save(ctx context.Context, version *Version) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.read()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(saveVersion(versions, version), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding logs pipeline history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("error creating logs pipeline history directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "."+filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error writing logs pipeline history: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing logs pipeline history: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing logs pipeline history: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error writing logs pipeline history: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) List(ctx context.Context) ([]*Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+func (s *FileStore) Get(ctx context.Context, id string) (*Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return findVersion(versions, id)
+}
+
+func (s *FileStore) read() ([]*Version, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading logs pipeline history: %w", err)
+	}
+	var versions []*Version
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("error parsing logs pipeline history %s: %w", s.path, err)
+	}
+	return versions, nil
+}
+
+// saveVersion replaces or appends version, keeping versions sorted by SavedAt.
+func saveVersion(versions []*Version, version *Version) []*Version {
+	kept := make([]*Version, 0, len(versions)+1)
+	for _, v := range versions {
+		if v.ID != version.ID {
+			kept = append(kept, v)
+		}
+	}
+	kept = append(kept, version)
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].SavedAt.Before(kept[j].SavedAt)
+	})
+	return kept
+}
+
+func findVersion(versions []*Version, id string) (*Version, error) {
+	for _, v := range versions {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrVersionNotFound, id)
+}
+
+// ConfigAPI reads and writes the stored configuration. It is satisfied by
+// the client's LogsPipeline service.
+type ConfigAPI interface {
+	Get(ctx context.Context) (*models.LogsPipelineConfig, error)
+	Create(ctx context.Context, value string) (*models.LogsPipelineConfig, error)
+	Update(ctx context.Context, value string) (*models.LogsPipelineConfig, error)
+}
+
+// History versions the configuration: it snapshots the current
+// configuration into a Store before every update, so updates can be
+// compared and rolled back.
+//
+//	history := logspipeline.NewHistory(gc.LogsPipeline, logspipeline.NewFileStore("pipeline-history.json"))
+//	if _, err := history.Update(ctx, config); err != nil { ... }
+//	...
+//	previous, err := history.Undo(ctx)
+type History struct {
+	api   ConfigAPI
+	store Store
+	now   func() time.Time
+}
+
+// NewHistory creates a History over api and store.
+func NewHistory(api ConfigAPI, store Store) *History {
+	return &History{api: api, store: store, now: time.Now}
+}
+
+// Snapshot saves the current configuration, if any, and returns its version.
+// A value saved before keeps its existing version.
+func (h *History) Snapshot(ctx context.Context) (*Version, error) {
+	current, err := h.api.Get(ctx)
+	if err != nil || current == nil {
+		return nil, err
+	}
+	return h.save(ctx, current)
+}
+
+func (h *History) save(ctx context.Context, model *models.LogsPipelineConfig) (*Version, error) {
+	version := NewVersion(model, h.now())
+	if existing, err := h.store.Get(ctx, version.ID); err == nil {
+		return existing, nil
+	}
+	if err := h.store.Save(ctx, version); err != nil {
+		return nil, fmt.Errorf("error saving logs pipeline config version: %w", err)
+	}
+	return version, nil
+}
+
+// Update validates config, snapshots the current configuration and replaces
+// it with config, creating it when none is set. The new configuration is
+// saved too. An invalid config returns a *ValidationError without calling
+// the API.
+func (h *History) Update(ctx context.Context, config *Config) (*models.LogsPipelineConfig, error) {
+	request, err := config.Request()
+	if err != nil {
+		return nil, err
+	}
+	return h.write(ctx, request.Value)
+}
+
+func (h *History) write(ctx context.Context, value string) (*models.LogsPipelineConfig, error) {
+	current, err := h.api.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *models.LogsPipelineConfig
+	if current == nil {
+		updated, err = h.api.Create(ctx, value)
+	} else {
+		if _, err := h.save(ctx, current); err != nil {
+			return nil, err
+		}
+		updated, err = h.api.Update(ctx, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if updated != nil {
+		if _, err := h.save(ctx, updated); err != nil {
+			return updated, err
+		}
+	}
+	return updated, nil
+}
+
+// Versions returns the saved versions, oldest first.
+func (h *History) Versions(ctx context.Context) ([]*Version, error) {
+	return h.store.List(ctx)
+}
+
+// Diff compares two saved versions. An empty toID compares with the current
+// configuration.
+func (h *History) Diff(ctx context.Context, fromID, toID string) (*ConfigDiff, error) {
+	from, err := h.store.Get(ctx, fromID)
+	if err != nil {
+		return nil, err
+	}
+	fromConfig, err := from.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	var toConfig *Config
+	if toID == "" {
+		current, err := h.api.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if toConfig, err = FromModel(current); err != nil {
+			return nil, err
+		}
+	} else {
+		to, err := h.store.Get(ctx, toID)
+		if err != nil {
+			return nil, err
+		}
+		if toConfig, err = to.Config(); err != nil {
+			return nil, err
+		}
+	}
+	return Diff(fromConfig, toConfig), nil
+}
+
+// Rollback restores the saved version id, after snapshotting the current
+// configuration. The stored value is restored as is.
+func (h *History) Rollback(ctx context.Context, id string) (*models.LogsPipelineConfig, error) {
+	version, err := h.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return h.write(ctx, version.Value)
+}
+
+// Undo rolls back to the most recent saved version whose value differs from
+// the current configuration. It returns ErrVersionNotFound when there is none.
+func (h *History) Undo(ctx context.Context) (*models.LogsPipelineConfig, error) {
+	current, err := h.api.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := h.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if current == nil || versions[i].Value != current.Value {
+			return h.write(ctx, versions[i].Value)
+		}
+	}
+	return nil, fmt.Errorf("%w: no earlier version", ErrVersionNotFound)
+}
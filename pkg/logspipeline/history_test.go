@@ -0,0 +1,178 @@
+package logspipeline
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+func TestDiff(t *testing.T) {
+	old, err := Parse(`ottlRules:
+- ruleName: a
+  statements:
+    - set(attributes["a"], 1)
+- ruleName: legacy
+  statements:
+    - delete_key(attributes, "x")
+- ruleName: b
+  conditions:
+    - container_name == "nginx"
+  statements:
+    - set(attributes["b"], 1)
+- ruleName: c
+  statements:
+    - set(attributes["c"], 1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := Parse(`ottlRules:
+- ruleName: c
+  statements:
+    - set(attributes["c"], 1)
+- ruleName: a
+  statements:
+    - set(attributes["a"], 1)
+- ruleName: b
+  conditions:
+    - container_name == "nginx-ingress"
+  statements:
+    - set(attributes["b"], 1)
+    - set(attributes["team"], "edge")
+- ruleName: tag-all
+  statements:
+    - set(resource.attributes["team"], "platform")
+version: 2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := Diff(old, new)
+	want := `~ rule c (moved)
+- rule legacy
+~ rule b
+    conditions:
+    - container_name == "nginx"
+    + container_name == "nginx-ingress"
+    statements:
+      set(attributes["b"], 1)
+    + set(attributes["team"], "edge")
++ rule tag-all
+    statements:
+    + set(resource.attributes["team"], "platform")
+~ top-level keys changed
+`
+	if got := diff.String(); got != want {
+		t.Errorf("Expected diff\n%s\ngot\n%s", want, got)
+	}
+	if diff.Changes[2].Kind != RuleModified || diff.Changes[2].Moved {
+		t.Errorf("Unexpected change %+v", diff.Changes[2])
+	}
+
+	if !Diff(old, old).Empty() {
+		t.Error("Expected an empty diff for the same config")
+	}
+	if changes := Diff(nil, old).Changes; len(changes) != 4 || changes[0].Kind != RuleAdded {
+		t.Errorf("Expected every rule to be added, got %+v", changes)
+	}
+}
+
+// fakeConfigAPI stores a single configuration and, like the API, keeps its
+// UUID across updates.
+type fakeConfigAPI struct {
+	current *models.LogsPipelineConfig
+	updates int
+}
+
+func (f *fakeConfigAPI) Get(ctx context.Context) (*models.LogsPipelineConfig, error) {
+	return f.current, nil
+}
+
+func (f *fakeConfigAPI) Create(ctx context.Context, value string) (*models.LogsPipelineConfig, error) {
+	f.current = &models.LogsPipelineConfig{UUID: "cfg-1", CreatedBy: "ci", Value: value,
+		CreatedTimestamp: strfmt.DateTime(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))}
+	return f.current, nil
+}
+
+func (f *fakeConfigAPI) Update(ctx context.Context, value string) (*models.LogsPipelineConfig, error) {
+	f.updates++
+	updated := *f.current
+	updated.Value = value
+	f.current = &updated
+	return f.current, nil
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeConfigAPI{}
+	store := NewFileStore(filepath.Join(t.TempDir(), "history", "pipeline.json"))
+	history := NewHistory(api, store)
+	clock := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	history.now = func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	}
+
+	configs := []*Config{}
+	for _, team := range []string{"core", "edge"} {
+		config, err := NewBuilder().Rule("tag").Do(`set(attributes["team"], "` + team + `")`).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		configs = append(configs, config)
+		if _, err := history.Update(ctx, config); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+
+	invalid := &Config{Rules: []*Rule{{Name: "bad"}}}
+	var validationErr *ValidationError
+	if _, err := history.Update(ctx, invalid); !errors.As(err, &validationErr) || api.updates != 1 {
+		t.Fatalf("Expected a validation error without an update, got %v", err)
+	}
+
+	versions, err := history.Versions(ctx)
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("Expected two versions, got %d: %v", len(versions), err)
+	}
+	first := versions[0]
+	if first.UUID != "cfg-1" || first.CreatedBy != "ci" || first.CreatedTimestamp.IsZero() {
+		t.Errorf("Expected the API metadata in the version, got %+v", first)
+	}
+
+	diff, err := history.Diff(ctx, first.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Changes) != 1 || diff.Changes[0].Statements[0].Text != `set(attributes["team"], "core")` {
+		t.Errorf("Unexpected diff %s", diff)
+	}
+
+	if _, err := history.Undo(ctx); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	want, _ := configs[0].YAML()
+	if api.current.Value != want {
+		t.Errorf("Expected the first config to be restored, got %s", api.current.Value)
+	}
+
+	if _, err := history.Rollback(ctx, versions[1].ID); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if api.current.Value != versions[1].Value {
+		t.Errorf("Expected the second config to be restored, got %s", api.current.Value)
+	}
+	if _, err := history.Rollback(ctx, "missing"); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("Expected ErrVersionNotFound, got %v", err)
+	}
+
+	// Restored values reuse their versions.
+	if versions, _ := history.Versions(ctx); len(versions) != 2 {
+		t.Errorf("Expected two versions, got %d", len(versions))
+	}
+}
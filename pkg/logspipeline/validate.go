@@ -0,0 +1,98 @@
+package logspipeline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/ottl"
+)
+
+// Problem is a single validation failure.
+type Problem struct {
+	// Rule is the rule name, or its position when the rule has no name.
+	Rule string
+	// Field is the offending field, e.g. "statements[1]", empty for the rule itself.
+	Field string
+	Msg   string
+	// Err is the underlying error, e.g. an *ottl.SyntaxError.
+	Err error
+}
+
+func (p Problem) String() string {
+	location := "rule " + p.Rule
+	if p.Field != "" {
+		location += " " + p.Field
+	}
+	return location + ": " + p.Msg
+}
+
+// ValidationError lists every problem found in a configuration.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		msgs[i] = problem.String()
+	}
+	return "invalid logs pipeline config: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks that rules have unique non-empty names and at least one
+// statement, and that every condition and statement is well-formed OTTL.
+// It returns a *ValidationError listing all problems.
+func (c *Config) Validate() error {
+	var problems []Problem
+	add := func(rule, field, msg string, err error) {
+		problems = append(problems, Problem{Rule: rule, Field: field, Msg: msg, Err: err})
+	}
+
+	seen := map[string]bool{}
+	for i, rule := range c.Rules {
+		if rule == nil {
+			add(fmt.Sprintf("#%d", i+1), "", "rule is empty", nil)
+			continue
+		}
+
+		name := rule.Name
+		switch {
+		case strings.TrimSpace(name) == "":
+			name = fmt.Sprintf("#%d", i+1)
+			add(name, keyRuleName, "rule name is empty", nil)
+		case seen[name]:
+			add(name, keyRuleName, "duplicate rule name", nil)
+		}
+		seen[rule.Name] = true
+
+		for j, condition := range rule.Conditions {
+			field := fmt.Sprintf("%s[%d]", keyConditions, j)
+			if strings.TrimSpace(condition) == "" {
+				add(name, field, "condition is empty", nil)
+				continue
+			}
+			if _, err := ottl.ParseCondition(condition); err != nil {
+				add(name, field, err.Error(), err)
+			}
+		}
+
+		if len(rule.Statements) == 0 {
+			add(name, keyStatements, "rule has no statements", nil)
+		}
+		for j, statement := range rule.Statements {
+			field := fmt.Sprintf("%s[%d]", keyStatements, j)
+			if strings.TrimSpace(statement) == "" {
+				add(name, field, "statement is empty", nil)
+				continue
+			}
+			if _, err := ottl.ParseStatement(statement); err != nil {
+				add(name, field, err.Error(), err)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+// Package logtail follows groundcover logs like tail -f.
+//
+// A Follower repeatedly searches logs over a moving time window. Each window
+// starts a little before the end of the previous one, so records that are
+// ingested late are still picked up, and records already emitted are dropped
+// by timestamp and content hash:
+//
+//	client, err := groundcover.New(apiKey, backendID, baseURL)
+//	...
+//	conditions := utils.NewConditionSet().
+//		Add(types.ConditionKeyWorkload, "checkout").
+//		Add(types.ConditionKeyNamespace, "prod")
+//	follower := logtail.NewFollower(client.Logs, logtail.WithConditions(conditions))
+//	for record := range follower.Follow(ctx) {
+//		fmt.Println(record.Timestamp, record.Fields["content"])
+//	}
+//	if err := follower.Err(); err != nil {
+//		...
+//	}
+package logtail
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/logrecord"
+	"github.com/groundcover-com/groundcover-sdk-go/internal/poller"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/utils"
+)
+
+// Defaults of a Follower.
+const (
+	DefaultInterval = 5 * time.Second
+	DefaultOverlap  = 30 * time.Second
+	DefaultLookback = time.Minute
+)
+
+// DefaultTimestampFields are the record fields searched for the record time.
+var DefaultTimestampFields = logrecord.TimestampFields
+
+// Searcher runs logs searches. *groundcover.LogsService implements it.
+type Searcher interface {
+	Search(ctx context.Context, request *models.LogsSearchRequest) (interface{}, error)
+}
+
+// Record is a log record emitted by a Follower.
+type Record struct {
+	// Timestamp is the record time, zero when the record has none.
+	Timestamp time.Time
+	// Hash is the hex SHA-256 of the record's canonical JSON encoding.
+	Hash string
+	// Fields is the record as returned by the API.
+	Fields map[string]interface{}
+}
+
+// Option customizes a Follower.
+type Option func(*Follower)
+
+// WithConditions filters the followed logs, e.g. to a workload. The
+// conditions are sent as the search Sources.
+func WithConditions(conditions *utils.ConditionSet) Option {
+	return func(f *Follower) {
+		f.sources = conditions.Build()
+	}
+}
+
+// WithQuery sets the LogsQL query of every search.
+func WithQuery(query string) Option {
+	return func(f *Follower) {
+		f.query = query
+	}
+}
+
+// WithInterval sets the time between searches. Defaults to DefaultInterval.
+func WithInterval(interval time.Duration) Option {
+	return func(f *Follower) {
+		f.interval = interval
+	}
+}
+
+// WithOverlap sets how far each window reaches back into the previous one to
+// cover ingestion lag. Defaults to DefaultOverlap.
+func WithOverlap(overlap time.Duration) Option {
+	return func(f *Follower) {
+		f.overlap = overlap
+	}
+}
+
+// WithLookback sets how far before the start of Follow the first window
+// begins. Defaults to DefaultLookback.
+func WithLookback(lookback time.Duration) Option {
+	return func(f *Follower) {
+		f.lookback = lookback
+	}
+}
+
+// WithTimestampFields sets the record fields searched, in order, for the
+// record time. Defaults to DefaultTimestampFields.
+func WithTimestampFields(fields ...string) Option {
+	return func(f *Follower) {
+		f.timestampFields = fields
+	}
+}
+
+// WithBufferSize sets the capacity of the channel returned by Follow.
+func WithBufferSize(size int) Option {
+	return func(f *Follower) {
+		f.bufferSize = size
+	}
+}
+
+// Follower follows logs matching a query and conditions.
+type Follower struct {
+	searcher        Searcher
+	query           string
+	sources         []*models.Condition
+	interval        time.Duration
+	overlap         time.Duration
+	lookback        time.Duration
+	timestampFields []string
+	bufferSize      int
+	now             func() time.Time
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewFollower creates a Follower searching through searcher.
+func NewFollower(searcher Searcher, options ...Option) *Follower {
+	f := &Follower{
+		searcher:        searcher,
+		sources:         []*models.Condition{},
+		interval:        DefaultInterval,
+		overlap:         DefaultOverlap,
+		lookback:        DefaultLookback,
+		timestampFields: DefaultTimestampFields,
+		now:             time.Now,
+	}
+	for _, option := range options {
+		option(f)
+	}
+	return f
+}
+
+// Follow starts following and returns the channel of new records. Records of
+// a window are emitted in timestamp order; records ingested late can be
+// older than records emitted by a previous window. The channel is closed when
+// ctx is done or a search fails, after which Err reports the failure.
+func (f *Follower) Follow(ctx context.Context) <-chan Record {
+	records := make(chan Record, f.bufferSize)
+	go func() {
+		defer close(records)
+		f.setErr(f.follow(ctx, records))
+	}()
+	return records
+}
+
+// Err returns the error that stopped the follower, or nil if it stopped
+// because its context was done.
+func (f *Follower) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+func (f *Follower) setErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *Follower) follow(ctx context.Context, records chan<- Record) error {
+	p := &poller.Poller[Record]{
+		Interval: f.interval,
+		Overlap:  f.overlap,
+		Lookback: f.lookback,
+		Now:      f.now,
+		Key:      Record.key,
+		Poll: func(ctx context.Context, start, end time.Time) iter.Seq2[Record, error] {
+			return func(yield func(Record, error) bool) {
+				batch, err := f.search(ctx, start, end)
+				if err != nil {
+					yield(Record{}, err)
+					return
+				}
+				for _, record := range batch {
+					if !yield(record, nil) {
+						return
+					}
+				}
+			}
+		},
+	}
+	return p.Run(ctx, records)
+}
+
+// search returns the records between start and end, sorted by timestamp.
+func (f *Follower) search(ctx context.Context, start, end time.Time) ([]Record, error) {
+	startTime, endTime := strfmt.DateTime(start.UTC()), strfmt.DateTime(end.UTC())
+	result, err := f.searcher.Search(ctx, &models.LogsSearchRequest{
+		Start:   &startTime,
+		End:     &endTime,
+		Query:   f.query,
+		Sources: f.sources,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := resultRows(result)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := make([]Record, 0, len(rows))
+	for _, row := range rows {
+		fields, ok := row.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected log record of type %T", row)
+		}
+		record, err := f.newRecord(fields)
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, record)
+	}
+
+	sort.SliceStable(batch, func(i, j int) bool {
+		if !batch[i].Timestamp.Equal(batch[j].Timestamp) {
+			return batch[i].Timestamp.Before(batch[j].Timestamp)
+		}
+		return batch[i].Hash < batch[j].Hash
+	})
+	return batch, nil
+}
+
+// resultRows extracts the records of a search response, either a bare list
+// or an object holding the list under "logs".
+func resultRows(result interface{}) ([]interface{}, error) {
+	switch v := result.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		if rows, ok := v["logs"].([]interface{}); ok || v["logs"] == nil {
+			return rows, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected logs search response of type %T", result)
+}
+
+func (f *Follower) newRecord(fields map[string]interface{}) (Record, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return Record{}, fmt.Errorf("error encoding log record: %w", err)
+	}
+	sum := sha256.Sum256(data)
+
+	record := Record{Hash: hex.EncodeToString(sum[:]), Fields: fields}
+	record.Timestamp, _ = logrecord.Timestamp(fields, f.timestampFields)
+	return record, nil
+}
+
+func (r Record) key() string {
+	if r.Timestamp.IsZero() {
+		return r.Hash
+	}
+	return strconv.FormatInt(r.Timestamp.UnixNano(), 10) + "/" + r.Hash
+}
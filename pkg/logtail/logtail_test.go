@@ -0,0 +1,155 @@
+package logtail
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/utils"
+)
+
+// scriptedSearcher returns one scripted response per call and then empty results.
+type scriptedSearcher struct {
+	mu        sync.Mutex
+	responses []interface{}
+	err       error
+	requests  []*models.LogsSearchRequest
+}
+
+func (s *scriptedSearcher) Search(ctx context.Context, request *models.LogsSearchRequest) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, request)
+	if len(s.responses) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return []interface{}{}, nil
+	}
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	return response, nil
+}
+
+func (s *scriptedSearcher) calls() []*models.LogsSearchRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*models.LogsSearchRequest{}, s.requests...)
+}
+
+func logRecord(timestamp, content string) map[string]interface{} {
+	return map[string]interface{}{"timestamp": timestamp, "content": content}
+}
+
+func TestFollowDeduplicatesAndOrders(t *testing.T) {
+	searcher := &scriptedSearcher{responses: []interface{}{
+		[]interface{}{
+			logRecord("2024-01-01T00:00:02Z", "second"),
+			logRecord("2024-01-01T00:00:01Z", "first"),
+		},
+		// The overlapping window returns the same records plus a late one.
+		[]interface{}{
+			logRecord("2024-01-01T00:00:01Z", "first"),
+			logRecord("2024-01-01T00:00:02Z", "second"),
+			logRecord("2024-01-01T00:00:02Z", "second, other content"),
+			logRecord("2024-01-01T00:00:03Z", "third"),
+		},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	follower := NewFollower(searcher, WithInterval(time.Millisecond))
+	records := follower.Follow(ctx)
+
+	var contents []string
+	for record := range records {
+		contents = append(contents, record.Fields["content"].(string))
+		if len(contents) == 4 {
+			cancel()
+		}
+	}
+
+	if len(contents) != 4 {
+		t.Fatalf("Expected 4 records, got %v", contents)
+	}
+	if contents[0] != "first" || contents[1] != "second" || contents[3] != "third" {
+		t.Errorf("Unexpected record order %v", contents)
+	}
+	if contents[2] != "second, other content" {
+		t.Errorf("Expected record with the same timestamp but other content, got %q", contents[2])
+	}
+	if err := follower.Err(); err != nil {
+		t.Errorf("Expected nil error after cancel, got %v", err)
+	}
+}
+
+func TestFollowMovesOverlappingWindow(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	ticks := 0
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		ticks++
+		return base.Add(time.Duration(ticks) * time.Minute)
+	}
+
+	searcher := &scriptedSearcher{}
+	conditions := utils.NewConditionSet().Add("workload", "checkout")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	follower := NewFollower(searcher,
+		WithConditions(conditions),
+		WithQuery("level:error"),
+		WithInterval(time.Millisecond),
+		WithOverlap(10*time.Second),
+		WithLookback(time.Minute),
+	)
+	follower.now = now
+	records := follower.Follow(ctx)
+
+	for len(searcher.calls()) < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	for range records {
+	}
+
+	calls := searcher.calls()
+	// Follow starts at tick 1, so the first window starts one lookback and one overlap earlier.
+	first := calls[0]
+	if got, want := time.Time(*first.Start), base.Add(-10*time.Second); !got.Equal(want) {
+		t.Errorf("Expected first start %v, got %v", want, got)
+	}
+	if got, want := time.Time(*first.End), base.Add(2*time.Minute); !got.Equal(want) {
+		t.Errorf("Expected first end %v, got %v", want, got)
+	}
+	second := calls[1]
+	if got, want := time.Time(*second.Start), time.Time(*first.End).Add(-10*time.Second); !got.Equal(want) {
+		t.Errorf("Expected second start %v, got %v", want, got)
+	}
+
+	if first.Query != "level:error" {
+		t.Errorf("Expected query to be sent, got %q", first.Query)
+	}
+	if len(first.Sources) != 1 || first.Sources[0].Key != "workload" {
+		t.Errorf("Expected workload condition in sources, got %+v", first.Sources)
+	}
+}
+
+func TestFollowStopsOnSearchError(t *testing.T) {
+	searchErr := errors.New("backend unavailable")
+	searcher := &scriptedSearcher{err: searchErr}
+
+	follower := NewFollower(searcher, WithInterval(time.Millisecond))
+	for range follower.Follow(context.Background()) {
+	}
+
+	if !errors.Is(follower.Err(), searchErr) {
+		t.Errorf("Expected search error, got %v", follower.Err())
+	}
+}
@@ -0,0 +1,831 @@
+package ottl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Env is the data a statement or condition runs against.
+//
+// Record is the log record as a JSON-like map: a path's first segment is a
+// top-level field and further segments and keys descend into nested maps and
+// lists, so resource.attributes["k8s.pod.name"] reads
+// Record["resource"]["attributes"]["k8s.pod.name"]. The cache path reads and
+// writes Cache instead, which is not part of the record.
+type Env struct {
+	Record map[string]interface{}
+	Cache  map[string]interface{}
+}
+
+// function describes a supported editor or converter.
+type function struct {
+	minArgs, maxArgs int
+	// regexArg is the index of an argument that must be a valid regular
+	// expression when it is a literal, or -1.
+	regexArg int
+	editor   func(env *Env, args []Expr) error
+	convert  func(args []interface{}) (interface{}, error)
+}
+
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"set":             {minArgs: 2, maxArgs: 2, regexArg: -1, editor: editSet},
+		"delete_key":      {minArgs: 2, maxArgs: 2, regexArg: -1, editor: editDeleteKey},
+		"replace_pattern": {minArgs: 3, maxArgs: 3, regexArg: 1, editor: editReplacePattern},
+		"merge_maps":      {minArgs: 3, maxArgs: 3, regexArg: -1, editor: editMergeMaps},
+		"ParseJSON":       {minArgs: 1, maxArgs: 1, regexArg: -1, convert: convertParseJSON},
+		"ExtractPatterns": {minArgs: 2, maxArgs: 2, regexArg: 1, convert: convertExtractPatterns},
+		"IsMatch":         {minArgs: 2, maxArgs: 2, regexArg: 1, convert: convertIsMatch},
+		"Concat":          {minArgs: 2, maxArgs: 2, regexArg: -1, convert: convertConcat},
+	}
+}
+
+// Functions returns the names of the editors and converters the interpreter supports.
+func Functions() []string {
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check reports calls the interpreter does not support: unknown functions,
+// wrong argument counts, named arguments and invalid literal patterns.
+func Check(statement *Statement) error {
+	if err := checkExpr(statement.Editor); err != nil {
+		return err
+	}
+	if statement.Condition != nil {
+		return checkExpr(statement.Condition)
+	}
+	return nil
+}
+
+// CheckCondition is Check for conditions.
+func CheckCondition(condition Expr) error {
+	return checkExpr(condition)
+}
+
+func checkExpr(e Expr) error {
+	switch v := e.(type) {
+	case *Call:
+		fn, ok := functions[v.Name]
+		if !ok {
+			return fmt.Errorf("ottl: unsupported function %s", v.Name)
+		}
+		if len(v.Args) < fn.minArgs || len(v.Args) > fn.maxArgs {
+			return fmt.Errorf("ottl: %s expects %d arguments, got %d", v.Name, fn.minArgs, len(v.Args))
+		}
+		for i, arg := range v.Args {
+			if arg.Name != "" {
+				return fmt.Errorf("ottl: %s: named arguments are not supported", v.Name)
+			}
+			if i == fn.regexArg {
+				if literal, ok := arg.Value.(*Literal); ok {
+					pattern, isString := literal.Value.(string)
+					if !isString {
+						return fmt.Errorf("ottl: %s: pattern must be a string", v.Name)
+					}
+					if _, err := regexp.Compile(pattern); err != nil {
+						return fmt.Errorf("ottl: %s: %w", v.Name, err)
+					}
+				}
+			}
+			if err := checkExpr(arg.Value); err != nil {
+				return err
+			}
+		}
+		return checkExprs(v.Keys)
+	case *Path:
+		for _, segment := range v.Segments {
+			if err := checkExprs(segment.Keys); err != nil {
+				return err
+			}
+		}
+	case *List:
+		return checkExprs(v.Items)
+	case *Map:
+		return checkExprs(v.Values)
+	case *Binary:
+		if err := checkExpr(v.Left); err != nil {
+			return err
+		}
+		return checkExpr(v.Right)
+	case *Not:
+		return checkExpr(v.Expr)
+	case *Negate:
+		return checkExpr(v.Expr)
+	}
+	return nil
+}
+
+func checkExprs(exprs []Expr) error {
+	for _, e := range exprs {
+		if err := checkExpr(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute runs the statement against env. It returns false without running
+// the editor when the where clause does not hold.
+func (s *Statement) Execute(env *Env) (bool, error) {
+	if s.Condition != nil {
+		ok, err := evaluateCondition(s.Condition, env)
+		if err != nil || !ok {
+			return false, wrapEvalError(err)
+		}
+	}
+	fn, ok := functions[s.Editor.Name]
+	if !ok || fn.editor == nil {
+		return false, fmt.Errorf("ottl: unsupported editor %s", s.Editor.Name)
+	}
+	args := make([]Expr, len(s.Editor.Args))
+	for i, arg := range s.Editor.Args {
+		args[i] = arg.Value
+	}
+	if err := fn.editor(env, args); err != nil {
+		return true, fmt.Errorf("ottl: %s: %w", s.Editor.Name, err)
+	}
+	return true, nil
+}
+
+// EvaluateCondition evaluates a condition, which must yield a bool.
+func EvaluateCondition(condition Expr, env *Env) (bool, error) {
+	result, err := evaluateCondition(condition, env)
+	return result, wrapEvalError(err)
+}
+
+// Evaluate returns the value of e. Missing paths and keys yield nil.
+func Evaluate(e Expr, env *Env) (interface{}, error) {
+	value, err := evaluate(e, env)
+	return value, wrapEvalError(err)
+}
+
+func wrapEvalError(err error) error {
+	if err != nil {
+		return fmt.Errorf("ottl: %w", err)
+	}
+	return nil
+}
+
+func evaluateCondition(condition Expr, env *Env) (bool, error) {
+	value, err := evaluate(condition, env)
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("condition %s is %s, not a bool", condition, typeName(value))
+	}
+	return result, nil
+}
+
+func evaluate(e Expr, env *Env) (interface{}, error) {
+	switch v := e.(type) {
+	case *Literal:
+		return v.Value, nil
+	case *Enum:
+		return v.Name, nil
+	case *Path:
+		return readPath(env, v)
+	case *List:
+		items := make([]interface{}, len(v.Items))
+		for i, item := range v.Items {
+			value, err := evaluate(item, env)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = value
+		}
+		return items, nil
+	case *Map:
+		m := make(map[string]interface{}, len(v.Keys))
+		for i, key := range v.Keys {
+			value, err := evaluate(v.Values[i], env)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = value
+		}
+		return m, nil
+	case *Call:
+		return evaluateCall(v, env)
+	case *Not:
+		value, err := evaluateCondition(v.Expr, env)
+		return !value, err
+	case *Negate:
+		value, err := evaluate(v.Expr, env)
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic("-", int64(0), value)
+	case *Binary:
+		return evaluateBinary(v, env)
+	}
+	return nil, fmt.Errorf("cannot evaluate %s", e)
+}
+
+func evaluateCall(call *Call, env *Env) (interface{}, error) {
+	fn, ok := functions[call.Name]
+	if !ok || fn.convert == nil {
+		return nil, fmt.Errorf("unsupported converter %s", call.Name)
+	}
+	args := make([]interface{}, len(call.Args))
+	for i, arg := range call.Args {
+		value, err := evaluate(arg.Value, env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	value, err := fn.convert(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", call.Name, err)
+	}
+	return index(env, value, call.Keys)
+}
+
+func evaluateBinary(b *Binary, env *Env) (interface{}, error) {
+	left, err := evaluate(b.Left, env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch b.Op {
+	case "and", "or":
+		l, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operand %s of %s is %s, not a bool", b.Left, b.Op, typeName(left))
+		}
+		if (b.Op == "and" && !l) || (b.Op == "or" && l) {
+			return l, nil
+		}
+		return evaluateCondition(b.Right, env)
+	}
+
+	right, err := evaluate(b.Right, env)
+	if err != nil {
+		return nil, err
+	}
+	switch b.Op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compare(b.Op, left, right), nil
+	default:
+		return arithmetic(b.Op, left, right)
+	}
+}
+
+// equal compares values like OTTL: numbers by value, other values by type and value.
+func equal(left, right interface{}) bool {
+	if l, ok := toFloat(left); ok {
+		r, ok := toFloat(right)
+		return ok && l == r
+	}
+	switch l := left.(type) {
+	case nil, string, bool:
+		return left == right
+	case []byte:
+		r, ok := right.([]byte)
+		return ok && bytes.Equal(l, r)
+	default:
+		lj, err1 := json.Marshal(left)
+		rj, err2 := json.Marshal(right)
+		return err1 == nil && err2 == nil && bytes.Equal(lj, rj)
+	}
+}
+
+// compare orders numbers and strings; other operands compare false.
+func compare(op string, left, right interface{}) bool {
+	var c int
+	if l, ok := toFloat(left); ok {
+		r, ok := toFloat(right)
+		if !ok {
+			return false
+		}
+		switch {
+		case l < r:
+			c = -1
+		case l > r:
+			c = 1
+		}
+	} else {
+		l, lok := left.(string)
+		r, rok := right.(string)
+		if !lok || !rok {
+			return false
+		}
+		c = strings.Compare(l, r)
+	}
+
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+func arithmetic(op string, left, right interface{}) (interface{}, error) {
+	li, lInt := left.(int64)
+	ri, rInt := right.(int64)
+	if lInt && rInt {
+		switch op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		case "/":
+			if ri == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return li / ri, nil
+		}
+	}
+
+	l, lok := toFloat(left)
+	r, rok := toFloat(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", op, typeName(left), typeName(right))
+	}
+	switch op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	default:
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return "a string"
+	case bool:
+		return "a bool"
+	case int64, int, float64, json.Number:
+		return "a number"
+	case map[string]interface{}:
+		return "a map"
+	case []interface{}:
+		return "a list"
+	case []byte:
+		return "bytes"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// readPath returns the value at path, or nil when any step is missing.
+func readPath(env *Env, path *Path) (interface{}, error) {
+	root, segments := pathRoot(env, path)
+	var current interface{} = root
+	for _, segment := range segments {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		value, err := index(env, m[segment.Name], segment.Keys)
+		if err != nil {
+			return nil, err
+		}
+		current = value
+	}
+	return current, nil
+}
+
+// pathRoot returns the map a path starts from and its remaining segments:
+// a cache path reads the cache with the keys of its first segment.
+func pathRoot(env *Env, path *Path) (map[string]interface{}, []PathSegment) {
+	if path.Segments[0].Name == "cache" {
+		if env.Cache == nil {
+			env.Cache = map[string]interface{}{}
+		}
+		first := PathSegment{Name: "cache", Keys: path.Segments[0].Keys}
+		return map[string]interface{}{"cache": env.Cache}, append([]PathSegment{first}, path.Segments[1:]...)
+	}
+	if env.Record == nil {
+		env.Record = map[string]interface{}{}
+	}
+	return env.Record, path.Segments
+}
+
+// index applies keys to value.
+func index(env *Env, value interface{}, keys []Expr) (interface{}, error) {
+	for _, keyExpr := range keys {
+		key, err := evaluate(keyExpr, env)
+		if err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			k, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key %s must be a string", keyExpr)
+			}
+			value = v[k]
+		case []interface{}:
+			i, ok := key.(int64)
+			if !ok {
+				return nil, fmt.Errorf("list index %s must be an int", keyExpr)
+			}
+			if i < 0 || int(i) >= len(v) {
+				return nil, nil
+			}
+			value = v[i]
+		default:
+			return nil, nil
+		}
+	}
+	return value, nil
+}
+
+// writePath sets the value at path, creating intermediate maps.
+func writePath(env *Env, path *Path, value interface{}) error {
+	root, segments := pathRoot(env, path)
+	var container interface{} = root
+	var step interface{} // the key, string or int64, applied to container
+
+	descend := func(key interface{}) error {
+		if step != nil {
+			next, err := child(container, step, true)
+			if err != nil {
+				return err
+			}
+			container = next
+		}
+		step = key
+		return nil
+	}
+
+	for _, segment := range segments {
+		if err := descend(segment.Name); err != nil {
+			return err
+		}
+		for _, keyExpr := range segment.Keys {
+			key, err := evaluate(keyExpr, env)
+			if err != nil {
+				return err
+			}
+			if err := descend(key); err != nil {
+				return err
+			}
+		}
+	}
+	if err := assign(container, step, value); err != nil {
+		return err
+	}
+	if path.Segments[0].Name == "cache" {
+		cache, ok := root["cache"].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("cache must be a map, got %s", typeName(root["cache"]))
+		}
+		env.Cache = cache
+	}
+	return nil
+}
+
+// child returns container[key], creating a map when create is set.
+func child(container, key interface{}, create bool) (interface{}, error) {
+	switch c := container.(type) {
+	case map[string]interface{}:
+		k, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key must be a string, got %v", key)
+		}
+		next, ok := c[k]
+		if !ok || next == nil {
+			if !create {
+				return nil, nil
+			}
+			next = map[string]interface{}{}
+			c[k] = next
+		}
+		return next, nil
+	case []interface{}:
+		i, ok := key.(int64)
+		if !ok || i < 0 || int(i) >= len(c) {
+			return nil, fmt.Errorf("list index %v out of range", key)
+		}
+		return c[i], nil
+	default:
+		return nil, fmt.Errorf("cannot index %s with %v", typeName(container), key)
+	}
+}
+
+func assign(container, key, value interface{}) error {
+	switch c := container.(type) {
+	case map[string]interface{}:
+		k, ok := key.(string)
+		if !ok {
+			return fmt.Errorf("map key must be a string, got %v", key)
+		}
+		c[k] = value
+		return nil
+	case []interface{}:
+		i, ok := key.(int64)
+		if !ok || i < 0 || int(i) >= len(c) {
+			return fmt.Errorf("list index %v out of range", key)
+		}
+		c[i] = value
+		return nil
+	default:
+		return fmt.Errorf("cannot set a key of %s", typeName(container))
+	}
+}
+
+func targetPath(e Expr) (*Path, error) {
+	path, ok := e.(*Path)
+	if !ok {
+		return nil, fmt.Errorf("target %s is not a path", e)
+	}
+	return path, nil
+}
+
+// editSet implements set(target, value). A nil value leaves target unchanged.
+func editSet(env *Env, args []Expr) error {
+	target, err := targetPath(args[0])
+	if err != nil {
+		return err
+	}
+	value, err := evaluate(args[1], env)
+	if err != nil || value == nil {
+		return err
+	}
+	return writePath(env, target, value)
+}
+
+// editDeleteKey implements delete_key(target, key).
+func editDeleteKey(env *Env, args []Expr) error {
+	target, err := targetPath(args[0])
+	if err != nil {
+		return err
+	}
+	m, err := readMap(env, target)
+	if err != nil || m == nil {
+		return err
+	}
+	key, err := evaluate(args[1], env)
+	if err != nil {
+		return err
+	}
+	k, ok := key.(string)
+	if !ok {
+		return fmt.Errorf("key must be a string, got %s", typeName(key))
+	}
+	delete(m, k)
+	return nil
+}
+
+// editReplacePattern implements replace_pattern(target, regex, replacement).
+// The replacement can refer to groups as $1 or ${name}.
+func editReplacePattern(env *Env, args []Expr) error {
+	target, err := targetPath(args[0])
+	if err != nil {
+		return err
+	}
+	value, err := readPath(env, target)
+	if err != nil {
+		return err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	pattern, err := evaluateRegexp(env, args[1])
+	if err != nil {
+		return err
+	}
+	replacement, err := evaluate(args[2], env)
+	if err != nil {
+		return err
+	}
+	r, ok := replacement.(string)
+	if !ok {
+		return fmt.Errorf("replacement must be a string, got %s", typeName(replacement))
+	}
+	if replaced := pattern.ReplaceAllString(s, r); replaced != s {
+		return writePath(env, target, replaced)
+	}
+	return nil
+}
+
+// editMergeMaps implements merge_maps(target, source, strategy) with the
+// insert, update and upsert strategies.
+func editMergeMaps(env *Env, args []Expr) error {
+	target, err := targetPath(args[0])
+	if err != nil {
+		return err
+	}
+	source, err := evaluate(args[1], env)
+	if err != nil {
+		return err
+	}
+	sourceMap, ok := source.(map[string]interface{})
+	if !ok {
+		if source == nil {
+			return nil
+		}
+		return fmt.Errorf("source must be a map, got %s", typeName(source))
+	}
+	if len(sourceMap) == 0 {
+		return nil
+	}
+	strategy, err := evaluate(args[2], env)
+	if err != nil {
+		return err
+	}
+
+	targetMap, err := readMap(env, target)
+	if err != nil {
+		return err
+	}
+	if targetMap == nil {
+		targetMap = map[string]interface{}{}
+		if err := writePath(env, target, targetMap); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range sortedKeys(sourceMap) {
+		_, exists := targetMap[key]
+		switch strategy {
+		case "insert":
+			if exists {
+				continue
+			}
+		case "update":
+			if !exists {
+				continue
+			}
+		case "upsert":
+		default:
+			return fmt.Errorf("unknown strategy %v, expected insert, update or upsert", strategy)
+		}
+		targetMap[key] = sourceMap[key]
+	}
+	return nil
+}
+
+func readMap(env *Env, path *Path) (map[string]interface{}, error) {
+	value, err := readPath(env, path)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("target %s is %s, not a map", path, typeName(value))
+	}
+	return m, nil
+}
+
+func evaluateRegexp(env *Env, e Expr) (*regexp.Regexp, error) {
+	value, err := evaluate(e, env)
+	if err != nil {
+		return nil, err
+	}
+	pattern, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("pattern must be a string, got %s", typeName(value))
+	}
+	return regexp.Compile(pattern)
+}
+
+// convertParseJSON implements ParseJSON(target): a JSON object string to a map.
+func convertParseJSON(args []interface{}) (interface{}, error) {
+	s, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("target must be a string, got %s", typeName(args[0]))
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		return nil, fmt.Errorf("target is not a JSON object: %w", err)
+	}
+	return m, nil
+}
+
+// convertExtractPatterns implements ExtractPatterns(target, pattern): the
+// named groups of the first match, as a map.
+func convertExtractPatterns(args []interface{}) (interface{}, error) {
+	s, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("target must be a string, got %s", typeName(args[0]))
+	}
+	pattern, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("pattern must be a string, got %s", typeName(args[1]))
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	named := false
+	for _, name := range re.SubexpNames() {
+		named = named || name != ""
+	}
+	if !named {
+		return nil, fmt.Errorf("pattern %q has no named capture groups", pattern)
+	}
+
+	result := map[string]interface{}{}
+	match := re.FindStringSubmatch(s)
+	if match == nil {
+		return result, nil
+	}
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			result[name] = match[i]
+		}
+	}
+	return result, nil
+}
+
+// convertIsMatch implements IsMatch(target, pattern).
+func convertIsMatch(args []interface{}) (interface{}, error) {
+	pattern, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("pattern must be a string, got %s", typeName(args[1]))
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	switch target := args[0].(type) {
+	case nil:
+		return false, nil
+	case string:
+		return re.MatchString(target), nil
+	default:
+		return re.MatchString(fmt.Sprint(target)), nil
+	}
+}
+
+// convertConcat implements Concat(values, delimiter).
+func convertConcat(args []interface{}) (interface{}, error) {
+	values, ok := args[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("values must be a list, got %s", typeName(args[0]))
+	}
+	delimiter, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("delimiter must be a string, got %s", typeName(args[1]))
+	}
+	parts := make([]string, len(values))
+	for i, value := range values {
+		if value != nil {
+			parts[i] = fmt.Sprint(value)
+		}
+	}
+	return strings.Join(parts, delimiter), nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,172 @@
+package ottl
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenInt
+	tokenFloat
+	tokenBytes
+	tokenPunct
+)
+
+type token struct {
+	kind  tokenKind
+	text  string // identifier, punctuation or number text; unquoted string value
+	pos   int    // byte offset in the input
+	bytes []byte
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// lex splits input into tokens.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '"':
+			value, end, err := lexString(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, pos: i})
+			i = end
+
+		case c == '0' && i+1 < len(input) && (input[i+1] == 'x' || input[i+1] == 'X'):
+			end := i + 2
+			for end < len(input) && isHexDigit(input[end]) {
+				end++
+			}
+			digits := input[i+2 : end]
+			if digits == "" || len(digits)%2 != 0 {
+				return nil, syntaxErrorf(input, i, "invalid bytes literal %q", input[i:end])
+			}
+			value := make([]byte, len(digits)/2)
+			for j := range value {
+				value[j] = hexValue(digits[2*j])<<4 | hexValue(digits[2*j+1])
+			}
+			tokens = append(tokens, token{kind: tokenBytes, text: input[i:end], pos: i, bytes: value})
+			i = end
+
+		case isDigit(c):
+			end, kind := lexNumber(input, i)
+			tokens = append(tokens, token{kind: kind, text: input[i:end], pos: i})
+			i = end
+
+		case c == '_' || isLetter(c):
+			end := i + 1
+			for end < len(input) && (input[end] == '_' || isLetter(input[end]) || isDigit(input[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: input[i:end], pos: i})
+			i = end
+
+		default:
+			punct := ""
+			for _, p := range []string{"==", "!=", "<=", ">=", "(", ")", "[", "]", "{", "}", ",", ".", ":", "=", "<", ">", "+", "-", "*", "/"} {
+				if strings.HasPrefix(input[i:], p) {
+					punct = p
+					break
+				}
+			}
+			if punct == "" {
+				return nil, syntaxErrorf(input, i, "unexpected character %q", rune(c))
+			}
+			tokens = append(tokens, token{kind: tokenPunct, text: punct, pos: i})
+			i += len(punct)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// lexString reads the double-quoted string starting at start.
+func lexString(input string, start int) (string, int, error) {
+	var value strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		switch c := input[i]; c {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\\':
+			if i+1 == len(input) {
+				break
+			}
+			i++
+			switch input[i] {
+			case '"', '\\':
+				value.WriteByte(input[i])
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			case 'r':
+				value.WriteByte('\r')
+			default:
+				// Regular expressions keep their escapes, e.g. "\\d+" or "\d+".
+				value.WriteByte('\\')
+				value.WriteByte(input[i])
+			}
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", 0, syntaxErrorf(input, start, "unterminated string")
+}
+
+func lexNumber(input string, start int) (int, tokenKind) {
+	end := start
+	for end < len(input) && isDigit(input[end]) {
+		end++
+	}
+	if end+1 < len(input) && input[end] == '.' && isDigit(input[end+1]) {
+		end++
+		for end < len(input) && isDigit(input[end]) {
+			end++
+		}
+		return end, tokenFloat
+	}
+	return end, tokenInt
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c < 0x80 && unicode.IsLetter(rune(c))
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexValue(c byte) byte {
+	switch {
+	case isDigit(c):
+		return c - '0'
+	case c >= 'a':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
@@ -0,0 +1,676 @@
+// Package ottl parses the OpenTelemetry Transformation Language statements
+// and conditions used by logs pipeline rules.
+//
+// A statement is an editor call with an optional where clause:
+//
+//	set(attributes["env"], "prod") where resource.attributes["k8s.namespace.name"] == "prod"
+//
+// A condition is a boolean expression over paths, literals and converter
+// calls:
+//
+//	IsMatch(body, "timeout") and not (severity_text == "DEBUG")
+//
+// The parser covers the OTTL syntax: string, int, float, bool, nil and bytes
+// literals, paths with keys, enums, lists, maps, converters with keys,
+// named arguments, arithmetic, comparisons and and/or/not.
+//
+// Statements and conditions also run locally against a log record held in an
+// Env; the interpreter supports the functions listed by Functions, and Check
+// reports statements that use anything else.
+package ottl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError reports malformed OTTL.
+type SyntaxError struct {
+	Input  string
+	Offset int // byte offset of the error in Input
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("ottl: syntax error at column %d: %s", e.Offset+1, e.Msg)
+}
+
+func syntaxErrorf(input string, offset int, format string, args ...interface{}) error {
+	return &SyntaxError{Input: input, Offset: offset, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Statement is an editor call applied where its condition holds.
+type Statement struct {
+	Editor *Call
+	// Condition is the where clause, nil when the statement always applies.
+	Condition Expr
+}
+
+func (s *Statement) String() string {
+	if s.Condition == nil {
+		return s.Editor.String()
+	}
+	return s.Editor.String() + " where " + s.Condition.String()
+}
+
+// Expr is an OTTL expression. String returns its canonical source.
+type Expr interface {
+	String() string
+	expr()
+}
+
+// Literal is a string, int64, float64, bool, []byte or nil value.
+type Literal struct {
+	Value interface{}
+}
+
+// Path reads telemetry, e.g. resource.attributes["service.name"].
+type Path struct {
+	Segments []PathSegment
+}
+
+// PathSegment is a name with optional keys, e.g. attributes["a"][0].
+type PathSegment struct {
+	Name string
+	Keys []Expr
+}
+
+// Enum is an upper-case constant such as SHA256.
+type Enum struct {
+	Name string
+}
+
+// Call is an editor (lower-case) or converter (upper-case) call. Converter
+// results can be indexed with Keys.
+type Call struct {
+	Name string
+	Args []Arg
+	Keys []Expr
+}
+
+// Arg is a call argument, named when Name is set.
+type Arg struct {
+	Name  string
+	Value Expr
+}
+
+// List is a list literal.
+type List struct {
+	Items []Expr
+}
+
+// Map is a map literal with ordered keys.
+type Map struct {
+	Keys   []string
+	Values []Expr
+}
+
+// Binary is a logical (and, or), comparison or arithmetic operation.
+type Binary struct {
+	Op          string
+	Left, Right Expr
+}
+
+// Not negates a boolean expression.
+type Not struct {
+	Expr Expr
+}
+
+// Negate is an arithmetic negation.
+type Negate struct {
+	Expr Expr
+}
+
+func (*Literal) expr() {}
+func (*Path) expr()    {}
+func (*Enum) expr()    {}
+func (*Call) expr()    {}
+func (*List) expr()    {}
+func (*Map) expr()     {}
+func (*Binary) expr()  {}
+func (*Not) expr()     {}
+func (*Negate) expr()  {}
+
+func (l *Literal) String() string {
+	switch v := l.Value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return quote(v)
+	case []byte:
+		return fmt.Sprintf("0x%x", v)
+	case float64:
+		s := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+		return s
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (p *Path) String() string {
+	parts := make([]string, len(p.Segments))
+	for i, segment := range p.Segments {
+		parts[i] = segment.Name + formatKeys(segment.Keys)
+	}
+	return strings.Join(parts, ".")
+}
+
+func (e *Enum) String() string {
+	return e.Name
+}
+
+func (c *Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = arg.Value.String()
+		if arg.Name != "" {
+			args[i] = arg.Name + " = " + args[i]
+		}
+	}
+	return c.Name + "(" + strings.Join(args, ", ") + ")" + formatKeys(c.Keys)
+}
+
+// IsEditor reports whether c calls an editor rather than a converter.
+func (c *Call) IsEditor() bool {
+	return c.Name != "" && c.Name[0] >= 'a' && c.Name[0] <= 'z'
+}
+
+func (l *List) String() string {
+	items := make([]string, len(l.Items))
+	for i, item := range l.Items {
+		items[i] = item.String()
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func (m *Map) String() string {
+	entries := make([]string, len(m.Keys))
+	for i, key := range m.Keys {
+		entries[i] = quote(key) + ": " + m.Values[i].String()
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+func (b *Binary) String() string {
+	return operand(b.Left, b.Op, false) + " " + b.Op + " " + operand(b.Right, b.Op, true)
+}
+
+func (n *Not) String() string {
+	if _, ok := n.Expr.(*Binary); ok {
+		return "not (" + n.Expr.String() + ")"
+	}
+	return "not " + n.Expr.String()
+}
+
+func (n *Negate) String() string {
+	return "-" + operand(n.Expr, "neg", false)
+}
+
+// precedence orders operators from loosest to tightest.
+var precedence = map[string]int{
+	"or": 1, "and": 2, "not": 3,
+	"==": 4, "!=": 4, "<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5, "*": 6, "/": 6, "neg": 7,
+}
+
+// operand formats e as an operand of op, parenthesized when needed.
+func operand(e Expr, op string, right bool) string {
+	var inner string
+	switch v := e.(type) {
+	case *Binary:
+		inner = v.Op
+	case *Not:
+		inner = "not"
+	default:
+		return e.String()
+	}
+	if precedence[inner] < precedence[op] || (right && precedence[inner] == precedence[op] && inner != "and" && inner != "or") {
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+func formatKeys(keys []Expr) string {
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString("[" + key.String() + "]")
+	}
+	return b.String()
+}
+
+func quote(s string) string {
+	replacer := strings.NewReplacer(`"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+	return `"` + replacer.Replace(s) + `"`
+}
+
+// ParseStatement parses an OTTL statement.
+func ParseStatement(input string) (*Statement, error) {
+	p, err := newParser(input)
+	if err != nil {
+		return nil, err
+	}
+
+	start := p.peek()
+	if start.kind != tokenIdent {
+		return nil, p.errorf(start, "expected an editor call, got %s", start.describe())
+	}
+	editor, err := p.parseCall(p.next())
+	if err != nil {
+		return nil, err
+	}
+	if !editor.IsEditor() {
+		return nil, p.errorf(start, "statement must call an editor, %s is a converter", editor.Name)
+	}
+	if len(editor.Keys) > 0 {
+		return nil, p.errorf(start, "editor %s cannot be indexed", editor.Name)
+	}
+
+	statement := &Statement{Editor: editor}
+	if p.peek().kind == tokenIdent && p.peek().text == "where" {
+		p.next()
+		if statement.Condition, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectEOF(); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+// ParseCondition parses an OTTL condition.
+func ParseCondition(input string) (Expr, error) {
+	p, err := newParser(input)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorf(p.peek(), "empty condition")
+	}
+	condition, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectEOF(); err != nil {
+		return nil, err
+	}
+	return condition, nil
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func newParser(input string) (*parser, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{input: input, tokens: tokens}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return syntaxErrorf(p.input, t.pos, format, args...)
+}
+
+func (p *parser) isPunct(text string) bool {
+	t := p.peek()
+	return t.kind == tokenPunct && t.text == text
+}
+
+func (p *parser) isKeyword(text string) bool {
+	t := p.peek()
+	return t.kind == tokenIdent && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.isPunct(text) {
+		return p.errorf(p.peek(), "expected %q, got %s", text, p.peek().describe())
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) expectEOF() error {
+	if t := p.peek(); t.kind != tokenEOF {
+		return p.errorf(t, "unexpected %s", t.describe())
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: "or", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: "and", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.isKeyword("not") {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind == tokenPunct {
+		switch t.text {
+		case "==", "!=", "<", "<=", ">", ">=":
+			p.next()
+			right, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			return &Binary{Op: t.text, Left: left, Right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseSum() (Expr, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("+") || p.isPunct("-") {
+		op := p.next().text
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseProduct() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("*") || p.isPunct("/") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Binary{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if !p.isPunct("-") {
+		return p.parsePrimary()
+	}
+	p.next()
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if literal, ok := expr.(*Literal); ok {
+		switch v := literal.Value.(type) {
+		case int64:
+			return &Literal{Value: -v}, nil
+		case float64:
+			return &Literal{Value: -v}, nil
+		}
+	}
+	return &Negate{Expr: expr}, nil
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &Literal{Value: t.text}, nil
+	case tokenBytes:
+		return &Literal{Value: t.bytes}, nil
+	case tokenInt:
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid int %s", t.text)
+		}
+		return &Literal{Value: n}, nil
+	case tokenFloat:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid float %s", t.text)
+		}
+		return &Literal{Value: f}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return &Literal{Value: t.text == "true"}, nil
+		case "nil":
+			return &Literal{}, nil
+		case "where", "and", "or", "not":
+			return nil, p.errorf(t, "unexpected keyword %q", t.text)
+		}
+		if p.isPunct("(") {
+			call, err := p.parseCall(t)
+			if err != nil {
+				return nil, err
+			}
+			if call.IsEditor() {
+				return nil, p.errorf(t, "editor %s cannot be used in an expression", call.Name)
+			}
+			return call, nil
+		}
+		return p.parsePath(t)
+	case tokenPunct:
+		switch t.text {
+		case "(":
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return expr, nil
+		case "[":
+			return p.parseList()
+		case "{":
+			return p.parseMap()
+		}
+	}
+	return nil, p.errorf(t, "unexpected %s", t.describe())
+}
+
+// parseCall parses the arguments and keys of the call named by name.
+func (p *parser) parseCall(name token) (*Call, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	call := &Call{Name: name.text}
+	for !p.isPunct(")") {
+		if len(call.Args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		var arg Arg
+		if t := p.peek(); t.kind == tokenIdent && p.tokens[p.pos+1].kind == tokenPunct && p.tokens[p.pos+1].text == "=" {
+			arg.Name = t.text
+			p.next()
+			p.next()
+		}
+		value, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		arg.Value = value
+		call.Args = append(call.Args, arg)
+	}
+	p.next()
+
+	keys, err := p.parseKeys()
+	if err != nil {
+		return nil, err
+	}
+	call.Keys = keys
+	return call, nil
+}
+
+func (p *parser) parsePath(first token) (Expr, error) {
+	keys, err := p.parseKeys()
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 && !p.isPunct(".") && isEnumName(first.text) {
+		return &Enum{Name: first.text}, nil
+	}
+
+	path := &Path{Segments: []PathSegment{{Name: first.text, Keys: keys}}}
+	for p.isPunct(".") {
+		p.next()
+		t := p.next()
+		if t.kind != tokenIdent {
+			return nil, p.errorf(t, "expected a path segment, got %s", t.describe())
+		}
+		keys, err := p.parseKeys()
+		if err != nil {
+			return nil, err
+		}
+		path.Segments = append(path.Segments, PathSegment{Name: t.text, Keys: keys})
+	}
+	return path, nil
+}
+
+func (p *parser) parseKeys() ([]Expr, error) {
+	var keys []Expr
+	for p.isPunct("[") {
+		p.next()
+		t := p.peek()
+		key, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if literal, ok := key.(*Literal); ok {
+			switch literal.Value.(type) {
+			case string, int64:
+			default:
+				return nil, p.errorf(t, "key must be a string or an int, got %s", literal)
+			}
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (p *parser) parseList() (Expr, error) {
+	list := &List{}
+	for !p.isPunct("]") {
+		if len(list.Items) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, item)
+	}
+	p.next()
+	return list, nil
+}
+
+func (p *parser) parseMap() (Expr, error) {
+	m := &Map{}
+	for !p.isPunct("}") {
+		if len(m.Keys) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		key := p.next()
+		if key.kind != tokenString {
+			return nil, p.errorf(key, "map key must be a string, got %s", key.describe())
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		m.Keys = append(m.Keys, key.text)
+		m.Values = append(m.Values, value)
+	}
+	p.next()
+	return m, nil
+}
+
+// isEnumName reports whether name is an upper-case constant such as SHA256.
+func isEnumName(name string) bool {
+	hasLetter := false
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			hasLetter = true
+		case c == '_' || isDigit(c):
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
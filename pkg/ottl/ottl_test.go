@@ -0,0 +1,231 @@
+package ottl
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseStatement(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // canonical form, same as input when empty
+	}{
+		{input: `set(attributes["test.key"], "test-value")`},
+		{input: `delete_key(attributes, "password") where attributes["password"] != nil`},
+		{input: `replace_pattern(body, "\\d{4}-\\d{4}", "****")`, want: `replace_pattern(body, "\d{4}-\d{4}", "****")`},
+		{input: `merge_maps(attributes, ParseJSON(body), "upsert") where IsMatch(body, "^\\{")`, want: `merge_maps(attributes, ParseJSON(body), "upsert") where IsMatch(body, "^\{")`},
+		{input: `set(attributes["level"], ParseJSON(body)["level"]) where format == "JSON"`},
+		{input: `set(attributes["pattern"], ExtractPatterns(body, "user=(?P<user>\\w+)"))`, want: `set(attributes["pattern"], ExtractPatterns(body, "user=(?P<user>\w+)"))`},
+		{input: `set(attributes["hash"], SHA256(body)) where not (a == 1 or b == 2) and c >= -3.5`},
+		{input: `set(resource.attributes["k8s.pod.name"], "x") where resource.attributes["k8s.namespace.name"] == "prod"`},
+		{input: `set(attributes["list"], [1, 2.0, true, nil, 0xbeef])`},
+		{input: `set(attributes["map"], {"a": 1, "b": {"c": "d"}})`},
+		{input: `set(attributes["n"], 1 + 2 * (3 - 4))`},
+		{input: `keep_keys(attributes, ["a", "b"])`},
+		{input: `set(attributes["t"], Time(attributes["ts"], format = "%Y-%m-%d"))`},
+		{input: `set(attributes["id"], Concat([a, b], "-"))`},
+		{input: `set( attributes [ "x" ] , "y" )   where  x==1`, want: `set(attributes["x"], "y") where x == 1`},
+		{input: `set(attributes["e"], STRING)`},
+		{input: "set(attributes[\"q\"], \"say \\\"hi\\\"\\n\")"},
+	}
+
+	for _, tt := range tests {
+		statement, err := ParseStatement(tt.input)
+		if err != nil {
+			t.Errorf("ParseStatement(%s) failed: %v", tt.input, err)
+			continue
+		}
+		want := tt.want
+		if want == "" {
+			want = tt.input
+		}
+		if got := statement.String(); got != want {
+			t.Errorf("ParseStatement(%s) = %s, want %s", tt.input, got, want)
+		}
+		if _, err := ParseStatement(statement.String()); err != nil {
+			t.Errorf("Canonical form %s does not parse: %v", statement, err)
+		}
+	}
+}
+
+func TestParseStatementStructure(t *testing.T) {
+	statement, err := ParseStatement(`set(attributes["a"][0], resource.attributes["b"]) where x == "y"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement.Editor.Name != "set" || len(statement.Editor.Args) != 2 {
+		t.Fatalf("Unexpected editor %+v", statement.Editor)
+	}
+	target, ok := statement.Editor.Args[0].Value.(*Path)
+	if !ok || len(target.Segments) != 1 || len(target.Segments[0].Keys) != 2 {
+		t.Fatalf("Unexpected target %#v", statement.Editor.Args[0].Value)
+	}
+	if key, ok := target.Segments[0].Keys[1].(*Literal); !ok || key.Value != int64(0) {
+		t.Errorf("Expected int key 0, got %#v", target.Segments[0].Keys[1])
+	}
+	condition, ok := statement.Condition.(*Binary)
+	if !ok || condition.Op != "==" {
+		t.Errorf("Unexpected condition %#v", statement.Condition)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		column int
+		msg    string
+	}{
+		{`set(attributes["a"], "b"`, 25, `expected ","`},
+		{`set(attributes["a"], "b)`, 22, "unterminated string"},
+		{`IsMatch(body, "x")`, 1, "must call an editor"},
+		{`attributes["a"]`, 11, `expected "("`},
+		{`set(attributes["a"], delete_key(x, "y"))`, 22, "cannot be used in an expression"},
+		{`set(attributes["a"], "b") when x == 1`, 27, `unexpected "when"`},
+		{`set(attributes[true], "b")`, 16, "key must be a string or an int"},
+		{`set(attributes["a"], "b") where`, 32, "unexpected end of input"},
+		{`set(attributes["a"], "b") where x == `, 38, "unexpected end of input"},
+		{`set(attributes["a"], {a: 1})`, 23, "map key must be a string"},
+		{`set(attributes["a"], #)`, 22, "unexpected character"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseStatement(tt.input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("ParseStatement(%s): expected a syntax error, got %v", tt.input, err)
+			continue
+		}
+		if syntaxErr.Offset+1 != tt.column || !strings.Contains(syntaxErr.Msg, tt.msg) {
+			t.Errorf("ParseStatement(%s): got %v, want column %d and %q", tt.input, err, tt.column, tt.msg)
+		}
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	for _, input := range []string{
+		`container_name == "nginx"`,
+		`IsMatch(body, "timeout") and not (severity_text == "DEBUG")`,
+		`attributes["status"] >= 500 or resource.attributes["env"] != "prod"`,
+		`true`,
+	} {
+		condition, err := ParseCondition(input)
+		if err != nil {
+			t.Errorf("ParseCondition(%s) failed: %v", input, err)
+			continue
+		}
+		if condition.String() != input {
+			t.Errorf("ParseCondition(%s) = %s", input, condition)
+		}
+	}
+
+	for _, input := range []string{``, `a ==`, `a == b c`, `set(a, b)`, `(a == b`} {
+		if _, err := ParseCondition(input); err == nil {
+			t.Errorf("ParseCondition(%q): expected an error", input)
+		}
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	env := &Env{Record: map[string]interface{}{
+		"container_name": "nginx",
+		"body":           "GET /health 200",
+		"attributes":     map[string]interface{}{"status": float64(503), "tags": []interface{}{"a", "b"}},
+		"resource":       map[string]interface{}{"attributes": map[string]interface{}{"env": "prod"}},
+	}}
+
+	tests := []struct {
+		condition string
+		want      bool
+	}{
+		{`container_name == "nginx"`, true},
+		{`container_name != "nginx"`, false},
+		{`attributes["status"] >= 500`, true},
+		{`attributes["status"] == 503 and resource.attributes["env"] == "prod"`, true},
+		{`attributes["missing"] == nil`, true},
+		{`attributes["tags"][1] == "b"`, true},
+		{`not (resource.attributes["env"] == "prod") or IsMatch(body, "health")`, true},
+		{`attributes["status"] + 1 * 2 == 505`, true},
+		{`-attributes["status"] < 0`, true},
+		{`workload == "api"`, false},
+	}
+	for _, tt := range tests {
+		condition, err := ParseCondition(tt.condition)
+		if err != nil {
+			t.Fatalf("ParseCondition(%s) failed: %v", tt.condition, err)
+		}
+		got, err := EvaluateCondition(condition, env)
+		if err != nil || got != tt.want {
+			t.Errorf("EvaluateCondition(%s) = %v, %v; want %v", tt.condition, got, err, tt.want)
+		}
+	}
+
+	condition, _ := ParseCondition(`body`)
+	if _, err := EvaluateCondition(condition, env); err == nil {
+		t.Error("Expected an error for a non-bool condition")
+	}
+}
+
+func TestExecute(t *testing.T) {
+	env := &Env{Record: map[string]interface{}{
+		"body":       `{"level":"warn","user":{"id":7},"password":"hunter2"}`,
+		"attributes": map[string]interface{}{"team": "core"},
+	}}
+
+	for _, statement := range []string{
+		`set(cache, ParseJSON(body))`,
+		`merge_maps(attributes, cache, "insert")`,
+		`delete_key(attributes, "password")`,
+		`set(attributes["user_id"], cache["user"]["id"])`,
+		`merge_maps(attributes, {"team": "edge", "new": "x"}, "update")`,
+		`set(body, "user=alice ip=10.0.0.1 took 35ms")`,
+		`merge_maps(attributes, ExtractPatterns(body, "user=(?P<user>\\w+)"), "upsert")`,
+		`replace_pattern(body, "ip=\\S+", "ip=<redacted>")`,
+		`set(resource.attributes["service.name"], Concat(["svc", attributes["team"]], "-")) where attributes["level"] == "warn"`,
+		`set(attributes["skipped"], true) where attributes["level"] == "error"`,
+	} {
+		parsed, err := ParseStatement(statement)
+		if err != nil {
+			t.Fatalf("ParseStatement(%s) failed: %v", statement, err)
+		}
+		if err := Check(parsed); err != nil {
+			t.Fatalf("Check(%s) failed: %v", statement, err)
+		}
+		if _, err := parsed.Execute(env); err != nil {
+			t.Fatalf("Execute(%s) failed: %v", statement, err)
+		}
+	}
+
+	got, _ := json.Marshal(env.Record)
+	want := `{"attributes":{"level":"warn","team":"edge","user":"alice","user_id":7},` +
+		`"body":"user=alice ip=\u003credacted\u003e took 35ms",` +
+		`"resource":{"attributes":{"service.name":"svc-edge"}}}`
+	if string(got) != want {
+		t.Errorf("Expected record\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	for _, statement := range []string{
+		`keep_keys(attributes, ["a"])`,
+		`set(attributes["a"])`,
+		`set(target = attributes["a"], value = 1)`,
+		`replace_pattern(body, "(", "x")`,
+		`set(attributes["a"], Unknown(body))`,
+	} {
+		parsed, err := ParseStatement(statement)
+		if err != nil {
+			t.Fatalf("ParseStatement(%s) failed: %v", statement, err)
+		}
+		if err := Check(parsed); err == nil {
+			t.Errorf("Check(%s): expected an error", statement)
+		}
+	}
+
+	parsed, _ := ParseStatement(`set(attributes["a"], ExtractPatterns(body, "no groups"))`)
+	env := &Env{Record: map[string]interface{}{"body": "x"}}
+	if _, err := parsed.Execute(env); err == nil || !strings.Contains(err.Error(), "named capture groups") {
+		t.Errorf("Expected a named capture groups error, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package redmetrics
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+	"time"
+)
+
+// DefaultSignificantDigits is the precision of histograms: percentiles are
+// within 0.1% of the recorded values.
+const DefaultSignificantDigits = 3
+
+// Histogram is an HDR histogram of durations. Values are counted in buckets
+// whose width grows with their magnitude, so that any value is known to
+// within a relative error of 10^-digits, whatever the range of values.
+// Only non-empty buckets take memory.
+type Histogram struct {
+	digits int
+	// halfMagnitude is log2 of half the number of sub-buckets of each
+	// power of two.
+	halfMagnitude uint
+	counts        map[int32]int64
+	total         int64
+	sum           time.Duration
+	min, max      time.Duration
+}
+
+// NewHistogram returns an empty histogram with the precision of digits
+// significant decimal digits, between 1 and 5.
+func NewHistogram(digits int) *Histogram {
+	digits = min(max(digits, 1), 5)
+	// Values up to 2*10^digits are counted exactly.
+	largest := 2 * math.Pow10(digits)
+	subBuckets := uint(math.Ceil(math.Log2(largest)))
+	return &Histogram{
+		digits:        digits,
+		halfMagnitude: subBuckets - 1,
+		counts:        map[int32]int64{},
+	}
+}
+
+// Record adds a value. Negative values are recorded as 0.
+func (h *Histogram) Record(d time.Duration) {
+	d = max(d, 0)
+	if h.total == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.counts[h.index(d)]++
+	h.total++
+	h.sum += d
+}
+
+// Merge adds the values of other.
+func (h *Histogram) Merge(other *Histogram) {
+	if other.total == 0 {
+		return
+	}
+	if h.total == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+	for index, count := range other.counts {
+		if other.digits == h.digits {
+			h.counts[index] += count
+		} else {
+			h.counts[h.index(other.lowest(index))] += count
+		}
+	}
+	h.total += other.total
+	h.sum += other.sum
+}
+
+// Count returns the number of values.
+func (h *Histogram) Count() int64 {
+	return h.total
+}
+
+// Min returns the smallest value.
+func (h *Histogram) Min() time.Duration {
+	return h.min
+}
+
+// Max returns the largest value.
+func (h *Histogram) Max() time.Duration {
+	return h.max
+}
+
+// Mean returns the average value.
+func (h *Histogram) Mean() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.total)
+}
+
+// Quantile returns the value below or at which a share q of the values
+// fall, q between 0 and 1: Quantile(0.99) is the p99.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return h.min
+	}
+	rank := int64(math.Ceil(min(q, 1) * float64(h.total)))
+
+	indexes := make([]int32, 0, len(h.counts))
+	for index := range h.counts {
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	var seen int64
+	for _, index := range indexes {
+		seen += h.counts[index]
+		if seen >= rank {
+			return min(h.highest(index), h.max)
+		}
+	}
+	return h.max
+}
+
+// index returns the bucket of d: the bucket is the power of two of d above
+// the exact range, the sub-bucket its leading bits.
+func (h *Histogram) index(d time.Duration) int32 {
+	value := uint64(d)
+	mask := uint64(1)<<(h.halfMagnitude+1) - 1
+	bucket := uint(bits.Len64(value|mask)) - (h.halfMagnitude + 1)
+	sub := value >> bucket
+	return int32((bucket+1)<<h.halfMagnitude) + int32(sub) - int32(1)<<h.halfMagnitude
+}
+
+// bucketOf returns the bucket and sub-bucket of an index.
+func (h *Histogram) bucketOf(index int32) (bucket uint, sub uint64) {
+	half := int32(1) << h.halfMagnitude
+	if index < 2*half {
+		return 0, uint64(index)
+	}
+	return uint(index>>h.halfMagnitude) - 1, uint64(index&(half-1)) + uint64(half)
+}
+
+// lowest returns the smallest value counted at index.
+func (h *Histogram) lowest(index int32) time.Duration {
+	bucket, sub := h.bucketOf(index)
+	return time.Duration(sub << bucket)
+}
+
+// highest returns the largest value counted at index.
+func (h *Histogram) highest(index int32) time.Duration {
+	bucket, sub := h.bucketOf(index)
+	return time.Duration((sub+1)<<bucket - 1)
+}
@@ -0,0 +1,406 @@
+// Package redmetrics computes RED metrics (request rate, error rate and
+// duration percentiles) client-side from traces search results, for
+// groupings and time ranges the backend does not pre-aggregate, such as a
+// single endpoint or a custom span attribute:
+//
+//	aggregator := redmetrics.NewAggregator(
+//		redmetrics.WithGroupBy("service", "http.route"),
+//		redmetrics.WithStep(5*time.Minute),
+//		redmetrics.WithTimeRange(start, end))
+//	result, err := gc.Traces.Search(ctx, request)
+//	...
+//	if err := aggregator.AddRows(export.Rows(result)); err != nil {
+//		...
+//	}
+//	aggregator.WriteTable(os.Stdout)
+//	for _, series := range aggregator.Series() {
+//		...
+//	}
+//
+// Durations are recorded in HDR histograms, so percentiles stay within 0.1%
+// of the exact values in constant memory per group and time bucket.
+package redmetrics
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/tracetree"
+)
+
+// Group-by names that refer to span fields rather than attributes.
+const (
+	GroupService   = "service"
+	GroupNamespace = "namespace"
+	GroupName      = "name"
+	GroupKind      = "kind"
+)
+
+// DefaultGroupBy groups spans by service and span name, the endpoint.
+var DefaultGroupBy = []string{GroupService, GroupName}
+
+// DefaultPercentiles are the percentiles reported for each point.
+var DefaultPercentiles = []float64{50, 95, 99}
+
+// Option customizes an Aggregator.
+type Option func(*Aggregator)
+
+// WithGroupBy sets what spans are grouped by: the span fields "service",
+// "namespace", "name" and "kind", or attribute keys such as "http.route",
+// looked up in the span attributes and then its resource. Spans without an
+// attribute have an empty value. Defaults to DefaultGroupBy; no names make
+// a single group.
+func WithGroupBy(names ...string) Option {
+	return func(a *Aggregator) {
+		a.groupBy = names
+	}
+}
+
+// WithStep buckets spans by start time into intervals of step. Defaults to
+// 0, a single point per group.
+func WithStep(step time.Duration) Option {
+	return func(a *Aggregator) {
+		a.step = step
+	}
+}
+
+// WithTimeRange sets the time range of the search. Buckets are aligned to
+// start, rates are computed over the range and spans starting outside it
+// are ignored. By default buckets are aligned to multiples of the step and
+// the range is that of the span start times.
+func WithTimeRange(start, end time.Time) Option {
+	return func(a *Aggregator) {
+		a.start, a.end = start, end
+	}
+}
+
+// WithPercentiles sets the duration percentiles reported, between 0 and
+// 100. Defaults to DefaultPercentiles.
+func WithPercentiles(percentiles ...float64) Option {
+	return func(a *Aggregator) {
+		a.percentiles = percentiles
+	}
+}
+
+// WithSignificantDigits sets the precision of the duration histograms.
+// Defaults to DefaultSignificantDigits.
+func WithSignificantDigits(digits int) Option {
+	return func(a *Aggregator) {
+		a.digits = digits
+	}
+}
+
+// WithFilter only aggregates the spans for which keep returns true, such
+// as server spans.
+func WithFilter(keep func(*tracetree.Span) bool) Option {
+	return func(a *Aggregator) {
+		a.filter = keep
+	}
+}
+
+// WithFields sets the field names of search records. Defaults to
+// tracetree.DefaultFields.
+func WithFields(fields tracetree.Fields) Option {
+	return func(a *Aggregator) {
+		a.fields = fields
+	}
+}
+
+type spanKey struct {
+	traceID, spanID string
+}
+
+type group struct {
+	values  []string
+	buckets map[int64]*bucket // by bucket start, in unix nanoseconds
+}
+
+type bucket struct {
+	requests, errors int64
+	durations        *Histogram
+}
+
+// Aggregator aggregates spans into RED metrics per group and time bucket.
+type Aggregator struct {
+	groupBy     []string
+	step        time.Duration
+	start, end  time.Time
+	percentiles []float64
+	digits      int
+	filter      func(*tracetree.Span) bool
+	fields      tracetree.Fields
+
+	groups      map[string]*group
+	seen        map[spanKey]struct{}
+	first, last time.Time
+}
+
+// NewAggregator returns an empty Aggregator.
+func NewAggregator(options ...Option) *Aggregator {
+	a := &Aggregator{
+		groupBy:     DefaultGroupBy,
+		percentiles: DefaultPercentiles,
+		digits:      DefaultSignificantDigits,
+		fields:      tracetree.DefaultFields,
+		groups:      map[string]*group{},
+		seen:        map[spanKey]struct{}{},
+	}
+	for _, option := range options {
+		option(a)
+	}
+	return a
+}
+
+// AddResults adds the spans of a traces search payload.
+func (a *Aggregator) AddResults(payload interface{}) error {
+	spans, err := tracetree.ParseSpans(payload, a.fields)
+	if err != nil {
+		return fmt.Errorf("error reading spans: %w", err)
+	}
+	a.AddSpans(spans...)
+	return nil
+}
+
+// AddRows adds the spans of search records, such as those of export.Rows,
+// stopping at the first error.
+func (a *Aggregator) AddRows(rows iter.Seq2[map[string]interface{}, error]) error {
+	i := 0
+	for row, err := range rows {
+		if err != nil {
+			return fmt.Errorf("error reading spans: %w", err)
+		}
+		span, err := tracetree.ParseSpan(row, a.fields)
+		if err != nil {
+			return fmt.Errorf("error reading spans: span %d: %w", i, err)
+		}
+		a.AddSpans(span)
+		i++
+	}
+	return nil
+}
+
+// AddSpans adds spans. Spans already added, by trace and span ID, are ignored.
+func (a *Aggregator) AddSpans(spans ...*tracetree.Span) {
+	for _, span := range spans {
+		if a.filter != nil && !a.filter(span) {
+			continue
+		}
+		if !a.start.IsZero() && (span.Start.Before(a.start) || !span.Start.Before(a.end)) {
+			continue
+		}
+		key := spanKey{span.TraceID, span.SpanID}
+		if _, ok := a.seen[key]; ok {
+			continue
+		}
+		a.seen[key] = struct{}{}
+
+		if a.first.IsZero() || span.Start.Before(a.first) {
+			a.first = span.Start
+		}
+		if span.Start.After(a.last) {
+			a.last = span.Start
+		}
+
+		values := make([]string, len(a.groupBy))
+		for i, name := range a.groupBy {
+			values[i] = groupValue(span, name)
+		}
+		groupKey := strings.Join(values, "\x00")
+		g, ok := a.groups[groupKey]
+		if !ok {
+			g = &group{values: values, buckets: map[int64]*bucket{}}
+			a.groups[groupKey] = g
+		}
+
+		start := bucketKey(a.bucketStart(span.Start))
+		b, ok := g.buckets[start]
+		if !ok {
+			b = &bucket{durations: NewHistogram(a.digits)}
+			g.buckets[start] = b
+		}
+		b.requests++
+		if span.Error {
+			b.errors++
+		}
+		b.durations.Record(span.Duration)
+	}
+}
+
+// bucketStart returns the start of the bucket of t.
+func (a *Aggregator) bucketStart(t time.Time) time.Time {
+	switch {
+	case a.step <= 0 && !a.start.IsZero():
+		return a.start
+	case a.step <= 0:
+		return time.Time{}
+	case !a.start.IsZero():
+		return a.start.Add(t.Sub(a.start) / a.step * a.step)
+	default:
+		return t.Truncate(a.step)
+	}
+}
+
+func bucketKey(start time.Time) int64 {
+	if start.IsZero() {
+		return 0
+	}
+	return start.UnixNano()
+}
+
+func groupValue(span *tracetree.Span, name string) string {
+	switch name {
+	case GroupService:
+		return span.Service
+	case GroupNamespace:
+		return span.Namespace
+	case GroupName:
+		return span.Name
+	case GroupKind:
+		return span.Kind
+	}
+	value, ok := span.Attributes[name]
+	if !ok {
+		value = span.Resource[name]
+	}
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Percentile is a duration percentile.
+type Percentile struct {
+	Percentile float64
+	Value      time.Duration
+}
+
+// Point holds the metrics of a group in a time bucket.
+type Point struct {
+	// Start is the start of the bucket, zero for a single point without a
+	// time range.
+	Start time.Time
+	// Window is the time the rate is computed over: the step, or the time
+	// range for a single point, shortened at the end of the time range.
+	Window   time.Duration
+	Requests int64
+	Errors   int64
+	// Durations holds the span durations.
+	Durations   *Histogram
+	Percentiles []Percentile
+}
+
+// Rate returns the requests per second, or 0 without a window.
+func (p Point) Rate() float64 {
+	if p.Window <= 0 {
+		return 0
+	}
+	return float64(p.Requests) / p.Window.Seconds()
+}
+
+// ErrorRate returns the share of requests that failed.
+func (p Point) ErrorRate() float64 {
+	if p.Requests == 0 {
+		return 0
+	}
+	return float64(p.Errors) / float64(p.Requests)
+}
+
+// Series holds the points of a group, in time order. With a step, every
+// bucket of the time range has a point, empty buckets included.
+type Series struct {
+	// Group maps each group-by name to the value of the group.
+	Group  map[string]string
+	Points []Point
+}
+
+// GroupBy returns the group-by names of the series.
+func (a *Aggregator) GroupBy() []string {
+	return a.groupBy
+}
+
+// Series returns a series per group, sorted by group values.
+func (a *Aggregator) Series() []Series {
+	groups := make([]*group, 0, len(a.groups))
+	for _, g := range a.groups {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return lessValues(groups[i].values, groups[j].values)
+	})
+
+	starts := a.bucketStarts()
+	series := make([]Series, 0, len(groups))
+	for _, g := range groups {
+		s := Series{Group: map[string]string{}}
+		for i, name := range a.groupBy {
+			s.Group[name] = g.values[i]
+		}
+		for _, start := range starts {
+			point := Point{Window: a.window(start), Durations: NewHistogram(a.digits)}
+			if !start.IsZero() {
+				point.Start = start.UTC()
+			}
+			if b, ok := g.buckets[bucketKey(start)]; ok {
+				point.Requests, point.Errors, point.Durations = b.requests, b.errors, b.durations
+			}
+			for _, p := range a.percentiles {
+				point.Percentiles = append(point.Percentiles, Percentile{Percentile: p, Value: point.Durations.Quantile(p / 100)})
+			}
+			s.Points = append(s.Points, point)
+		}
+		series = append(series, s)
+	}
+	return series
+}
+
+// bucketStarts returns the starts of the buckets in the time range.
+func (a *Aggregator) bucketStarts() []time.Time {
+	if a.first.IsZero() {
+		return nil
+	}
+	if a.step <= 0 {
+		return []time.Time{a.bucketStart(a.first)}
+	}
+	first, last := a.bucketStart(a.first), a.bucketStart(a.last)
+	if !a.start.IsZero() {
+		first, last = a.start, a.bucketStart(a.end.Add(-1))
+	}
+	var starts []time.Time
+	for start := first; !start.After(last); start = start.Add(a.step) {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// window returns the length of the bucket starting at start.
+func (a *Aggregator) window(start time.Time) time.Duration {
+	if a.step <= 0 {
+		if !a.start.IsZero() {
+			return a.end.Sub(a.start)
+		}
+		return a.last.Sub(a.first)
+	}
+	if !a.end.IsZero() && a.end.Sub(start) < a.step {
+		return a.end.Sub(start)
+	}
+	return a.step
+}
+
+func lessValues(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
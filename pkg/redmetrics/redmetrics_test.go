@@ -0,0 +1,168 @@
+package redmetrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/export"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/tracetree"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram(3)
+	for i := 1; i <= 100000; i++ {
+		h.Record(time.Duration(i) * time.Microsecond)
+	}
+
+	for _, test := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.5, 50 * time.Millisecond},
+		{0.95, 95 * time.Millisecond},
+		{0.99, 99 * time.Millisecond},
+		{0.999, 99900 * time.Microsecond},
+	} {
+		got := h.Quantile(test.q)
+		if relative := math.Abs(float64(got-test.want)) / float64(test.want); relative > 0.001 {
+			t.Errorf("Quantile(%v) = %v, want %v within 0.1%%", test.q, got, test.want)
+		}
+	}
+	if h.Quantile(0) != time.Microsecond || h.Quantile(1) != 100*time.Millisecond || h.Count() != 100000 {
+		t.Errorf("Unexpected bounds %v, %v, count %d", h.Quantile(0), h.Quantile(1), h.Count())
+	}
+	if mean := h.Mean(); mean != 50000500*time.Nanosecond {
+		t.Errorf("Expected a mean of 50.0005ms, got %v", mean)
+	}
+
+	// Small values are exact.
+	exact := NewHistogram(3)
+	for _, d := range []time.Duration{3, 1, 2, 1500} {
+		exact.Record(d)
+	}
+	if exact.Quantile(0.5) != 2 || exact.Quantile(0.75) != 3 || exact.Max() != 1500 {
+		t.Errorf("Expected exact small values, got p50 %d, p75 %d", exact.Quantile(0.5), exact.Quantile(0.75))
+	}
+
+	merged := NewHistogram(2)
+	merged.Record(time.Second)
+	merged.Merge(h)
+	if merged.Count() != 100001 || merged.Max() != time.Second || merged.Min() != time.Microsecond {
+		t.Errorf("Unexpected merged histogram: %d values in [%v, %v]", merged.Count(), merged.Min(), merged.Max())
+	}
+	if p50 := merged.Quantile(0.5); math.Abs(float64(p50-50*time.Millisecond))/float64(50*time.Millisecond) > 0.01 {
+		t.Errorf("Expected the merged p50 within 1%% of 50ms, got %v", p50)
+	}
+}
+
+func record(trace, id, service, route string, start time.Time, ms float64, failed bool) map[string]interface{} {
+	return map[string]interface{}{
+		"trace_id": trace, "span_id": id, "workload": service, "span_name": "HTTP " + route,
+		"start_time": start.Format(time.RFC3339Nano), "duration_ms": ms, "is_error": failed,
+		"attributes": map[string]interface{}{"http.route": route},
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	aggregator := NewAggregator(
+		WithGroupBy("service", "http.route"),
+		WithStep(time.Minute),
+		WithTimeRange(start, start.Add(150*time.Second)),
+		WithFilter(func(span *tracetree.Span) bool { return span.Name != "HTTP /healthz" }))
+
+	payload := []interface{}{
+		record("t1", "a", "cart", "/checkout", start, 10, false),
+		record("t2", "a", "cart", "/checkout", start.Add(10*time.Second), 20, true),
+		record("t3", "a", "cart", "/checkout", start.Add(130*time.Second), 40, false),
+		record("t4", "a", "cart", "/items", start.Add(5*time.Second), 5, false),
+		record("t5", "a", "cart", "/healthz", start, 1, false),                    // filtered out
+		record("t6", "a", "cart", "/checkout", start.Add(-time.Second), 1, false), // before the range
+		record("t6", "b", "cart", "/checkout", start.Add(150*time.Second), 1, false),
+	}
+	if err := aggregator.AddRows(export.Rows(payload)); err != nil {
+		t.Fatalf("AddRows failed: %v", err)
+	}
+	// Spans seen before are ignored.
+	if err := aggregator.AddResults(payload[:2]); err != nil {
+		t.Fatalf("AddResults failed: %v", err)
+	}
+
+	series := aggregator.Series()
+	if len(series) != 2 || series[0].Group["http.route"] != "/checkout" || series[1].Group["http.route"] != "/items" {
+		t.Fatalf("Unexpected series %+v", series)
+	}
+	checkout := series[0].Points
+	if len(checkout) != 3 || !checkout[1].Start.Equal(start.Add(time.Minute)) || checkout[1].Requests != 0 {
+		t.Fatalf("Expected three points with an empty one, got %+v", checkout)
+	}
+	first := checkout[0]
+	if first.Requests != 2 || first.Errors != 1 || first.ErrorRate() != 0.5 || first.Rate() != 2.0/60 ||
+		first.Percentiles[2].Value != 20*time.Millisecond {
+		t.Errorf("Unexpected first point %+v", first)
+	}
+	// Percentiles are the highest value of their histogram bucket.
+	if p50 := first.Percentiles[0].Value; p50 < 10*time.Millisecond || p50 > 10010*time.Microsecond {
+		t.Errorf("Expected a p50 within 0.1%% of 10ms, got %v", p50)
+	}
+	// The last bucket ends with the time range.
+	if last := checkout[2]; last.Window != 30*time.Second || last.Rate() != 1.0/30 {
+		t.Errorf("Unexpected last point %+v", last)
+	}
+
+	var table bytes.Buffer
+	if err := aggregator.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	want := `SERVICE   HTTP.ROUTE   TIME                   REQUESTS   RATE/S   ERRORS   ERROR%   P50        P95    P99    MAX
+cart      /checkout    2026-10-18T12:00:00Z   2          0.03     1        50.0%    10.002ms   20ms   20ms   20ms
+cart      /checkout    2026-10-18T12:01:00Z   0          0.00     0        0.0%     0s         0s     0s     0s
+cart      /checkout    2026-10-18T12:02:00Z   1          0.03     0        0.0%     40ms       40ms   40ms   40ms
+cart      /items       2026-10-18T12:00:00Z   1          0.02     0        0.0%     5ms        5ms    5ms    5ms
+cart      /items       2026-10-18T12:01:00Z   0          0.00     0        0.0%     0s         0s     0s     0s
+cart      /items       2026-10-18T12:02:00Z   0          0.00     0        0.0%     0s         0s     0s     0s
+`
+	if table.String() != want {
+		t.Errorf("Expected table\n%s\ngot\n%s", want, table.String())
+	}
+
+	data, err := json.Marshal(series[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `{"errorRate":0.5,"errors":1,"maxMs":20,"meanMs":15,"p50Ms":10.002431,"p95Ms":20,"p99Ms":20,"rate":0.03333333333333333,"requests":2,"start":"2026-10-18T12:00:00Z","windowMs":60000}`) {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
+
+func TestAggregatorSinglePoint(t *testing.T) {
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	aggregator := NewAggregator(WithGroupBy(), WithPercentiles(90))
+	aggregator.AddSpans(
+		&tracetree.Span{TraceID: "t", SpanID: "a", Start: start, Duration: time.Millisecond},
+		&tracetree.Span{TraceID: "t", SpanID: "b", Start: start.Add(4 * time.Second), Duration: 3 * time.Millisecond, Error: true},
+	)
+
+	series := aggregator.Series()
+	if len(series) != 1 || len(series[0].Points) != 1 {
+		t.Fatalf("Expected a single point, got %+v", series)
+	}
+	point := series[0].Points[0]
+	if !point.Start.IsZero() || point.Window != 4*time.Second || point.Rate() != 0.5 ||
+		point.Percentiles[0].Value != 3*time.Millisecond {
+		t.Errorf("Unexpected point %+v", point)
+	}
+
+	var table bytes.Buffer
+	if err := aggregator.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	want := "REQUESTS   RATE/S   ERRORS   ERROR%   P90   MAX\n2          0.50     1        50.0%    3ms   3ms\n"
+	if table.String() != want {
+		t.Errorf("Expected table\n%s\ngot\n%s", want, table.String())
+	}
+}
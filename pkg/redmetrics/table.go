@@ -0,0 +1,100 @@
+package redmetrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteTable writes the series as a table with a row per point: the group
+// values, the bucket start when bucketing, the request count and rate, the
+// errors, the duration percentiles and the maximum duration.
+func (a *Aggregator) WriteTable(w io.Writer) error {
+	var headers []string
+	for _, name := range a.groupBy {
+		headers = append(headers, strings.ToUpper(name))
+	}
+	if a.step > 0 {
+		headers = append(headers, "TIME")
+	}
+	headers = append(headers, "REQUESTS", "RATE/S", "ERRORS", "ERROR%")
+	for _, p := range a.percentiles {
+		headers = append(headers, "P"+formatPercentile(p))
+	}
+	headers = append(headers, "MAX")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, series := range a.Series() {
+		for _, point := range series.Points {
+			var row []string
+			for _, name := range a.groupBy {
+				row = append(row, series.Group[name])
+			}
+			if a.step > 0 {
+				row = append(row, point.Start.Format(time.RFC3339))
+			}
+			row = append(row,
+				strconv.FormatInt(point.Requests, 10),
+				strconv.FormatFloat(point.Rate(), 'f', 2, 64),
+				strconv.FormatInt(point.Errors, 10),
+				strconv.FormatFloat(point.ErrorRate()*100, 'f', 1, 64)+"%")
+			for _, p := range point.Percentiles {
+				row = append(row, formatDuration(p.Value))
+			}
+			row = append(row, formatDuration(point.Durations.Max()))
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+	}
+	return tw.Flush()
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
+
+func formatPercentile(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// MarshalJSON encodes the point with durations in milliseconds, e.g.
+// {"start": ..., "requests": 120, "errors": 3, "rate": 2, "errorRate": 0.025,
+// "p50Ms": 12.5, "p99Ms": 80, "meanMs": 15.1, "maxMs": 95}.
+func (p Point) MarshalJSON() ([]byte, error) {
+	doc := map[string]interface{}{
+		"requests":  p.Requests,
+		"errors":    p.Errors,
+		"rate":      p.Rate(),
+		"errorRate": p.ErrorRate(),
+		"windowMs":  milliseconds(p.Window),
+		"meanMs":    milliseconds(p.Durations.Mean()),
+		"maxMs":     milliseconds(p.Durations.Max()),
+	}
+	if !p.Start.IsZero() {
+		doc["start"] = p.Start
+	}
+	for _, percentile := range p.Percentiles {
+		doc["p"+formatPercentile(percentile.Percentile)+"Ms"] = milliseconds(percentile.Value)
+	}
+	return json.Marshal(doc)
+}
+
+// MarshalJSON encodes the series as {"group": {...}, "points": [...]}.
+func (s Series) MarshalJSON() ([]byte, error) {
+	points := s.Points
+	if points == nil {
+		points = []Point{}
+	}
+	return json.Marshal(struct {
+		Group  map[string]string `json:"group"`
+		Points []Point           `json:"points"`
+	}{s.Group, points})
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,89 @@
+package rightsizing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/quantity"
+)
+
+// WriteTable writes the recommendations as a table with one row per
+// container.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tWORKLOAD\tCONTAINER\tCPU P95\tCPU MAX\tCPU REQUEST\tCPU LIMIT\tMEMORY P95\tMEMORY MAX\tMEMORY REQUEST\tMEMORY LIMIT\tFLAGS")
+	for _, recommendation := range r.Recommendations {
+		for _, c := range recommendation.Containers {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				recommendation.Namespace, recommendation.Workload, c.Name,
+				CPUQuantity(c.CPU.P95), CPUQuantity(c.CPU.Max), CPUQuantity(c.Requests.CPU), CPUQuantity(c.Limits.CPU),
+				MemoryQuantity(c.Memory.P95), MemoryQuantity(c.Memory.Max), MemoryQuantity(c.Requests.Memory), MemoryQuantity(c.Limits.Memory),
+				strings.Join(recommendation.Flags, ","))
+		}
+	}
+	return tw.Flush()
+}
+
+// MarshalJSON encodes the lookback as a PromQL duration, e.g. "7d".
+func (r *Report) MarshalJSON() ([]byte, error) {
+	type report Report
+	return json.Marshal(struct {
+		Lookback string `json:"lookback"`
+		*report
+	}{promDuration(r.Lookback), (*report)(r)})
+}
+
+// Patch returns a strategic merge patch setting the recommended resources
+// of the workload's containers, for kubectl patch:
+//
+//	kubectl patch deployment cart -n shop --patch-file cart.json
+//
+// Containers are matched by name. The patch also names the workload, with
+// its apiVersion and kind when WorkloadsList reports one.
+func (r *Recommendation) Patch() map[string]interface{} {
+	containers := make([]interface{}, 0, len(r.Containers))
+	for _, c := range r.Containers {
+		containers = append(containers, map[string]interface{}{
+			"name": c.Name,
+			"resources": map[string]interface{}{
+				"requests": map[string]string{"cpu": CPUQuantity(c.Requests.CPU), "memory": MemoryQuantity(c.Requests.Memory)},
+				"limits":   map[string]string{"cpu": CPUQuantity(c.Limits.CPU), "memory": MemoryQuantity(c.Limits.Memory)},
+			},
+		})
+	}
+	var spec interface{} = map[string]interface{}{
+		"template": map[string]interface{}{"spec": map[string]interface{}{"containers": containers}},
+	}
+	apiVersion := "apps/v1"
+	switch r.Kind {
+	case "CronJob":
+		apiVersion = "batch/v1"
+		spec = map[string]interface{}{"jobTemplate": map[string]interface{}{"spec": spec}}
+	case "Job":
+		apiVersion = "batch/v1"
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]string{"name": r.Workload, "namespace": r.Namespace},
+		"spec":     spec,
+	}
+	if r.Kind != "" {
+		patch["apiVersion"], patch["kind"] = apiVersion, r.Kind
+	}
+	return patch
+}
+
+// CPUQuantity renders millicores as a Kubernetes quantity, e.g. "250m" or
+// "2".
+func CPUQuantity(millicores float64) string {
+	return quantity.CPU(millicores)
+}
+
+// MemoryQuantity renders bytes as a Kubernetes quantity in binary units,
+// e.g. "512Mi" or "2Gi", rounding up to a mebibyte.
+func MemoryQuantity(bytes float64) string {
+	return quantity.Memory(bytes)
+}
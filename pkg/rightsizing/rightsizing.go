@@ -0,0 +1,464 @@
+// Package rightsizing recommends container resource requests and limits
+// from historical usage.
+//
+// An Advisor lists workloads through WorkloadsList and queries the p95, p99
+// and maximum CPU and memory usage of each of their containers over a
+// lookback period through MetricsQuery. Requests are recommended from the
+// p95 and limits from the maximum, both with a safety margin:
+//
+//	advisor := rightsizing.NewAdvisor(client.K8s, client.Metrics,
+//		rightsizing.WithNamespace("shop"),
+//		rightsizing.WithLookback(14*24*time.Hour))
+//	report, err := advisor.Recommend(ctx)
+//	if err != nil {
+//		...
+//	}
+//	report.WriteTable(os.Stdout)
+//	for _, recommendation := range report.Recommendations {
+//		patch, _ := json.Marshal(recommendation.Patch())
+//		...
+//	}
+//
+// Usage is that of the busiest pod of a workload at each point in time, and
+// recommendations are per pod. The limits reported by WorkloadsList are
+// totals across the pods of a workload and are divided by its PodsCount
+// before being compared with the usage. CPU is in millicores and memory in
+// bytes throughout.
+package rightsizing
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/workloads"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/types"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/utils"
+)
+
+// Defaults of an Advisor.
+const (
+	DefaultLookback   = 7 * 24 * time.Hour
+	DefaultResolution = 5 * time.Minute
+	DefaultMargin     = 0.2
+	// DefaultMinCPU and DefaultMinMemory are the smallest recommendations,
+	// for idle containers.
+	DefaultMinCPU    = 10
+	DefaultMinMemory = 32 << 20
+	// DefaultOverProvisioned flags a workload whose p99 usage is below this
+	// fraction of its limit.
+	DefaultOverProvisioned = 0.5
+	// DefaultOOMRisk flags a workload whose maximum memory usage reached
+	// this fraction of its limit.
+	DefaultOOMRisk = 0.9
+)
+
+// Default metrics of per-container usage.
+const (
+	DefaultCPUMetric    = "groundcover_container_cpu_usage_rate_m_cpu"
+	DefaultMemoryMetric = "groundcover_container_memory_working_set_bytes"
+)
+
+// Labels are the metric labels identifying a container.
+type Labels struct {
+	Cluster   string
+	Namespace string
+	Workload  string
+	Container string
+}
+
+// DefaultLabels are the labels of the default metrics.
+var DefaultLabels = Labels{
+	Cluster:   "cluster",
+	Namespace: "namespace",
+	Workload:  "workload_name",
+	Container: "container_name",
+}
+
+// Flags of a Recommendation.
+const (
+	FlagOverProvisionedCPU    = "OverProvisionedCPU"
+	FlagOverProvisionedMemory = "OverProvisionedMemory"
+	FlagOOMRisk               = "OOMRisk"
+)
+
+// Querier runs PromQL queries. *groundcover.MetricsService implements it.
+type Querier interface {
+	Query(ctx context.Context, request *models.QueryRequest) (interface{}, error)
+}
+
+// Stats summarizes the usage of a resource over the lookback period.
+type Stats struct {
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// Resources are amounts of CPU, in millicores, and memory, in bytes.
+type Resources struct {
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+}
+
+// Container is the usage of a container and its recommended resources.
+type Container struct {
+	Name     string    `json:"name"`
+	CPU      Stats     `json:"cpu"`
+	Memory   Stats     `json:"memory"`
+	Requests Resources `json:"requests"`
+	Limits   Resources `json:"limits"`
+}
+
+// Recommendation is the recommended resources of the containers of a
+// workload.
+type Recommendation struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload"`
+	Kind      string `json:"kind,omitempty"`
+	// Current are the limits of a pod: those reported by WorkloadsList,
+	// which are totals across the pods of the workload, divided by its
+	// PodsCount.
+	Current    Resources    `json:"current"`
+	Containers []*Container `json:"containers"`
+	// Flags are FlagOverProvisionedCPU, FlagOverProvisionedMemory and
+	// FlagOOMRisk.
+	Flags []string `json:"flags,omitempty"`
+}
+
+// HasFlag reports whether the recommendation has flag.
+func (r *Recommendation) HasFlag(flag string) bool {
+	return slices.Contains(r.Flags, flag)
+}
+
+// Report is the result of Recommend.
+type Report struct {
+	Lookback time.Duration `json:"lookback"`
+	Margin   float64       `json:"margin"`
+	// Recommendations are sorted by namespace and workload. Workloads
+	// without usage data are left out.
+	Recommendations []*Recommendation `json:"recommendations"`
+}
+
+// Option customizes an Advisor.
+type Option func(*Advisor)
+
+// WithNamespace restricts the recommendations to a namespace.
+func WithNamespace(namespace string) Option {
+	return func(a *Advisor) {
+		a.namespace = namespace
+	}
+}
+
+// WithWorkload restricts the recommendations to a workload.
+func WithWorkload(workload string) Option {
+	return func(a *Advisor) {
+		a.workload = workload
+	}
+}
+
+// WithSources restricts the listed workloads to sources such as a cluster.
+// The conditions are sent as the WorkloadsList Sources.
+func WithSources(sources *utils.ConditionSet) Option {
+	return func(a *Advisor) {
+		a.sources = sources.Build()
+	}
+}
+
+// WithLookback sets the period of usage considered. Defaults to
+// DefaultLookback.
+func WithLookback(lookback time.Duration) Option {
+	return func(a *Advisor) {
+		a.lookback = lookback
+	}
+}
+
+// WithResolution sets the resolution of the usage samples. Defaults to
+// DefaultResolution.
+func WithResolution(resolution time.Duration) Option {
+	return func(a *Advisor) {
+		a.resolution = resolution
+	}
+}
+
+// WithMargin sets the safety margin added to recommendations, as a
+// fraction of usage. Defaults to DefaultMargin.
+func WithMargin(margin float64) Option {
+	return func(a *Advisor) {
+		a.margin = margin
+	}
+}
+
+// WithMetrics sets the metrics of per-container CPU usage, in millicores,
+// and memory usage, in bytes, and their labels. Defaults to
+// DefaultCPUMetric, DefaultMemoryMetric and DefaultLabels.
+func WithMetrics(cpu, memory string, labels Labels) Option {
+	return func(a *Advisor) {
+		a.cpuMetric, a.memoryMetric, a.labels = cpu, memory, labels
+	}
+}
+
+// WithThresholds sets the fractions of the current limits below which p99
+// usage flags a workload as over-provisioned and at which maximum memory
+// usage flags it at risk of OOM kills. Defaults to DefaultOverProvisioned
+// and DefaultOOMRisk.
+func WithThresholds(overProvisioned, oomRisk float64) Option {
+	return func(a *Advisor) {
+		a.overProvisioned, a.oomRisk = overProvisioned, oomRisk
+	}
+}
+
+// Advisor builds rightsizing recommendations.
+type Advisor struct {
+	workloads       workloads.Lister
+	metrics         Querier
+	namespace       string
+	workload        string
+	sources         []*models.Condition
+	lookback        time.Duration
+	resolution      time.Duration
+	margin          float64
+	cpuMetric       string
+	memoryMetric    string
+	labels          Labels
+	overProvisioned float64
+	oomRisk         float64
+	now             func() time.Time
+}
+
+// NewAdvisor creates an Advisor listing workloads through k8s and querying
+// usage through metrics.
+func NewAdvisor(k8s workloads.Lister, metrics Querier, options ...Option) *Advisor {
+	a := &Advisor{
+		workloads:       k8s,
+		metrics:         metrics,
+		sources:         []*models.Condition{},
+		lookback:        DefaultLookback,
+		resolution:      DefaultResolution,
+		margin:          DefaultMargin,
+		cpuMetric:       DefaultCPUMetric,
+		memoryMetric:    DefaultMemoryMetric,
+		labels:          DefaultLabels,
+		overProvisioned: DefaultOverProvisioned,
+		oomRisk:         DefaultOOMRisk,
+		now:             time.Now,
+	}
+	for _, option := range options {
+		option(a)
+	}
+	return a
+}
+
+// key identifies a workload.
+type key struct {
+	cluster, namespace, workload string
+}
+
+// Recommend lists the workloads, queries their usage and recommends their
+// resources.
+func (a *Advisor) Recommend(ctx context.Context) (*Report, error) {
+	now := a.now()
+	usage := map[key]map[string]*Container{}
+	for _, query := range []struct {
+		metric string
+		stat   func(*Container) *Stats
+	}{
+		{a.cpuMetric, func(c *Container) *Stats { return &c.CPU }},
+		{a.memoryMetric, func(c *Container) *Stats { return &c.Memory }},
+	} {
+		for _, aggregation := range []struct {
+			function string
+			value    func(*Stats) *float64
+		}{
+			{"quantile_over_time(0.95, %s)", func(s *Stats) *float64 { return &s.P95 }},
+			{"quantile_over_time(0.99, %s)", func(s *Stats) *float64 { return &s.P99 }},
+			{"max_over_time(%s)", func(s *Stats) *float64 { return &s.Max }},
+		} {
+			samples, err := a.query(ctx, fmt.Sprintf(aggregation.function, a.subquery(query.metric)), now)
+			if err != nil {
+				return nil, err
+			}
+			for _, sample := range samples {
+				k := key{sample.labels[a.labels.Cluster], sample.labels[a.labels.Namespace], sample.labels[a.labels.Workload]}
+				name := sample.labels[a.labels.Container]
+				if usage[k] == nil {
+					usage[k] = map[string]*Container{}
+				}
+				if usage[k][name] == nil {
+					usage[k][name] = &Container{Name: name}
+				}
+				*aggregation.value(query.stat(usage[k][name])) = sample.value
+			}
+		}
+	}
+
+	report := &Report{Lookback: a.lookback, Margin: a.margin, Recommendations: []*Recommendation{}}
+	conditions := utils.NewConditionSet()
+	if a.namespace != "" {
+		conditions.Add(types.ConditionKeyNamespace, a.namespace)
+	}
+	if a.workload != "" {
+		conditions.Add(types.ConditionKeyWorkload, a.workload)
+	}
+	request := &models.WorkloadsListRequest{Conditions: conditions.Build(), Sources: a.sources}
+	for workload, err := range workloads.All(ctx, a.workloads, request) {
+		if err != nil {
+			return nil, err
+		}
+		containers := usage[key{workload.Cluster, workload.Namespace, workload.Workload}]
+		if containers == nil {
+			// Metrics may carry no cluster.
+			containers = usage[key{"", workload.Namespace, workload.Workload}]
+		}
+		if len(containers) == 0 {
+			continue
+		}
+		report.Recommendations = append(report.Recommendations, a.recommend(workload, containers))
+	}
+	slices.SortFunc(report.Recommendations, func(a, b *Recommendation) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Workload, b.Workload), cmp.Compare(a.Cluster, b.Cluster))
+	})
+	return report, nil
+}
+
+// recommend recommends the resources of the containers of a workload and
+// flags it against its current limits per pod, which are compared with the
+// usage of all the containers of a pod together.
+func (a *Advisor) recommend(workload *models.WorkloadsListItem, containers map[string]*Container) *Recommendation {
+	pods := float64(max(workload.PodsCount, 1))
+	recommendation := &Recommendation{
+		Cluster:   workload.Cluster,
+		Namespace: workload.Namespace,
+		Workload:  workload.Workload,
+		Kind:      workload.Kind,
+		Current:   Resources{CPU: workload.CPULimit / pods, Memory: workload.MemoryLimit / pods},
+	}
+	var cpu, memory Stats
+	for _, container := range containers {
+		container.Requests = Resources{
+			CPU:    roundUp(max(container.CPU.P95*(1+a.margin), DefaultMinCPU), 1),
+			Memory: roundUp(max(container.Memory.P95*(1+a.margin), DefaultMinMemory), 1<<20),
+		}
+		container.Limits = Resources{
+			CPU:    roundUp(max(container.CPU.Max*(1+a.margin), container.Requests.CPU), 1),
+			Memory: roundUp(max(container.Memory.Max*(1+a.margin), container.Requests.Memory), 1<<20),
+		}
+		cpu.P99 += container.CPU.P99
+		memory.P99 += container.Memory.P99
+		memory.Max += container.Memory.Max
+		recommendation.Containers = append(recommendation.Containers, container)
+	}
+	slices.SortFunc(recommendation.Containers, func(a, b *Container) int { return cmp.Compare(a.Name, b.Name) })
+
+	if limit := recommendation.Current.CPU; limit > 0 && cpu.P99 < limit*a.overProvisioned {
+		recommendation.Flags = append(recommendation.Flags, FlagOverProvisionedCPU)
+	}
+	if limit := recommendation.Current.Memory; limit > 0 && memory.P99 < limit*a.overProvisioned {
+		recommendation.Flags = append(recommendation.Flags, FlagOverProvisionedMemory)
+	}
+	if limit := recommendation.Current.Memory; limit > 0 && memory.Max >= limit*a.oomRisk {
+		recommendation.Flags = append(recommendation.Flags, FlagOOMRisk)
+	}
+	return recommendation
+}
+
+// subquery returns the usage of the busiest pod of each container over the
+// lookback period, as a PromQL subquery.
+func (a *Advisor) subquery(metric string) string {
+	var matchers []string
+	if a.namespace != "" {
+		matchers = append(matchers, a.labels.Namespace+"="+strconv.Quote(a.namespace))
+	}
+	if a.workload != "" {
+		matchers = append(matchers, a.labels.Workload+"="+strconv.Quote(a.workload))
+	}
+	if len(matchers) > 0 {
+		metric += "{" + strings.Join(matchers, ", ") + "}"
+	}
+	by := []string{a.labels.Cluster, a.labels.Namespace, a.labels.Workload, a.labels.Container}
+	return fmt.Sprintf("max by (%s) (%s)[%s:%s]", strings.Join(by, ", "), metric, promDuration(a.lookback), promDuration(a.resolution))
+}
+
+// sample is a sample of an instant query result.
+type sample struct {
+	labels map[string]string
+	value  float64
+}
+
+// query runs an instant query at now and returns its samples.
+func (a *Advisor) query(ctx context.Context, promql string, now time.Time) ([]sample, error) {
+	at := strfmt.DateTime(now.UTC())
+	result, err := a.metrics.Query(ctx, &models.QueryRequest{
+		Start:     at,
+		End:       at,
+		Promql:    promql,
+		QueryType: models.QueryRequestQueryTypeInstant,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying %s: %w", promql, err)
+	}
+	samples, err := parseVector(result)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing result of %s: %w", promql, err)
+	}
+	return samples, nil
+}
+
+// parseVector reads the samples of a Prometheus instant vector response.
+func parseVector(result interface{}) ([]sample, error) {
+	response, _ := result.(map[string]interface{})
+	data, _ := response["data"].(map[string]interface{})
+	if data == nil {
+		data = response
+	}
+	series, ok := data["result"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response %T", result)
+	}
+	var samples []sample
+	for _, item := range series {
+		s, _ := item.(map[string]interface{})
+		pair, _ := s["value"].([]interface{})
+		if len(pair) != 2 {
+			continue
+		}
+		text, _ := pair[1].(string)
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(value) {
+			continue
+		}
+		labels := map[string]string{}
+		metric, _ := s["metric"].(map[string]interface{})
+		for name, value := range metric {
+			labels[name], _ = value.(string)
+		}
+		samples = append(samples, sample{labels: labels, value: value})
+	}
+	return samples, nil
+}
+
+// promDuration renders a duration in PromQL syntax, e.g. 7d or 90s.
+func promDuration(d time.Duration) string {
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}} {
+		if d >= unit.size && d%unit.size == 0 {
+			return strconv.FormatInt(int64(d/unit.size), 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10) + "s"
+}
+
+// roundUp rounds value up to a multiple of step.
+func roundUp(value, step float64) float64 {
+	return math.Ceil(value/step) * step
+}
@@ -0,0 +1,211 @@
+package rightsizing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+type fakeWorkloads struct {
+	workloads []*models.WorkloadsListItem
+	requests  []models.WorkloadsListRequest
+}
+
+func (f *fakeWorkloads) Workloads(ctx context.Context, request *models.WorkloadsListRequest) (*models.WorkloadsListResponse, error) {
+	f.requests = append(f.requests, *request)
+	return &models.WorkloadsListResponse{Workloads: f.workloads, Total: uint32(len(f.workloads))}, nil
+}
+
+// fakeMetrics answers each query with the samples of its aggregation and
+// metric, keyed like "p95 cpu".
+type fakeMetrics struct {
+	samples map[string][]map[string]interface{}
+	err     error
+	queries []models.QueryRequest
+}
+
+func (f *fakeMetrics) Query(ctx context.Context, request *models.QueryRequest) (interface{}, error) {
+	f.queries = append(f.queries, *request)
+	if f.err != nil {
+		return nil, f.err
+	}
+	stat := "max"
+	if strings.HasPrefix(request.Promql, "quantile_over_time(0.95,") {
+		stat = "p95"
+	} else if strings.HasPrefix(request.Promql, "quantile_over_time(0.99,") {
+		stat = "p99"
+	}
+	resource := "memory"
+	if strings.Contains(request.Promql, DefaultCPUMetric) {
+		resource = "cpu"
+	}
+	result := []interface{}{}
+	for _, s := range f.samples[stat+" "+resource] {
+		result = append(result, s)
+	}
+	return map[string]interface{}{"status": "success", "data": map[string]interface{}{"resultType": "vector", "result": result}}, nil
+}
+
+func vector(workload, container string, value float64) map[string]interface{} {
+	return map[string]interface{}{
+		"metric": map[string]interface{}{"namespace": "shop", "workload_name": workload, "container_name": container},
+		"value":  []interface{}{1792324800.0, fmt.Sprint(value)},
+	}
+}
+
+const mi = 1 << 20
+
+func fixture() (*fakeWorkloads, *fakeMetrics) {
+	return &fakeWorkloads{workloads: []*models.WorkloadsListItem{
+		{Namespace: "shop", Workload: "cart", Kind: "Deployment", CPULimit: 2000, MemoryLimit: 512 * mi},
+		{Namespace: "shop", Workload: "report", Kind: "CronJob", CPULimit: 1000, MemoryLimit: 4096 * mi},
+		{Namespace: "shop", Workload: "idle"},
+	}}, &fakeMetrics{samples: map[string][]map[string]interface{}{
+		"p95 cpu":    {vector("cart", "app", 400), vector("cart", "proxy", 20), vector("report", "job", 100)},
+		"p99 cpu":    {vector("cart", "app", 600), vector("cart", "proxy", 30), vector("report", "job", 150)},
+		"max cpu":    {vector("cart", "app", 900), vector("cart", "proxy", 50), vector("report", "job", 200)},
+		"p95 memory": {vector("cart", "app", 380*mi), vector("cart", "proxy", 20*mi), vector("report", "job", 300*mi)},
+		"p99 memory": {vector("cart", "app", 420*mi), vector("cart", "proxy", 22*mi), vector("report", "job", 350*mi)},
+		"max memory": {vector("cart", "app", 470*mi), vector("cart", "proxy", 25*mi), vector("report", "job", 400*mi)},
+	}}
+}
+
+func TestRecommend(t *testing.T) {
+	k8s, metrics := fixture()
+	advisor := NewAdvisor(k8s, metrics, WithNamespace("shop"), WithLookback(14*24*time.Hour))
+	advisor.now = func() time.Time { return time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC) }
+	report, err := advisor.Recommend(context.Background())
+	if err != nil {
+		t.Fatalf("Recommend failed: %v", err)
+	}
+
+	if len(metrics.queries) != 6 || metrics.queries[0].QueryType != models.QueryRequestQueryTypeInstant ||
+		metrics.queries[0].Promql != `quantile_over_time(0.95, max by (cluster, namespace, workload_name, container_name) (groundcover_container_cpu_usage_rate_m_cpu{namespace="shop"})[14d:5m])` {
+		t.Errorf("Unexpected queries %+v", metrics.queries)
+	}
+	if len(k8s.requests) != 1 || len(k8s.requests[0].Conditions) != 1 {
+		t.Errorf("Unexpected workloads requests %+v", k8s.requests)
+	}
+
+	if len(report.Recommendations) != 2 || report.Recommendations[0].Workload != "cart" || report.Recommendations[1].Workload != "report" {
+		t.Fatalf("Expected cart and report, got %+v", report.Recommendations)
+	}
+	cart := report.Recommendations[0]
+	app := cart.Containers[0]
+	if app.Name != "app" || app.Requests.CPU != 480 || app.Limits.CPU != 1080 || app.Requests.Memory != 456*mi || app.Limits.Memory != 564*mi {
+		t.Errorf("Unexpected app recommendation %+v", app)
+	}
+	if proxy := cart.Containers[1]; proxy.Requests.CPU != 24 || proxy.Requests.Memory != DefaultMinMemory || proxy.Limits.Memory != DefaultMinMemory {
+		t.Errorf("Unexpected proxy recommendation %+v", proxy)
+	}
+	if !cart.HasFlag(FlagOverProvisionedCPU) || !cart.HasFlag(FlagOOMRisk) || cart.HasFlag(FlagOverProvisionedMemory) {
+		t.Errorf("Unexpected cart flags %v", cart.Flags)
+	}
+	if report := report.Recommendations[1]; !report.HasFlag(FlagOverProvisionedCPU) || !report.HasFlag(FlagOverProvisionedMemory) || report.HasFlag(FlagOOMRisk) {
+		t.Errorf("Unexpected report flags %v", report.Flags)
+	}
+
+	var table bytes.Buffer
+	if err := report.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	want := "NAMESPACE   WORKLOAD   CONTAINER   CPU P95   CPU MAX   CPU REQUEST   CPU LIMIT   MEMORY P95   MEMORY MAX   MEMORY REQUEST   MEMORY LIMIT   FLAGS\n" +
+		"shop        cart       app         400m      900m      480m          1080m       380Mi        470Mi        456Mi            564Mi          OverProvisionedCPU,OOMRisk\n" +
+		"shop        cart       proxy       20m       50m       24m           60m         20Mi         25Mi         32Mi             32Mi           OverProvisionedCPU,OOMRisk\n" +
+		"shop        report     job         100m      200m      120m          240m        300Mi        400Mi        360Mi            480Mi          OverProvisionedCPU,OverProvisionedMemory\n"
+	if table.String() != want {
+		t.Errorf("Unexpected table:\n%s\nwant:\n%s", table.String(), want)
+	}
+
+	patch, _ := json.Marshal(cart.Patch())
+	wantPatch := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"cart","namespace":"shop"},"spec":{"template":{"spec":{"containers":[` +
+		`{"name":"app","resources":{"limits":{"cpu":"1080m","memory":"564Mi"},"requests":{"cpu":"480m","memory":"456Mi"}}},` +
+		`{"name":"proxy","resources":{"limits":{"cpu":"60m","memory":"32Mi"},"requests":{"cpu":"24m","memory":"32Mi"}}}]}}}}`
+	if string(patch) != wantPatch {
+		t.Errorf("Unexpected patch:\n%s\nwant:\n%s", patch, wantPatch)
+	}
+	patch, _ = json.Marshal(report.Recommendations[1].Patch())
+	if !strings.HasPrefix(string(patch), `{"apiVersion":"batch/v1","kind":"CronJob","metadata":{"name":"report","namespace":"shop"},"spec":{"jobTemplate":{"spec":{"template":`) {
+		t.Errorf("Unexpected CronJob patch %s", patch)
+	}
+
+	encoded, _ := json.Marshal(report)
+	if !strings.HasPrefix(string(encoded), `{"lookback":"14d","margin":0.2,"recommendations":[{"namespace":"shop","workload":"cart"`) {
+		t.Errorf("Unexpected JSON %s", encoded)
+	}
+}
+
+func TestRecommendReplicas(t *testing.T) {
+	// The limits are totals across 3 pods, 1 CPU and 512Mi each; the usage
+	// is that of the busiest pod.
+	k8s := &fakeWorkloads{workloads: []*models.WorkloadsListItem{
+		{Namespace: "shop", Workload: "web", Kind: "Deployment", PodsCount: 3, CPULimit: 3000, MemoryLimit: 1536 * mi},
+	}}
+	metrics := &fakeMetrics{samples: map[string][]map[string]interface{}{
+		"p95 cpu":    {vector("web", "app", 500)},
+		"p99 cpu":    {vector("web", "app", 600)},
+		"max cpu":    {vector("web", "app", 800)},
+		"p95 memory": {vector("web", "app", 400*mi)},
+		"p99 memory": {vector("web", "app", 450*mi)},
+		"max memory": {vector("web", "app", 480*mi)},
+	}}
+	report, err := NewAdvisor(k8s, metrics).Recommend(context.Background())
+	if err != nil {
+		t.Fatalf("Recommend failed: %v", err)
+	}
+	if len(report.Recommendations) != 1 {
+		t.Fatalf("Expected web, got %+v", report.Recommendations)
+	}
+	web := report.Recommendations[0]
+	if web.Current != (Resources{CPU: 1000, Memory: 512 * mi}) {
+		t.Errorf("Expected the limits of a pod, got %+v", web.Current)
+	}
+	if !slices.Equal(web.Flags, []string{FlagOOMRisk}) {
+		t.Errorf("Expected only OOMRisk against the limits of a pod, got %v", web.Flags)
+	}
+	if app := web.Containers[0]; app.Requests.CPU != 600 || app.Limits.Memory != 576*mi {
+		t.Errorf("Expected recommendations per pod, got %+v", app)
+	}
+}
+
+func TestRecommendError(t *testing.T) {
+	k8s, metrics := fixture()
+	metrics.err = errors.New("unavailable")
+	_, err := NewAdvisor(k8s, metrics).Recommend(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), "error querying quantile_over_time(0.95, max by") || !strings.HasSuffix(err.Error(), "[7d:5m]): unavailable") {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	metrics.err = nil
+	metrics.samples = nil
+	report, err := NewAdvisor(k8s, metrics).Recommend(context.Background())
+	if err != nil || len(report.Recommendations) != 0 {
+		t.Errorf("Expected no recommendations without usage, got %+v, %v", report, err)
+	}
+}
+
+func TestQuantities(t *testing.T) {
+	for millicores, want := range map[float64]string{0: "0m", 250: "250m", 1000: "1", 2500: "2500m", 99.6: "100m"} {
+		if got := CPUQuantity(millicores); got != want {
+			t.Errorf("CPUQuantity(%v) = %s, want %s", millicores, got, want)
+		}
+	}
+	for bytes, want := range map[float64]string{0: "0Mi", 1: "1Mi", 512 * mi: "512Mi", 2048 * mi: "2Gi", 1500 * mi: "1500Mi"} {
+		if got := MemoryQuantity(bytes); got != want {
+			t.Errorf("MemoryQuantity(%v) = %s, want %s", bytes, got, want)
+		}
+	}
+	for d, want := range map[time.Duration]string{7 * 24 * time.Hour: "7d", 36 * time.Hour: "36h", 5 * time.Minute: "5m", 90 * time.Second: "90s"} {
+		if got := promDuration(d); got != want {
+			t.Errorf("promDuration(%v) = %s, want %s", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package servicegraph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EdgeChange is an edge present in both compared graphs.
+type EdgeChange struct {
+	Old, New *Edge
+}
+
+// ErrorRateDelta returns the change of the error rate.
+func (c EdgeChange) ErrorRateDelta() float64 {
+	return c.New.ErrorRate() - c.Old.ErrorRate()
+}
+
+// P95Delta returns the change of the p95 latency.
+func (c EdgeChange) P95Delta() time.Duration {
+	return c.New.P95 - c.Old.P95
+}
+
+// GraphDiff is the difference between two graphs.
+type GraphDiff struct {
+	AddedNodes   []*Node
+	RemovedNodes []*Node
+	AddedEdges   []*Edge
+	RemovedEdges []*Edge
+	// Changed are the edges in both graphs whose error rate or p95 latency
+	// changed.
+	Changed []EdgeChange
+}
+
+// Empty reports whether the graphs have the same dependencies with the same
+// error rates and latencies.
+func (d *GraphDiff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0 && len(d.Changed) == 0
+}
+
+// Compare returns the difference from old to new.
+func Compare(old, new *Graph) *GraphDiff {
+	diff := &GraphDiff{}
+
+	oldNodes := map[NodeKey]bool{}
+	for _, node := range old.Nodes {
+		oldNodes[node.Key] = true
+	}
+	newNodes := map[NodeKey]bool{}
+	for _, node := range new.Nodes {
+		newNodes[node.Key] = true
+		if !oldNodes[node.Key] {
+			diff.AddedNodes = append(diff.AddedNodes, node)
+		}
+	}
+	for _, node := range old.Nodes {
+		if !newNodes[node.Key] {
+			diff.RemovedNodes = append(diff.RemovedNodes, node)
+		}
+	}
+
+	oldEdges := map[edgeKey]*Edge{}
+	for _, edge := range old.Edges {
+		oldEdges[edgeKey{edge.From, edge.To}] = edge
+	}
+	newEdges := map[edgeKey]bool{}
+	for _, edge := range new.Edges {
+		key := edgeKey{edge.From, edge.To}
+		newEdges[key] = true
+		previous, ok := oldEdges[key]
+		switch {
+		case !ok:
+			diff.AddedEdges = append(diff.AddedEdges, edge)
+		case previous.ErrorRate() != edge.ErrorRate() || previous.P95 != edge.P95:
+			diff.Changed = append(diff.Changed, EdgeChange{Old: previous, New: edge})
+		}
+	}
+	for _, edge := range old.Edges {
+		if !newEdges[edgeKey{edge.From, edge.To}] {
+			diff.RemovedEdges = append(diff.RemovedEdges, edge)
+		}
+	}
+	return diff
+}
+
+// String renders the diff with a line per change: "+" for added nodes and
+// edges, "-" for removed ones and "~" for changed edges, e.g.
+// "~ shop/frontend -> shop/cart: errors 0.0% -> 8.3%, p95 12ms -> 40ms".
+func (d *GraphDiff) String() string {
+	var b strings.Builder
+	for _, node := range d.AddedNodes {
+		fmt.Fprintf(&b, "+ node %s\n", node.Key)
+	}
+	for _, node := range d.RemovedNodes {
+		fmt.Fprintf(&b, "- node %s\n", node.Key)
+	}
+	for _, edge := range d.AddedEdges {
+		fmt.Fprintf(&b, "+ %s -> %s (%d calls)\n", edge.From, edge.To, edge.Calls)
+	}
+	for _, edge := range d.RemovedEdges {
+		fmt.Fprintf(&b, "- %s -> %s\n", edge.From, edge.To)
+	}
+	for _, change := range d.Changed {
+		fmt.Fprintf(&b, "~ %s -> %s: errors %s -> %s, p95 %s -> %s\n", change.New.From, change.New.To,
+			formatRate(change.Old.ErrorRate()), formatRate(change.New.ErrorRate()),
+			change.Old.P95.Round(time.Microsecond), change.New.P95.Round(time.Microsecond))
+	}
+	return b.String()
+}
@@ -0,0 +1,160 @@
+package servicegraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DOT renders the graph in Graphviz DOT. Edges with errors are drawn red.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph services {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		label := node.Key.Service
+		if node.Key.Namespace != "" {
+			label += "\\n" + node.Key.Namespace
+		}
+		fmt.Fprintf(&b, "  %s [label=%s];\n", dotQuote(node.Key.String()), dotQuote(label))
+	}
+	for _, edge := range g.Edges {
+		attrs := "label=" + dotQuote(edgeLabel(edge))
+		if edge.Errors > 0 {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotQuote(edge.From.String()), dotQuote(edge.To.String()), attrs)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes s as a DOT string. Backslashes are kept so that label
+// escapes such as \n work.
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	ids := map[NodeKey]string{}
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, node := range g.Nodes {
+		id := "n" + strconv.Itoa(i)
+		ids[node.Key] = id
+		fmt.Fprintf(&b, "  %s[%s]\n", id, mermaidQuote(node.Key.String()))
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[edge.From], mermaidQuote(edgeLabel(edge)), ids[edge.To])
+	}
+	return b.String()
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+func edgeLabel(edge *Edge) string {
+	return fmt.Sprintf("%d calls, %s errors, p95 %s", edge.Calls, formatRate(edge.ErrorRate()), edge.P95.Round(time.Microsecond))
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate*100, 'f', 1, 64) + "%"
+}
+
+type jsonGraph struct {
+	Nodes []jsonNode `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+type jsonNode struct {
+	ID        string `json:"id"`
+	Service   string `json:"service"`
+	Namespace string `json:"namespace,omitempty"`
+	Spans     int64  `json:"spans"`
+	Errors    int64  `json:"errors"`
+}
+
+type jsonEdge struct {
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Calls     int64   `json:"calls"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+	P50Ms     float64 `json:"p50Ms"`
+	P95Ms     float64 `json:"p95Ms"`
+	P99Ms     float64 `json:"p99Ms"`
+}
+
+// MarshalJSON encodes the graph as {"nodes": [...], "edges": [...]}, with
+// nodes identified by "namespace/service" and latencies in milliseconds.
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	doc := jsonGraph{Nodes: []jsonNode{}, Edges: []jsonEdge{}}
+	for _, node := range g.Nodes {
+		doc.Nodes = append(doc.Nodes, jsonNode{
+			ID:        node.Key.String(),
+			Service:   node.Key.Service,
+			Namespace: node.Key.Namespace,
+			Spans:     node.Spans,
+			Errors:    node.Errors,
+		})
+	}
+	for _, edge := range g.Edges {
+		doc.Edges = append(doc.Edges, jsonEdge{
+			From:      edge.From.String(),
+			To:        edge.To.String(),
+			Calls:     edge.Calls,
+			Errors:    edge.Errors,
+			ErrorRate: edge.ErrorRate(),
+			P50Ms:     milliseconds(edge.P50),
+			P95Ms:     milliseconds(edge.P95),
+			P99Ms:     milliseconds(edge.P99),
+		})
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON decodes the encoding of MarshalJSON.
+func (g *Graph) UnmarshalJSON(data []byte) error {
+	var doc jsonGraph
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	keys := map[string]NodeKey{}
+	g.Nodes, g.Edges = nil, nil
+	for _, node := range doc.Nodes {
+		key := NodeKey{Namespace: node.Namespace, Service: node.Service}
+		keys[node.ID] = key
+		g.Nodes = append(g.Nodes, &Node{Key: key, Spans: node.Spans, Errors: node.Errors})
+	}
+	for _, edge := range doc.Edges {
+		from, ok := keys[edge.From]
+		if !ok {
+			return fmt.Errorf("edge from unknown node %q", edge.From)
+		}
+		to, ok := keys[edge.To]
+		if !ok {
+			return fmt.Errorf("edge to unknown node %q", edge.To)
+		}
+		g.Edges = append(g.Edges, &Edge{
+			From:   from,
+			To:     to,
+			Calls:  edge.Calls,
+			Errors: edge.Errors,
+			P50:    fromMilliseconds(edge.P50Ms),
+			P95:    fromMilliseconds(edge.P95Ms),
+			P99:    fromMilliseconds(edge.P99Ms),
+		})
+	}
+	return nil
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func fromMilliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
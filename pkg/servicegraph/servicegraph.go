@@ -0,0 +1,310 @@
+// Package servicegraph derives a directed graph of which services call which
+// from traces: an edge goes from a span's service to the service of each
+// child span in another service.
+//
+//	builder := servicegraph.NewBuilder()
+//	err := builder.Collect(ctx, gc.Traces, "", start, end, 10*time.Minute)
+//	...
+//	graph := builder.Graph()
+//	fmt.Println(graph.DOT())
+//
+// Edges carry call counts, error rates and latency percentiles of the called
+// spans. Graphs export to DOT, Mermaid and JSON, and Compare diffs two graphs,
+// e.g. from consecutive time windows.
+package servicegraph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/tracetree"
+)
+
+// NodeKey identifies a service: a workload in a namespace.
+type NodeKey struct {
+	Namespace string
+	Service   string
+}
+
+func (k NodeKey) String() string {
+	if k.Namespace == "" {
+		return k.Service
+	}
+	return k.Namespace + "/" + k.Service
+}
+
+func (k NodeKey) less(other NodeKey) bool {
+	if k.Namespace != other.Namespace {
+		return k.Namespace < other.Namespace
+	}
+	return k.Service < other.Service
+}
+
+// Node is a service of the graph.
+type Node struct {
+	Key    NodeKey
+	Spans  int64
+	Errors int64
+}
+
+// Edge is the calls from one service to another.
+type Edge struct {
+	From, To NodeKey
+	Calls    int64
+	Errors   int64
+	// P50, P95 and P99 are latency percentiles of the called spans.
+	P50, P95, P99 time.Duration
+}
+
+// ErrorRate returns the share of failed calls.
+func (e *Edge) ErrorRate() float64 {
+	if e.Calls == 0 {
+		return 0
+	}
+	return float64(e.Errors) / float64(e.Calls)
+}
+
+// Graph is a service graph. Nodes and edges are sorted by key.
+type Graph struct {
+	Nodes []*Node
+	Edges []*Edge
+}
+
+// Node returns the node with key, or nil.
+func (g *Graph) Node(key NodeKey) *Node {
+	for _, node := range g.Nodes {
+		if node.Key == key {
+			return node
+		}
+	}
+	return nil
+}
+
+// Edge returns the edge from one service to another, or nil.
+func (g *Graph) Edge(from, to NodeKey) *Edge {
+	for _, edge := range g.Edges {
+		if edge.From == from && edge.To == to {
+			return edge
+		}
+	}
+	return nil
+}
+
+// Searcher runs traces searches. It is satisfied by the client's Traces service.
+type Searcher interface {
+	Search(ctx context.Context, request *models.TracesSearchRequest) (interface{}, error)
+}
+
+// Option configures a Builder.
+type Option func(*Builder)
+
+// WithFields sets the record fields spans are read from. The default is
+// tracetree.DefaultFields.
+func WithFields(fields tracetree.Fields) Option {
+	return func(b *Builder) {
+		b.fields = fields
+	}
+}
+
+type spanKey struct {
+	traceID, spanID string
+}
+
+type edgeKey struct {
+	from, to NodeKey
+}
+
+type edgeStats struct {
+	calls, errors int64
+	durations     []time.Duration
+}
+
+// Builder accumulates spans into a Graph. Spans can arrive in any order and
+// across calls: a child whose parent has not been seen yet is linked when the
+// parent arrives. Builders are not safe for concurrent use.
+type Builder struct {
+	fields tracetree.Fields
+
+	nodes   map[NodeKey]*Node
+	edges   map[edgeKey]*edgeStats
+	seen    map[spanKey]NodeKey
+	pending map[spanKey][]*tracetree.Span
+}
+
+// NewBuilder creates an empty Builder.
+func NewBuilder(options ...Option) *Builder {
+	b := &Builder{
+		fields:  tracetree.DefaultFields,
+		nodes:   map[NodeKey]*Node{},
+		edges:   map[edgeKey]*edgeStats{},
+		seen:    map[spanKey]NodeKey{},
+		pending: map[spanKey][]*tracetree.Span{},
+	}
+	for _, option := range options {
+		option(b)
+	}
+	return b
+}
+
+// AddResults adds the spans of a traces search payload.
+func (b *Builder) AddResults(payload interface{}) error {
+	spans, err := tracetree.ParseSpans(payload, b.fields)
+	if err != nil {
+		return fmt.Errorf("error reading spans: %w", err)
+	}
+	b.AddSpans(spans...)
+	return nil
+}
+
+// AddSpans adds spans. Spans already added, by trace and span ID, are ignored.
+func (b *Builder) AddSpans(spans ...*tracetree.Span) {
+	for _, span := range spans {
+		key := spanKey{span.TraceID, span.SpanID}
+		if _, ok := b.seen[key]; ok {
+			continue
+		}
+		node := NodeKey{Namespace: span.Namespace, Service: span.Service}
+		b.seen[key] = node
+
+		n, ok := b.nodes[node]
+		if !ok {
+			n = &Node{Key: node}
+			b.nodes[node] = n
+		}
+		n.Spans++
+		if span.Error {
+			n.Errors++
+		}
+
+		if span.ParentSpanID != "" {
+			parentKey := spanKey{span.TraceID, span.ParentSpanID}
+			if parent, ok := b.seen[parentKey]; ok {
+				b.link(parent, span)
+			} else {
+				b.pending[parentKey] = append(b.pending[parentKey], span)
+			}
+		}
+		for _, child := range b.pending[key] {
+			b.link(node, child)
+		}
+		delete(b.pending, key)
+	}
+}
+
+// link records a call from the parent service to child, unless both are
+// the same service.
+func (b *Builder) link(parent NodeKey, child *tracetree.Span) {
+	to := NodeKey{Namespace: child.Namespace, Service: child.Service}
+	if parent == to {
+		return
+	}
+	key := edgeKey{parent, to}
+	stats, ok := b.edges[key]
+	if !ok {
+		stats = &edgeStats{}
+		b.edges[key] = stats
+	}
+	stats.calls++
+	if child.Error {
+		stats.errors++
+	}
+	stats.durations = append(stats.durations, child.Duration)
+}
+
+// AddTraces adds the spans of assembled traces.
+func (b *Builder) AddTraces(traces ...*tracetree.Trace) {
+	for _, trace := range traces {
+		b.AddSpans(trace.Spans...)
+	}
+}
+
+// Unresolved returns the number of spans whose parent has not been seen.
+func (b *Builder) Unresolved() int {
+	n := 0
+	for _, children := range b.pending {
+		n += len(children)
+	}
+	return n
+}
+
+// Collect searches traces matching query from start to end, in windows of
+// step (one search when step is 0), and adds their spans.
+func (b *Builder) Collect(ctx context.Context, searcher Searcher, query string, start, end time.Time, step time.Duration) error {
+	if !start.Before(end) {
+		return fmt.Errorf("start time %s is not before end time %s", start, end)
+	}
+	if step <= 0 {
+		step = end.Sub(start)
+	}
+	for from := start; from.Before(end); from = from.Add(step) {
+		to := from.Add(step)
+		if to.After(end) {
+			to = end
+		}
+		windowStart, windowEnd := strfmt.DateTime(from), strfmt.DateTime(to)
+		payload, err := searcher.Search(ctx, &models.TracesSearchRequest{
+			Start: &windowStart,
+			End:   &windowEnd,
+			Query: query,
+		})
+		if err != nil {
+			return fmt.Errorf("error searching traces from %s to %s: %w", from.Format(time.RFC3339), to.Format(time.RFC3339), err)
+		}
+		if err := b.AddResults(payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Graph returns the graph of the spans added so far.
+func (b *Builder) Graph() *Graph {
+	graph := &Graph{}
+	for _, node := range b.nodes {
+		copied := *node
+		graph.Nodes = append(graph.Nodes, &copied)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].Key.less(graph.Nodes[j].Key)
+	})
+
+	for key, stats := range b.edges {
+		durations := append([]time.Duration(nil), stats.durations...)
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		graph.Edges = append(graph.Edges, &Edge{
+			From:   key.from,
+			To:     key.to,
+			Calls:  stats.calls,
+			Errors: stats.errors,
+			P50:    percentile(durations, 0.50),
+			P95:    percentile(durations, 0.95),
+			P99:    percentile(durations, 0.99),
+		})
+	}
+	sortEdges(graph.Edges)
+	return graph
+}
+
+func sortEdges(edges []*Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From.less(edges[j].From)
+		}
+		return edges[i].To.less(edges[j].To)
+	})
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
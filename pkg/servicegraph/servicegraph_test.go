@@ -0,0 +1,172 @@
+package servicegraph
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/tracetree"
+)
+
+func span(trace, id, parent, service string, ms int, failed bool) map[string]interface{} {
+	return map[string]interface{}{
+		"trace_id": trace, "span_id": id, "parent_span_id": parent,
+		"workload": service, "namespace": "shop",
+		"start_time": "2026-10-18T12:00:00Z", "duration_ms": float64(ms), "is_error": failed,
+	}
+}
+
+// scriptedSearcher returns one payload per search and records the requests.
+type scriptedSearcher struct {
+	payloads []interface{}
+	requests []*models.TracesSearchRequest
+}
+
+func (s *scriptedSearcher) Search(ctx context.Context, request *models.TracesSearchRequest) (interface{}, error) {
+	s.requests = append(s.requests, request)
+	payload := s.payloads[0]
+	s.payloads = s.payloads[1:]
+	return payload, nil
+}
+
+func TestCollect(t *testing.T) {
+	searcher := &scriptedSearcher{payloads: []interface{}{
+		[]interface{}{
+			// The child arrives before its parent.
+			span("t1", "c1", "f1", "cart", 10, false),
+			span("t1", "f1", "", "frontend", 30, false),
+			span("t1", "f2", "f1", "frontend", 5, false), // same service: no edge
+			span("t1", "r1", "c1", "redis", 1, false),
+			span("t2", "f3", "", "frontend", 50, false),
+			span("t2", "c2", "f3", "cart", 20, true),
+			span("t2", "c2", "f3", "cart", 20, true), // duplicate
+		},
+		[]interface{}{
+			// Its parent came in the previous window.
+			span("t2", "p1", "f3", "payment", 40, false),
+			span("t3", "x1", "missing", "worker", 1, false),
+		},
+	}}
+
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	builder := NewBuilder()
+	if err := builder.Collect(context.Background(), searcher, "env:prod", start, start.Add(90*time.Minute), time.Hour); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(searcher.requests) != 2 || searcher.requests[1].Query != "env:prod" ||
+		time.Time(*searcher.requests[1].End) != start.Add(90*time.Minute) {
+		t.Errorf("Expected two windows, got %d requests", len(searcher.requests))
+	}
+	if builder.Unresolved() != 1 {
+		t.Errorf("Expected one unresolved span, got %d", builder.Unresolved())
+	}
+
+	graph := builder.Graph()
+	frontend, cart := NodeKey{"shop", "frontend"}, NodeKey{"shop", "cart"}
+	var edges []string
+	for _, edge := range graph.Edges {
+		edges = append(edges, edge.From.String()+" -> "+edge.To.String())
+	}
+	want := []string{"shop/cart -> shop/redis", "shop/frontend -> shop/cart", "shop/frontend -> shop/payment"}
+	if !reflect.DeepEqual(edges, want) {
+		t.Errorf("Expected edges %v, got %v", want, edges)
+	}
+
+	edge := graph.Edge(frontend, cart)
+	if edge.Calls != 2 || edge.Errors != 1 || edge.ErrorRate() != 0.5 || edge.P50 != 10*time.Millisecond || edge.P99 != 20*time.Millisecond {
+		t.Errorf("Unexpected edge %+v", edge)
+	}
+	if node := graph.Node(frontend); node.Spans != 3 {
+		t.Errorf("Expected 3 frontend spans, got %+v", node)
+	}
+}
+
+func testGraph(t *testing.T) *Graph {
+	t.Helper()
+	builder := NewBuilder()
+	err := builder.AddResults([]interface{}{
+		span("t1", "f1", "", "frontend", 30, false),
+		span("t1", "c1", "f1", "cart", 12, true),
+		span("t1", "r1", "c1", "redis", 1, false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return builder.Graph()
+}
+
+func TestExports(t *testing.T) {
+	graph := testGraph(t)
+
+	wantDOT := `digraph services {
+  rankdir=LR;
+  node [shape=box];
+  "shop/cart" [label="cart\nshop"];
+  "shop/frontend" [label="frontend\nshop"];
+  "shop/redis" [label="redis\nshop"];
+  "shop/cart" -> "shop/redis" [label="1 calls, 0.0% errors, p95 1ms"];
+  "shop/frontend" -> "shop/cart" [label="1 calls, 100.0% errors, p95 12ms", color=red];
+}
+`
+	if got := graph.DOT(); got != wantDOT {
+		t.Errorf("Expected DOT\n%s\ngot\n%s", wantDOT, got)
+	}
+
+	wantMermaid := `flowchart LR
+  n0["shop/cart"]
+  n1["shop/frontend"]
+  n2["shop/redis"]
+  n0 -->|"1 calls, 0.0% errors, p95 1ms"| n2
+  n1 -->|"1 calls, 100.0% errors, p95 12ms"| n0
+`
+	if got := graph.Mermaid(); got != wantMermaid {
+		t.Errorf("Expected Mermaid\n%s\ngot\n%s", wantMermaid, got)
+	}
+
+	data, err := json.Marshal(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `{"from":"shop/frontend","to":"shop/cart","calls":1,"errors":1,"errorRate":1,"p50Ms":12,"p95Ms":12,"p99Ms":12}`) {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+	decoded := &Graph{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, graph) {
+		t.Errorf("Expected the JSON to decode to the same graph, got %+v", decoded)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := testGraph(t)
+
+	builder := NewBuilder()
+	builder.AddTraces(tracetree.Build([]*tracetree.Span{
+		{TraceID: "t", SpanID: "f", Service: "frontend", Namespace: "shop", Duration: 50 * time.Millisecond},
+		{TraceID: "t", SpanID: "c", ParentSpanID: "f", Service: "cart", Namespace: "shop", Duration: 40 * time.Millisecond},
+		{TraceID: "t", SpanID: "m", ParentSpanID: "c", Service: "memcached", Namespace: "shop", Duration: time.Millisecond},
+	})...)
+	diff := Compare(old, builder.Graph())
+
+	want := `+ node shop/memcached
+- node shop/redis
++ shop/cart -> shop/memcached (1 calls)
+- shop/cart -> shop/redis
+~ shop/frontend -> shop/cart: errors 100.0% -> 0.0%, p95 12ms -> 40ms
+`
+	if got := diff.String(); got != want {
+		t.Errorf("Expected diff\n%s\ngot\n%s", want, got)
+	}
+	if diff.Changed[0].P95Delta() != 28*time.Millisecond || diff.Changed[0].ErrorRateDelta() != -1 {
+		t.Errorf("Unexpected change %+v", diff.Changed[0])
+	}
+	if !Compare(old, old).Empty() {
+		t.Error("Expected no changes between a graph and itself")
+	}
+}
@@ -0,0 +1,298 @@
+package traceformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/tracetree"
+)
+
+// Jaeger tag keys.
+const (
+	tagSpanKind          = "span.kind"
+	tagError             = "error"
+	tagStatusCode        = "otel.status_code"
+	tagStatusDescription = "otel.status_description"
+	fieldEvent           = "event"
+)
+
+// JaegerTraces is the JSON document of the Jaeger UI and query API, which
+// the UI accepts as a trace file.
+type JaegerTraces struct {
+	Data []JaegerTrace `json:"data"`
+}
+
+type JaegerTrace struct {
+	TraceID   string                   `json:"traceID"`
+	Spans     []JaegerSpan             `json:"spans"`
+	Processes map[string]JaegerProcess `json:"processes"`
+}
+
+type JaegerSpan struct {
+	TraceID       string            `json:"traceID"`
+	SpanID        string            `json:"spanID"`
+	OperationName string            `json:"operationName"`
+	References    []JaegerReference `json:"references"`
+	Flags         int               `json:"flags"`
+	StartTime     int64             `json:"startTime"` // µs since the epoch
+	Duration      int64             `json:"duration"`  // µs
+	Tags          []JaegerTag       `json:"tags"`
+	Logs          []JaegerLog       `json:"logs"`
+	ProcessID     string            `json:"processID"`
+}
+
+type JaegerReference struct {
+	RefType string `json:"refType"`
+	TraceID string `json:"traceID"`
+	SpanID  string `json:"spanID"`
+}
+
+type JaegerProcess struct {
+	ServiceName string      `json:"serviceName"`
+	Tags        []JaegerTag `json:"tags"`
+}
+
+type JaegerLog struct {
+	Timestamp int64       `json:"timestamp"` // µs since the epoch
+	Fields    []JaegerTag `json:"fields"`
+}
+
+// JaegerTag is a typed key value; Type is "string", "bool", "int64" or
+// "float64".
+type JaegerTag struct {
+	Key   string      `json:"key"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// ToJaeger converts spans to a Jaeger document, with a trace per trace ID in
+// order of appearance and a process per distinct resource of the trace.
+func ToJaeger(spans []*tracetree.Span) *JaegerTraces {
+	doc := &JaegerTraces{Data: []JaegerTrace{}}
+	traceIndex := map[string]int{}
+	processIDs := map[string]map[string]string{} // trace ID -> resource key -> process ID
+	for _, span := range spans {
+		traceID := hexID(span.TraceID, 32)
+		i, ok := traceIndex[traceID]
+		if !ok {
+			i = len(doc.Data)
+			traceIndex[traceID] = i
+			doc.Data = append(doc.Data, JaegerTrace{TraceID: traceID, Processes: map[string]JaegerProcess{}})
+			processIDs[traceID] = map[string]string{}
+		}
+		trace := &doc.Data[i]
+
+		resource, attributes := splitAttributes(span)
+		key := resourceKey(resource)
+		processID, ok := processIDs[traceID][key]
+		if !ok {
+			processID = "p" + strconv.Itoa(len(trace.Processes)+1)
+			processIDs[traceID][key] = processID
+			delete(resource, keyServiceName)
+			trace.Processes[processID] = JaegerProcess{ServiceName: span.Service, Tags: toTags(resource)}
+		}
+
+		jaegerSpan := JaegerSpan{
+			TraceID:       traceID,
+			SpanID:        hexID(span.SpanID, 16),
+			OperationName: span.Name,
+			References:    []JaegerReference{},
+			Flags:         1,
+			StartTime:     microseconds(span.Start),
+			Duration:      span.Duration.Microseconds(),
+			Tags:          toTags(attributes),
+			Logs:          []JaegerLog{},
+			ProcessID:     processID,
+		}
+		if span.ParentSpanID != "" {
+			jaegerSpan.References = append(jaegerSpan.References, JaegerReference{
+				RefType: "CHILD_OF",
+				TraceID: traceID,
+				SpanID:  hexID(span.ParentSpanID, 16),
+			})
+		}
+		if kind := kindName(kindValue(span.Kind)); kind != "" {
+			jaegerSpan.Tags = append(jaegerSpan.Tags, toTag(tagSpanKind, kind))
+		}
+		if span.Error {
+			jaegerSpan.Tags = append(jaegerSpan.Tags, toTag(tagError, true), toTag(tagStatusCode, "ERROR"))
+		}
+		if span.StatusMessage != "" {
+			jaegerSpan.Tags = append(jaegerSpan.Tags, toTag(tagStatusDescription, span.StatusMessage))
+		}
+		for _, event := range span.Events {
+			fields := append([]JaegerTag{toTag(fieldEvent, event.Name)}, toTags(event.Attributes)...)
+			jaegerSpan.Logs = append(jaegerSpan.Logs, JaegerLog{Timestamp: microseconds(event.Time), Fields: fields})
+		}
+
+		trace.Spans = append(trace.Spans, jaegerSpan)
+	}
+	return doc
+}
+
+// FromJaeger converts a Jaeger document to spans.
+func FromJaeger(doc *JaegerTraces) ([]*tracetree.Span, error) {
+	var spans []*tracetree.Span
+	for _, trace := range doc.Data {
+		for _, s := range trace.Spans {
+			process, ok := trace.Processes[s.ProcessID]
+			if !ok {
+				return nil, fmt.Errorf("span %s has unknown process %q", s.SpanID, s.ProcessID)
+			}
+			traceID := s.TraceID
+			if traceID == "" {
+				traceID = trace.TraceID
+			}
+			span := &tracetree.Span{
+				TraceID:  hexID(traceID, 32),
+				SpanID:   s.SpanID,
+				Name:     s.OperationName,
+				Service:  process.ServiceName,
+				Start:    fromMicroseconds(s.StartTime),
+				Duration: time.Duration(s.Duration) * time.Microsecond,
+			}
+			for _, ref := range s.References {
+				if ref.RefType == "CHILD_OF" || span.ParentSpanID == "" {
+					span.ParentSpanID = ref.SpanID
+				}
+			}
+
+			resource, err := fromTags(process.Tags)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding process %s: %w", s.ProcessID, err)
+			}
+			takeResource(span, resource)
+
+			for _, tag := range s.Tags {
+				value, err := fromTag(tag)
+				if err != nil {
+					return nil, fmt.Errorf("error decoding span %s: %w", s.SpanID, err)
+				}
+				switch tag.Key {
+				case tagSpanKind:
+					kind, _ := value.(string)
+					span.Kind = kindName(kindValue(kind))
+				case tagError:
+					span.Error = span.Error || value == true
+				case tagStatusCode:
+					span.Error = span.Error || value == "ERROR"
+				case tagStatusDescription:
+					span.StatusMessage, _ = value.(string)
+				default:
+					if span.Attributes == nil {
+						span.Attributes = map[string]interface{}{}
+					}
+					span.Attributes[tag.Key] = value
+				}
+			}
+
+			for _, log := range s.Logs {
+				event := tracetree.Event{Time: fromMicroseconds(log.Timestamp)}
+				for _, field := range log.Fields {
+					value, err := fromTag(field)
+					if err != nil {
+						return nil, fmt.Errorf("error decoding span %s: %w", s.SpanID, err)
+					}
+					if field.Key == fieldEvent {
+						event.Name, _ = value.(string)
+						continue
+					}
+					if event.Attributes == nil {
+						event.Attributes = map[string]interface{}{}
+					}
+					event.Attributes[field.Key] = value
+				}
+				span.Events = append(span.Events, event)
+			}
+			spans = append(spans, span)
+		}
+	}
+	return spans, nil
+}
+
+func microseconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMicro()
+}
+
+func fromMicroseconds(us int64) time.Time {
+	if us == 0 {
+		return time.Time{}
+	}
+	return time.UnixMicro(us).UTC()
+}
+
+func toTags(m map[string]interface{}) []JaegerTag {
+	tags := make([]JaegerTag, 0, len(m))
+	for _, key := range sortedKeys(m) {
+		tags = append(tags, toTag(key, m[key]))
+	}
+	return tags
+}
+
+// toTag returns a typed tag. Values other than strings, booleans and
+// numbers are encoded as JSON strings.
+func toTag(key string, value interface{}) JaegerTag {
+	switch v := normalizeNumber(value).(type) {
+	case string:
+		return JaegerTag{Key: key, Type: "string", Value: v}
+	case bool:
+		return JaegerTag{Key: key, Type: "bool", Value: v}
+	case int64:
+		return JaegerTag{Key: key, Type: "int64", Value: v}
+	case float64:
+		return JaegerTag{Key: key, Type: "float64", Value: v}
+	default:
+		data, _ := json.Marshal(v)
+		return JaegerTag{Key: key, Type: "string", Value: string(data)}
+	}
+}
+
+func fromTag(tag JaegerTag) (interface{}, error) {
+	switch tag.Type {
+	case "string", "":
+		if s, ok := tag.Value.(string); ok {
+			return s, nil
+		}
+	case "bool":
+		if b, ok := tag.Value.(bool); ok {
+			return b, nil
+		}
+	case "int64":
+		switch v := normalizeNumber(tag.Value).(type) {
+		case int64:
+			return v, nil
+		case string:
+			return strconv.ParseInt(v, 10, 64)
+		}
+	case "float64":
+		switch v := tag.Value.(type) {
+		case float64:
+			return v, nil
+		case json.Number:
+			return v.Float64()
+		}
+	case "binary":
+		return tag.Value, nil
+	}
+	return nil, fmt.Errorf("tag %s: invalid %s value %v", tag.Key, tag.Type, tag.Value)
+}
+
+func fromTags(tags []JaegerTag) (map[string]interface{}, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]interface{}, len(tags))
+	for _, tag := range tags {
+		value, err := fromTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		m[tag.Key] = value
+	}
+	return m, nil
+}
@@ -0,0 +1,303 @@
+package traceformat
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/tracetree"
+)
+
+// OTLP status codes.
+const (
+	StatusUnset = 0
+	StatusOK    = 1
+	StatusError = 2
+)
+
+// OTLPTraces is an OTLP/JSON traces document, as sent to /v1/traces.
+type OTLPTraces struct {
+	ResourceSpans []OTLPResourceSpans `json:"resourceSpans"`
+}
+
+type OTLPResourceSpans struct {
+	Resource   OTLPResource     `json:"resource"`
+	ScopeSpans []OTLPScopeSpans `json:"scopeSpans"`
+}
+
+type OTLPResource struct {
+	Attributes []OTLPKeyValue `json:"attributes,omitempty"`
+}
+
+type OTLPScopeSpans struct {
+	Scope OTLPScope  `json:"scope"`
+	Spans []OTLPSpan `json:"spans"`
+}
+
+type OTLPScope struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+type OTLPSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano Int64          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   Int64          `json:"endTimeUnixNano"`
+	Attributes        []OTLPKeyValue `json:"attributes,omitempty"`
+	Events            []OTLPEvent    `json:"events,omitempty"`
+	Status            OTLPStatus     `json:"status"`
+}
+
+type OTLPEvent struct {
+	TimeUnixNano Int64          `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []OTLPKeyValue `json:"attributes,omitempty"`
+}
+
+type OTLPStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type OTLPKeyValue struct {
+	Key   string       `json:"key"`
+	Value OTLPAnyValue `json:"value"`
+}
+
+// OTLPAnyValue holds one of its fields.
+type OTLPAnyValue struct {
+	StringValue *string        `json:"stringValue,omitempty"`
+	BoolValue   *bool          `json:"boolValue,omitempty"`
+	IntValue    *Int64         `json:"intValue,omitempty"`
+	DoubleValue *float64       `json:"doubleValue,omitempty"`
+	ArrayValue  *OTLPArray     `json:"arrayValue,omitempty"`
+	KvlistValue *OTLPKeyValues `json:"kvlistValue,omitempty"`
+	BytesValue  *string        `json:"bytesValue,omitempty"` // base64
+}
+
+type OTLPArray struct {
+	Values []OTLPAnyValue `json:"values"`
+}
+
+type OTLPKeyValues struct {
+	Values []OTLPKeyValue `json:"values"`
+}
+
+// Int64 is a 64-bit integer encoded as a decimal string, as in OTLP/JSON.
+// It decodes from strings and numbers.
+type Int64 int64
+
+func (i Int64) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatInt(int64(i), 10) + `"`), nil
+}
+
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if len(s) >= 2 && s[0] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid int64 %s", data)
+	}
+	*i = Int64(n)
+	return nil
+}
+
+// ScopeName is the instrumentation scope of converted spans.
+const ScopeName = "groundcover"
+
+// ToOTLP converts spans to an OTLP/JSON document, with a ResourceSpans per
+// distinct resource in order of appearance.
+func ToOTLP(spans []*tracetree.Span) *OTLPTraces {
+	doc := &OTLPTraces{ResourceSpans: []OTLPResourceSpans{}}
+	index := map[string]int{}
+	for _, span := range spans {
+		resource, attributes := splitAttributes(span)
+		key := resourceKey(resource)
+		i, ok := index[key]
+		if !ok {
+			i = len(doc.ResourceSpans)
+			index[key] = i
+			doc.ResourceSpans = append(doc.ResourceSpans, OTLPResourceSpans{
+				Resource:   OTLPResource{Attributes: toKeyValues(resource)},
+				ScopeSpans: []OTLPScopeSpans{{Scope: OTLPScope{Name: ScopeName}}},
+			})
+		}
+
+		otlpSpan := OTLPSpan{
+			TraceID:           hexID(span.TraceID, 32),
+			SpanID:            hexID(span.SpanID, 16),
+			ParentSpanID:      hexID(span.ParentSpanID, 16),
+			Name:              span.Name,
+			Kind:              kindValue(span.Kind),
+			StartTimeUnixNano: unixNano(span.Start),
+			EndTimeUnixNano:   unixNano(span.End()),
+			Attributes:        toKeyValues(attributes),
+			Status:            OTLPStatus{Message: span.StatusMessage},
+		}
+		if span.Error {
+			otlpSpan.Status.Code = StatusError
+		}
+		for _, event := range span.Events {
+			otlpSpan.Events = append(otlpSpan.Events, OTLPEvent{
+				TimeUnixNano: unixNano(event.Time),
+				Name:         event.Name,
+				Attributes:   toKeyValues(event.Attributes),
+			})
+		}
+
+		scope := &doc.ResourceSpans[i].ScopeSpans[0]
+		scope.Spans = append(scope.Spans, otlpSpan)
+	}
+	return doc
+}
+
+// FromOTLP converts an OTLP/JSON document to spans.
+func FromOTLP(doc *OTLPTraces) ([]*tracetree.Span, error) {
+	var spans []*tracetree.Span
+	for _, resourceSpans := range doc.ResourceSpans {
+		resource, err := fromKeyValues(resourceSpans.Resource.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding resource: %w", err)
+		}
+		for _, scope := range resourceSpans.ScopeSpans {
+			for _, s := range scope.Spans {
+				span := &tracetree.Span{
+					TraceID:       s.TraceID,
+					SpanID:        s.SpanID,
+					ParentSpanID:  s.ParentSpanID,
+					Name:          s.Name,
+					Kind:          kindName(s.Kind),
+					Start:         fromUnixNano(s.StartTimeUnixNano),
+					Duration:      time.Duration(s.EndTimeUnixNano - s.StartTimeUnixNano),
+					Error:         s.Status.Code == StatusError,
+					StatusMessage: s.Status.Message,
+				}
+				takeResource(span, resource)
+				if span.Attributes, err = fromKeyValues(s.Attributes); err != nil {
+					return nil, fmt.Errorf("error decoding span %s: %w", s.SpanID, err)
+				}
+				for _, e := range s.Events {
+					event := tracetree.Event{Name: e.Name, Time: fromUnixNano(e.TimeUnixNano)}
+					if event.Attributes, err = fromKeyValues(e.Attributes); err != nil {
+						return nil, fmt.Errorf("error decoding span %s: %w", s.SpanID, err)
+					}
+					span.Events = append(span.Events, event)
+				}
+				spans = append(spans, span)
+			}
+		}
+	}
+	return spans, nil
+}
+
+func unixNano(t time.Time) Int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return Int64(t.UnixNano())
+}
+
+func fromUnixNano(n Int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(n)).UTC()
+}
+
+func toKeyValues(m map[string]interface{}) []OTLPKeyValue {
+	if len(m) == 0 {
+		return nil
+	}
+	values := make([]OTLPKeyValue, 0, len(m))
+	for _, key := range sortedKeys(m) {
+		values = append(values, OTLPKeyValue{Key: key, Value: toAnyValue(m[key])})
+	}
+	return values
+}
+
+func toAnyValue(value interface{}) OTLPAnyValue {
+	switch v := normalizeNumber(value).(type) {
+	case string:
+		return OTLPAnyValue{StringValue: &v}
+	case bool:
+		return OTLPAnyValue{BoolValue: &v}
+	case int64:
+		i := Int64(v)
+		return OTLPAnyValue{IntValue: &i}
+	case float64:
+		return OTLPAnyValue{DoubleValue: &v}
+	case []byte:
+		s := base64.StdEncoding.EncodeToString(v)
+		return OTLPAnyValue{BytesValue: &s}
+	case []interface{}:
+		array := &OTLPArray{Values: make([]OTLPAnyValue, len(v))}
+		for i, item := range v {
+			array.Values[i] = toAnyValue(item)
+		}
+		return OTLPAnyValue{ArrayValue: array}
+	case map[string]interface{}:
+		return OTLPAnyValue{KvlistValue: &OTLPKeyValues{Values: toKeyValues(v)}}
+	case nil:
+		return OTLPAnyValue{}
+	default:
+		data, _ := json.Marshal(v)
+		s := string(data)
+		return OTLPAnyValue{StringValue: &s}
+	}
+}
+
+func fromKeyValues(values []OTLPKeyValue) (map[string]interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]interface{}, len(values))
+	for _, kv := range values {
+		value, err := fromAnyValue(kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", kv.Key, err)
+		}
+		m[kv.Key] = value
+	}
+	return m, nil
+}
+
+func fromAnyValue(v OTLPAnyValue) (interface{}, error) {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue, nil
+	case v.BoolValue != nil:
+		return *v.BoolValue, nil
+	case v.IntValue != nil:
+		return int64(*v.IntValue), nil
+	case v.DoubleValue != nil:
+		return *v.DoubleValue, nil
+	case v.BytesValue != nil:
+		return base64.StdEncoding.DecodeString(*v.BytesValue)
+	case v.ArrayValue != nil:
+		items := make([]interface{}, len(v.ArrayValue.Values))
+		for i, item := range v.ArrayValue.Values {
+			value, err := fromAnyValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = value
+		}
+		return items, nil
+	case v.KvlistValue != nil:
+		m, err := fromKeyValues(v.KvlistValue.Values)
+		if m == nil && err == nil {
+			m = map[string]interface{}{}
+		}
+		return m, err
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,198 @@
+// Package traceformat converts the spans of traces searches to the OTLP/JSON
+// and Jaeger UI JSON formats, and back, so traces can be loaded into other
+// tools such as a local Jaeger:
+//
+//	spans, err := tracetree.ParseSpans(payload, tracetree.DefaultFields)
+//	...
+//	data, err := json.Marshal(traceformat.ToJaeger(spans))
+//
+// Fields map as follows:
+//
+//	span field                 OTLP                               Jaeger
+//	TraceID, SpanID            traceId, spanId (hex)              traceID, spanID (hex)
+//	ParentSpanID               parentSpanId                       CHILD_OF reference
+//	Name                       name                               operationName
+//	Service                    resource service.name              process serviceName
+//	Namespace                  resource k8s.namespace.name        process tag k8s.namespace.name
+//	Resource                   resource attributes                process tags
+//	Kind                       kind (SPAN_KIND_*)                 tag span.kind
+//	Start, Duration            startTimeUnixNano, endTimeUnixNano startTime, duration (µs)
+//	Error                      status.code ERROR, else UNSET      tags error=true, otel.status_code=ERROR
+//	StatusMessage              status.message                     tag otel.status_description
+//	Attributes                 attributes                         tags
+//	Events                     events                             logs, the name in an "event" field
+//
+// Attributes named cluster, env, node_name, pod_name and container_name
+// become the resource attributes k8s.cluster.name, deployment.environment,
+// k8s.node.name, k8s.pod.name and k8s.container.name, and attributes with a
+// resource prefix such as "k8s." or "host." move to the resource.
+//
+// IDs that are not hex of the expected length are replaced by a hash, so
+// links between spans are kept. Converting back yields normalized spans:
+// kinds as "server", "client", "producer", "consumer" or "internal",
+// integral numbers as int64, and for Jaeger, times in microseconds and
+// nested attribute values as JSON strings.
+package traceformat
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/tracetree"
+)
+
+// Resource attribute keys.
+const (
+	keyServiceName = "service.name"
+	keyNamespace   = "k8s.namespace.name"
+)
+
+// resourceAliases maps record fields to resource attributes.
+var resourceAliases = map[string]string{
+	"cluster":        "k8s.cluster.name",
+	"env":            "deployment.environment",
+	"node_name":      "k8s.node.name",
+	"pod_name":       "k8s.pod.name",
+	"container_name": "k8s.container.name",
+}
+
+// resourcePrefixes are the prefixes of resource semantic conventions.
+var resourcePrefixes = []string{
+	"k8s.", "host.", "container.", "cloud.", "process.", "service.", "deployment.", "telemetry.", "os.",
+}
+
+// kinds are the span kinds, indexed by their OTLP value.
+var kinds = []string{"", "internal", "server", "client", "producer", "consumer"}
+
+// kindValue returns the OTLP value of a kind given as "server",
+// "SPAN_KIND_SERVER" or "2", or 0.
+func kindValue(kind string) int {
+	kind = strings.TrimPrefix(strings.ToLower(kind), "span_kind_")
+	for i, name := range kinds {
+		if name != "" && (kind == name || kind == string(rune('0'+i))) {
+			return i
+		}
+	}
+	return 0
+}
+
+func kindName(value int) string {
+	if value < 0 || value >= len(kinds) {
+		return ""
+	}
+	return kinds[value]
+}
+
+// splitAttributes returns the resource and span attributes of span.
+func splitAttributes(span *tracetree.Span) (resource, attributes map[string]interface{}) {
+	resource = map[string]interface{}{}
+	for key, value := range span.Resource {
+		resource[key] = value
+	}
+	if span.Service != "" {
+		resource[keyServiceName] = span.Service
+	}
+	if span.Namespace != "" {
+		resource[keyNamespace] = span.Namespace
+	}
+
+	attributes = map[string]interface{}{}
+	for key, value := range span.Attributes {
+		if alias, ok := resourceAliases[key]; ok {
+			resource[alias] = value
+			continue
+		}
+		if isResourceKey(key) {
+			resource[key] = value
+			continue
+		}
+		attributes[key] = value
+	}
+	return resource, attributes
+}
+
+func isResourceKey(key string) bool {
+	for _, prefix := range resourcePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// takeResource sets the service and namespace of span from resource and
+// keeps the other attributes as the span's resource.
+func takeResource(span *tracetree.Span, resource map[string]interface{}) {
+	for key, value := range resource {
+		switch key {
+		case keyServiceName:
+			span.Service, _ = value.(string)
+		case keyNamespace:
+			span.Namespace, _ = value.(string)
+		default:
+			if span.Resource == nil {
+				span.Resource = map[string]interface{}{}
+			}
+			span.Resource[key] = value
+		}
+	}
+}
+
+// hexID returns id as hex of size digits: hex IDs are lowercased and padded,
+// other IDs hashed.
+func hexID(id string, size int) string {
+	if id == "" {
+		return ""
+	}
+	lower := strings.ToLower(id)
+	if _, err := hex.DecodeString(padEven(lower)); err == nil && len(lower) <= size {
+		return strings.Repeat("0", size-len(lower)) + lower
+	}
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])[:size]
+}
+
+func padEven(s string) string {
+	if len(s)%2 == 1 {
+		return "0" + s
+	}
+	return s
+}
+
+// resourceKey identifies a resource for grouping.
+func resourceKey(resource map[string]interface{}) string {
+	data, _ := json.Marshal(resource) // map keys are sorted
+	return string(data)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeNumber returns integral float64 values as int64.
+func normalizeNumber(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		if v == float64(int64(v)) && v >= -1<<53 && v <= 1<<53 {
+			return int64(v)
+		}
+	case int:
+		return int64(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	}
+	return value
+}
@@ -0,0 +1,166 @@
+package traceformat
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/tracetree"
+)
+
+func loadSpans(t *testing.T) []*tracetree.Span {
+	t.Helper()
+	data, err := os.ReadFile("testdata/search.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("Invalid fixture: %v", err)
+	}
+	spans, err := tracetree.ParseSpans(payload, tracetree.DefaultFields)
+	if err != nil {
+		t.Fatalf("ParseSpans failed: %v", err)
+	}
+	return spans
+}
+
+// assertGolden compares the JSON encoding of value with a golden file,
+// ignoring formatting.
+func assertGolden(t *testing.T, path string, value interface{}) {
+	t.Helper()
+	got, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(want, &wantValue); err != nil {
+		t.Fatalf("Invalid golden file: %v", err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("Output differs from %s, got\n%s", path, got)
+	}
+}
+
+// roundTrip encodes value as JSON and decodes it into a new T.
+func roundTrip[T any](t *testing.T, value *T) *T {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(T)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("Failed to decode %s: %v", data, err)
+	}
+	return decoded
+}
+
+func TestOTLP(t *testing.T) {
+	spans := loadSpans(t)
+	doc := ToOTLP(spans)
+	assertGolden(t, "testdata/search.otlp.json", doc)
+
+	decoded, err := FromOTLP(roundTrip(t, doc))
+	if err != nil {
+		t.Fatalf("FromOTLP failed: %v", err)
+	}
+	if len(decoded) != len(spans) {
+		t.Fatalf("Expected %d spans, got %d", len(spans), len(decoded))
+	}
+
+	root, client := decoded[0], decoded[1]
+	if root.SpanID != "00f067aa0ba902b7" || root.ParentSpanID != "" || root.Kind != "server" ||
+		root.Service != "frontend" || root.Namespace != "shop" || !root.Error ||
+		root.StatusMessage != "upstream cart failed" || root.Duration != 120*time.Millisecond {
+		t.Errorf("Unexpected root span %+v", root)
+	}
+	if root.Resource["k8s.cluster.name"] != "prod-eu" || root.Resource["service.version"] != "1.4.2" ||
+		root.Attributes["http.status_code"] != int64(502) {
+		t.Errorf("Unexpected root attributes %v, resource %v", root.Attributes, root.Resource)
+	}
+	if client.ParentSpanID != "00f067aa0ba902b7" || client.Kind != "client" || len(client.Events) != 1 ||
+		client.Events[0].Name != "exception" ||
+		!client.Events[0].Time.Equal(time.Date(2026, 10, 18, 12, 0, 0, 105e6, time.UTC)) ||
+		!reflect.DeepEqual(client.Attributes["retry"], map[string]interface{}{"attempt": int64(2), "backoff_ms": int64(50)}) {
+		t.Errorf("Unexpected client span %+v", client)
+	}
+
+	// Converting the decoded spans again gives the same document.
+	assertGolden(t, "testdata/search.otlp.json", ToOTLP(decoded))
+}
+
+func TestJaeger(t *testing.T) {
+	spans := loadSpans(t)
+	doc := ToJaeger(spans)
+	assertGolden(t, "testdata/search.jaeger.json", doc)
+
+	decoded, err := FromJaeger(roundTrip(t, doc))
+	if err != nil {
+		t.Fatalf("FromJaeger failed: %v", err)
+	}
+	if len(decoded) != len(spans) {
+		t.Fatalf("Expected %d spans, got %d", len(spans), len(decoded))
+	}
+
+	root, client, batch := decoded[0], decoded[1], decoded[3]
+	if root.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || root.Kind != "server" || !root.Error ||
+		root.StatusMessage != "upstream cart failed" || root.Namespace != "shop" ||
+		!root.Start.Equal(spans[0].Start) || root.Resource["k8s.pod.name"] != "frontend-7d9f-abcde" {
+		t.Errorf("Unexpected root span %+v", root)
+	}
+	if client.ParentSpanID != root.SpanID || client.Duration != 100500*time.Microsecond ||
+		client.Attributes["retry"] != `{"attempt":2,"backoff_ms":50}` ||
+		len(client.Events) != 1 || client.Events[0].Attributes["exception.type"] != "TimeoutError" {
+		t.Errorf("Unexpected client span %+v", client)
+	}
+	// IDs that are not hex are hashed.
+	if len(batch.TraceID) != 32 || len(batch.SpanID) != 16 || batch.Kind != "consumer" {
+		t.Errorf("Unexpected batch span %+v", batch)
+	}
+
+	assertGolden(t, "testdata/search.jaeger.json", ToJaeger(decoded))
+}
+
+func TestIDsAndValues(t *testing.T) {
+	for _, test := range []struct {
+		id   string
+		size int
+		want string
+	}{
+		{"00F067AA0BA902B7", 16, "00f067aa0ba902b7"},
+		{"a3ce929d0e0e4736", 32, "0000000000000000a3ce929d0e0e4736"},
+		{"abc", 16, "0000000000000abc"},
+		{"", 16, ""},
+	} {
+		if got := hexID(test.id, test.size); got != test.want {
+			t.Errorf("hexID(%q, %d) = %q, want %q", test.id, test.size, got, test.want)
+		}
+	}
+	if id := hexID("job-42", 32); id != hexID("job-42", 32) || len(id) != 32 {
+		t.Errorf("Expected a stable hash, got %q", id)
+	}
+
+	for _, kind := range []string{"server", "SPAN_KIND_SERVER", "Server", "2"} {
+		if kindValue(kind) != 2 {
+			t.Errorf("Expected %q to be a server span", kind)
+		}
+	}
+
+	var i Int64
+	if err := json.Unmarshal([]byte(`1760788800000000000`), &i); err != nil || i != 1760788800000000000 {
+		t.Errorf("Expected a number to decode, got %d, %v", i, err)
+	}
+	if err := json.Unmarshal([]byte(`"x"`), &i); err == nil {
+		t.Error("Expected an error for a non-numeric string")
+	}
+}
@@ -0,0 +1,343 @@
+package tracetree
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/logrecord"
+)
+
+// Span is a span of a traces search result.
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	// Service is the workload or service that emitted the span.
+	Service   string
+	Namespace string
+	// Kind is the span kind as given, e.g. "server" or "SPAN_KIND_CLIENT".
+	Kind     string
+	Start    time.Time
+	Duration time.Duration
+	Error    bool
+	// StatusMessage describes the error, if any.
+	StatusMessage string
+	// Attributes holds the span attributes: the nested attribute maps of
+	// the record and the record fields not read into other fields.
+	Attributes map[string]interface{}
+	// Resource holds the nested resource attributes of the record.
+	Resource map[string]interface{}
+	Events   []Event
+
+	// The fields below are set when the span is assembled into a Trace.
+
+	Parent   *Span   `json:"-"`
+	Children []*Span // by start time
+	Depth    int
+	// Orphan is set on spans whose parent is not in the trace.
+	Orphan bool
+	// Skew is the shift applied to Start, and to the span's descendants, to
+	// fit the span within its parent.
+	Skew time.Duration
+	// SelfTime is the part of the span not covered by its children.
+	SelfTime time.Duration
+	// Critical is set on spans on the trace's critical path, and
+	// CriticalTime is the time they contribute to it.
+	Critical     bool
+	CriticalTime time.Duration
+	// ErrorOrigin is set on error spans none of whose children failed.
+	ErrorOrigin bool
+}
+
+// Event is a timestamped annotation of a span.
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]interface{}
+}
+
+// End returns the end time of the span.
+func (s *Span) End() time.Time {
+	return s.Start.Add(s.Duration)
+}
+
+// Fields lists, for each span field, the record fields searched in order.
+//
+// Duration fields are read in the unit their name ends with: "_ns", "_us",
+// "_ms", "_seconds" or "_s"; numbers in other fields are nanoseconds and
+// strings are Go durations such as "12ms". Spans without a duration use End.
+type Fields struct {
+	TraceID      []string
+	SpanID       []string
+	ParentSpanID []string
+	Name         []string
+	Service      []string
+	Namespace    []string
+	Kind         []string
+	Start        []string
+	Duration     []string
+	End          []string
+	// Error fields hold a bool, or a status string equal to "error" or
+	// "STATUS_CODE_ERROR", ignoring case.
+	Error         []string
+	StatusMessage []string
+	// Attributes and Resource fields hold maps, merged in order.
+	Attributes []string
+	Resource   []string
+	// Events fields hold lists of {"name", "timestamp", "attributes"}
+	// objects.
+	Events []string
+}
+
+// DefaultFields are the field names of traces search results, with common
+// alternatives.
+var DefaultFields = Fields{
+	TraceID:       []string{"trace_id", "traceId", "traceID"},
+	SpanID:        []string{"span_id", "spanId", "spanID"},
+	ParentSpanID:  []string{"parent_span_id", "parentSpanId", "parentSpanID"},
+	Name:          []string{"span_name", "name", "operation_name", "operationName", "resource_name"},
+	Service:       []string{"workload", "service_name", "serviceName", "service"},
+	Namespace:     []string{"namespace", "k8s.namespace.name"},
+	Kind:          []string{"span_kind", "kind", "spanKind"},
+	Start:         []string{"start_time", "startTime", "timestamp", "time"},
+	Duration:      []string{"duration_ns", "duration_us", "duration_ms", "duration_seconds", "duration"},
+	End:           []string{"end_time", "endTime"},
+	Error:         []string{"is_error", "error", "status", "status_code", "statusCode"},
+	StatusMessage: []string{"status_message", "statusMessage", "error_message"},
+	Attributes:    []string{"attributes", "span_attributes", "tags"},
+	Resource:      []string{"resource", "resource_attributes", "resourceAttributes"},
+	Events:        []string{"events", "logs"},
+}
+
+// ParseSpan reads a span from a search record. The record must have a trace
+// ID and a span ID.
+func ParseSpan(record map[string]interface{}, fields Fields) (*Span, error) {
+	used := map[string]bool{}
+	str := func(names []string) string {
+		for _, name := range names {
+			if s, ok := stringValue(record[name]); ok && s != "" {
+				used[name] = true
+				return s
+			}
+		}
+		return ""
+	}
+
+	span := &Span{
+		TraceID:      str(fields.TraceID),
+		SpanID:       str(fields.SpanID),
+		ParentSpanID: str(fields.ParentSpanID),
+		Name:         str(fields.Name),
+		Service:      str(fields.Service),
+		Namespace:    str(fields.Namespace),
+		Kind:         str(fields.Kind),
+	}
+	span.StatusMessage = str(fields.StatusMessage)
+	if span.TraceID == "" || span.SpanID == "" {
+		return nil, fmt.Errorf("span without trace and span IDs")
+	}
+	// An all-zero parent ID means no parent.
+	if strings.Trim(span.ParentSpanID, "0") == "" {
+		span.ParentSpanID = ""
+	}
+
+	for _, name := range fields.Start {
+		if start, ok := logrecord.ParseTimestamp(record[name]); ok {
+			span.Start = start
+			used[name] = true
+			break
+		}
+	}
+
+	found := false
+	for _, name := range fields.Duration {
+		if duration, ok := parseDuration(name, record[name]); ok {
+			span.Duration, found = duration, true
+			used[name] = true
+			break
+		}
+	}
+	if !found {
+		for _, name := range fields.End {
+			if end, ok := logrecord.ParseTimestamp(record[name]); ok && !span.Start.IsZero() {
+				span.Duration = end.Sub(span.Start)
+				used[name] = true
+				break
+			}
+		}
+	}
+	if span.Duration < 0 {
+		span.Duration = 0
+	}
+
+	for _, name := range fields.Error {
+		if failed, ok := errorValue(record[name]); ok {
+			span.Error = failed
+			used[name] = true
+			break
+		}
+	}
+
+	for _, name := range fields.Events {
+		if events, ok := record[name].([]interface{}); ok {
+			span.Events = parseEvents(events)
+			used[name] = true
+			break
+		}
+	}
+
+	span.Resource = mergeMaps(record, fields.Resource, used)
+	attributes := mergeMaps(record, fields.Attributes, used)
+	for key, value := range record {
+		if !used[key] {
+			if attributes == nil {
+				attributes = map[string]interface{}{}
+			}
+			attributes[key] = value
+		}
+	}
+	span.Attributes = attributes
+	return span, nil
+}
+
+// mergeMaps merges the maps held by fields, later fields winning.
+func mergeMaps(record map[string]interface{}, fields []string, used map[string]bool) map[string]interface{} {
+	var merged map[string]interface{}
+	for _, name := range fields {
+		m, ok := record[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		used[name] = true
+		if merged == nil {
+			merged = map[string]interface{}{}
+		}
+		for key, value := range m {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+func parseEvents(items []interface{}) []Event {
+	var events []Event
+	for _, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		event := Event{}
+		event.Name, _ = stringValue(fields["name"])
+		event.Time, _ = logrecord.Timestamp(fields, []string{"timestamp", "time", "timeUnixNano"})
+		event.Attributes, _ = fields["attributes"].(map[string]interface{})
+		events = append(events, event)
+	}
+	return events
+}
+
+// ParseSpans reads the spans of a traces search payload: a list of span
+// objects, or an object with the list under "spans" or "traces".
+func ParseSpans(payload interface{}, fields Fields) ([]*Span, error) {
+	var items []interface{}
+	switch v := payload.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		items = v
+	case []map[string]interface{}:
+		for _, record := range v {
+			items = append(items, record)
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"spans", "traces"} {
+			if list, ok := v[key].([]interface{}); ok {
+				items = list
+				break
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unexpected traces search payload of type %T", payload)
+	}
+
+	spans := make([]*Span, 0, len(items))
+	for i, item := range items {
+		record, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("span %d: unexpected value of type %T", i, item)
+		}
+		span, err := ParseSpan(record, fields)
+		if err != nil {
+			return nil, fmt.Errorf("span %d: %w", i, err)
+		}
+		spans = append(spans, span)
+	}
+	return spans, nil
+}
+
+func stringValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+func parseDuration(name string, value interface{}) (time.Duration, bool) {
+	var n float64
+	switch v := value.(type) {
+	case float64:
+		n = v
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		n = f
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d, true
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, false
+		}
+		n = f
+	default:
+		return 0, false
+	}
+
+	unit := time.Nanosecond
+	switch {
+	case strings.HasSuffix(name, "_us"):
+		unit = time.Microsecond
+	case strings.HasSuffix(name, "_ms"):
+		unit = time.Millisecond
+	case strings.HasSuffix(name, "_seconds"), strings.HasSuffix(name, "_s"):
+		unit = time.Second
+	}
+	return time.Duration(math.Round(n * float64(unit))), true
+}
+
+func errorValue(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		switch strings.ToLower(v) {
+		case "error", "status_code_error", "true":
+			return true, true
+		case "ok", "unset", "status_code_ok", "status_code_unset", "false", "":
+			return false, true
+		}
+	}
+	return false, false
+}
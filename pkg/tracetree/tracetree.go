@@ -0,0 +1,388 @@
+// Package tracetree assembles the flat span lists of traces searches into
+// trees and analyses them:
+//
+//	payload, err := gc.Traces.Search(ctx, request)
+//	...
+//	traces, err := tracetree.Assemble(payload)
+//	for _, trace := range traces {
+//		fmt.Print(trace) // indented waterfall
+//		for _, service := range trace.Services {
+//			fmt.Println(service.Service, service.SelfTime, service.CriticalTime)
+//		}
+//	}
+//
+// Spans are grouped by trace ID and linked to their parents. Spans whose
+// parent is missing become additional roots marked Orphan. A child that
+// starts before its parent, which only clock skew between hosts explains, is
+// shifted with its descendants to start with the parent.
+//
+// Each span gets its self time, the part not covered by its children, and
+// the time it contributes to the critical path: the chain of spans that
+// determined the trace's duration, found by walking back from the root's
+// end through the last-finishing child at each step. Error spans none of
+// whose children failed are marked as error origins.
+package tracetree
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Trace is an assembled trace.
+type Trace struct {
+	ID string
+	// Root is the earliest span without a parent ID, or the earliest orphan
+	// when the trace has none.
+	Root *Span
+	// Roots are Root followed by the other roots, by start time.
+	Roots []*Span
+	// Spans are all spans in depth-first order, as in the waterfall.
+	Spans    []*Span
+	Start    time.Time
+	Duration time.Duration
+	// CriticalPath are the spans on the critical path of Root, by start time.
+	CriticalPath []*Span
+	// Services break the trace time down per service, by self time
+	// descending.
+	Services []ServiceTime
+	// ErrorChains show how errors propagated: each starts at an error
+	// origin and climbs through its failing ancestors.
+	ErrorChains [][]*Span
+	// Duplicates counts spans dropped for repeating a span ID.
+	Duplicates int
+}
+
+// ServiceTime is the share of a trace spent in a service.
+type ServiceTime struct {
+	Service   string
+	Namespace string
+	Spans     int
+	Errors    int
+	// Total is the sum of span durations, SelfTime the sum of self times
+	// and CriticalTime the time on the critical path.
+	Total        time.Duration
+	SelfTime     time.Duration
+	CriticalTime time.Duration
+}
+
+// End returns the end time of the trace.
+func (t *Trace) End() time.Time {
+	return t.Start.Add(t.Duration)
+}
+
+// ErrorOrigins returns the error origins, by start time.
+func (t *Trace) ErrorOrigins() []*Span {
+	origins := make([]*Span, 0, len(t.ErrorChains))
+	for _, chain := range t.ErrorChains {
+		origins = append(origins, chain[0])
+	}
+	return origins
+}
+
+// Span returns the span with id, or nil.
+func (t *Trace) Span(id string) *Span {
+	for _, span := range t.Spans {
+		if span.SpanID == id {
+			return span
+		}
+	}
+	return nil
+}
+
+type config struct {
+	fields Fields
+}
+
+// Option configures Assemble.
+type Option func(*config)
+
+// WithFields sets the record fields spans are read from. The default is
+// DefaultFields.
+func WithFields(fields Fields) Option {
+	return func(c *config) {
+		c.fields = fields
+	}
+}
+
+// Assemble reads the spans of a traces search payload and builds their traces.
+func Assemble(payload interface{}, options ...Option) ([]*Trace, error) {
+	c := &config{fields: DefaultFields}
+	for _, option := range options {
+		option(c)
+	}
+	spans, err := ParseSpans(payload, c.fields)
+	if err != nil {
+		return nil, fmt.Errorf("error reading spans: %w", err)
+	}
+	return Build(spans), nil
+}
+
+// Build groups spans by trace ID and assembles the traces, ordered by start
+// time. It sets the tree fields of the spans, adjusting Start for clock skew.
+func Build(spans []*Span) []*Trace {
+	groups := map[string][]*Span{}
+	var ids []string
+	for _, span := range spans {
+		if _, ok := groups[span.TraceID]; !ok {
+			ids = append(ids, span.TraceID)
+		}
+		groups[span.TraceID] = append(groups[span.TraceID], span)
+	}
+
+	traces := make([]*Trace, 0, len(ids))
+	for _, id := range ids {
+		traces = append(traces, build(id, groups[id]))
+	}
+	sort.SliceStable(traces, func(i, j int) bool {
+		if !traces[i].Start.Equal(traces[j].Start) {
+			return traces[i].Start.Before(traces[j].Start)
+		}
+		return traces[i].ID < traces[j].ID
+	})
+	return traces
+}
+
+func build(id string, spans []*Span) *Trace {
+	trace := &Trace{ID: id}
+
+	byID := map[string]*Span{}
+	var unique []*Span
+	for _, span := range spans {
+		if _, ok := byID[span.SpanID]; ok {
+			trace.Duplicates++
+			continue
+		}
+		span.Parent, span.Children, span.Depth = nil, nil, 0
+		span.Orphan, span.Skew, span.SelfTime = false, 0, 0
+		span.Critical, span.CriticalTime, span.ErrorOrigin = false, 0, false
+		byID[span.SpanID] = span
+		unique = append(unique, span)
+	}
+	sortByStart(unique)
+
+	var roots, orphans []*Span
+	for _, span := range unique {
+		parent, ok := byID[span.ParentSpanID]
+		switch {
+		case span.ParentSpanID == "":
+			roots = append(roots, span)
+		case !ok || parent == span:
+			span.Orphan = true
+			orphans = append(orphans, span)
+		default:
+			span.Parent = parent
+			parent.Children = append(parent.Children, span)
+		}
+	}
+	if len(roots) == 0 && len(orphans) > 0 {
+		roots, orphans = orphans[:1], orphans[1:]
+	}
+	trace.Roots = append(roots, orphans...)
+
+	visited := map[*Span]bool{}
+	for _, root := range trace.Roots {
+		trace.visit(root, 0, visited)
+	}
+	// Spans left unvisited are in parent cycles: break them as orphans.
+	for _, span := range unique {
+		if visited[span] {
+			continue
+		}
+		parent := span.Parent
+		for i, child := range parent.Children {
+			if child == span {
+				parent.Children = append(parent.Children[:i:i], parent.Children[i+1:]...)
+				break
+			}
+		}
+		span.Parent, span.Orphan = nil, true
+		trace.Roots = append(trace.Roots, span)
+		trace.visit(span, 0, visited)
+	}
+
+	if len(trace.Roots) == 0 {
+		return trace
+	}
+	trace.Root = trace.Roots[0]
+	start, end := trace.Root.Start, trace.Root.End()
+	for _, span := range trace.Spans {
+		if span.Start.Before(start) {
+			start = span.Start
+		}
+		if span.End().After(end) {
+			end = span.End()
+		}
+	}
+	trace.Start, trace.Duration = start, end.Sub(start)
+
+	markCritical(trace.Root, trace.Root.End())
+	for _, span := range trace.Spans {
+		if span.Critical {
+			trace.CriticalPath = append(trace.CriticalPath, span)
+		}
+	}
+	sortByStart(trace.CriticalPath)
+
+	trace.Services = serviceTimes(trace.Spans)
+	trace.ErrorChains = errorChains(trace.Spans)
+	return trace
+}
+
+// visit walks the tree depth-first, adjusting skew, and computes depths and
+// self times.
+func (t *Trace) visit(span *Span, depth int, visited map[*Span]bool) {
+	visited[span] = true
+	span.Depth = depth
+	t.Spans = append(t.Spans, span)
+
+	for _, child := range span.Children {
+		// Children move with their parent's adjustment.
+		child.Start = child.Start.Add(span.Skew)
+		child.Skew = span.Skew
+		if child.Start.Before(span.Start) {
+			shift := span.Start.Sub(child.Start)
+			child.Start = span.Start
+			child.Skew += shift
+		}
+	}
+	sortByStart(span.Children)
+
+	span.SelfTime = span.Duration - covered(span, span.Children)
+	for _, child := range span.Children {
+		t.visit(child, depth+1, visited)
+	}
+}
+
+// covered returns the part of span's interval covered by children.
+func covered(span *Span, children []*Span) time.Duration {
+	var total time.Duration
+	var cursor time.Time
+	for i, child := range children {
+		start, end := child.Start, child.End()
+		if start.Before(span.Start) {
+			start = span.Start
+		}
+		if end.After(span.End()) {
+			end = span.End()
+		}
+		if i > 0 && start.Before(cursor) {
+			start = cursor
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+		if end.After(cursor) {
+			cursor = end
+		}
+	}
+	return total
+}
+
+// markCritical marks the critical path below span up to until: walking back
+// from until, the child that finished last is on the path, then the search
+// continues from that child's start.
+func markCritical(span *Span, until time.Time) {
+	span.Critical = true
+	cursor := span.End()
+	if until.Before(cursor) {
+		cursor = until
+	}
+
+	children := append([]*Span(nil), span.Children...)
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].End().After(children[j].End())
+	})
+
+	var own time.Duration
+	for _, child := range children {
+		if !child.Start.Before(cursor) || !child.End().After(span.Start) {
+			continue
+		}
+		end := child.End()
+		if end.After(cursor) {
+			end = cursor
+		}
+		own += cursor.Sub(end)
+		markCritical(child, end)
+		cursor = child.Start
+		if cursor.Before(span.Start) {
+			cursor = span.Start
+		}
+	}
+	if cursor.After(span.Start) {
+		own += cursor.Sub(span.Start)
+	}
+	span.CriticalTime = own
+}
+
+func serviceTimes(spans []*Span) []ServiceTime {
+	index := map[[2]string]int{}
+	var services []ServiceTime
+	for _, span := range spans {
+		key := [2]string{span.Namespace, span.Service}
+		i, ok := index[key]
+		if !ok {
+			i = len(services)
+			index[key] = i
+			services = append(services, ServiceTime{Service: span.Service, Namespace: span.Namespace})
+		}
+		s := &services[i]
+		s.Spans++
+		if span.Error {
+			s.Errors++
+		}
+		s.Total += span.Duration
+		s.SelfTime += span.SelfTime
+		if span.Critical {
+			s.CriticalTime += span.CriticalTime
+		}
+	}
+	sort.SliceStable(services, func(i, j int) bool {
+		if services[i].SelfTime != services[j].SelfTime {
+			return services[i].SelfTime > services[j].SelfTime
+		}
+		if services[i].Namespace != services[j].Namespace {
+			return services[i].Namespace < services[j].Namespace
+		}
+		return services[i].Service < services[j].Service
+	})
+	return services
+}
+
+func errorChains(spans []*Span) [][]*Span {
+	var chains [][]*Span
+	for _, span := range spans {
+		if !span.Error {
+			continue
+		}
+		span.ErrorOrigin = true
+		for _, child := range span.Children {
+			if child.Error {
+				span.ErrorOrigin = false
+				break
+			}
+		}
+		if !span.ErrorOrigin {
+			continue
+		}
+		chain := []*Span{span}
+		for parent := span.Parent; parent != nil && parent.Error; parent = parent.Parent {
+			chain = append(chain, parent)
+		}
+		chains = append(chains, chain)
+	}
+	sort.SliceStable(chains, func(i, j int) bool {
+		return chains[i][0].Start.Before(chains[j][0].Start)
+	})
+	return chains
+}
+
+func sortByStart(spans []*Span) {
+	sort.SliceStable(spans, func(i, j int) bool {
+		if !spans[i].Start.Equal(spans[j].Start) {
+			return spans[i].Start.Before(spans[j].Start)
+		}
+		return spans[i].SpanID < spans[j].SpanID
+	})
+}
@@ -0,0 +1,164 @@
+package tracetree
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+const checkoutSpans = `[
+	{"trace_id": "t1", "span_id": "root", "span_name": "GET /checkout", "workload": "frontend", "namespace": "shop",
+	 "start_time": "2026-10-18T12:00:00Z", "duration_ms": 100, "http.status_code": 500},
+	{"trace_id": "t1", "span_id": "cart", "parent_span_id": "root", "span_name": "GetCart", "workload": "cart", "namespace": "shop",
+	 "start_time": "2026-10-18T12:00:00.005Z", "duration_ms": 20},
+	{"trace_id": "t1", "span_id": "redis", "parent_span_id": "cart", "span_name": "GET", "workload": "redis", "namespace": "shop",
+	 "start_time": "2026-10-18T11:59:59.998Z", "duration": "3ms"},
+	{"trace_id": "t1", "span_id": "pay", "parent_span_id": "root", "span_name": "Charge", "workload": "payment", "namespace": "shop",
+	 "start_time": "2026-10-18T12:00:00.030Z", "duration_ms": 60, "status": "STATUS_CODE_ERROR"},
+	{"trace_id": "t1", "span_id": "db", "parent_span_id": "pay", "span_name": "INSERT", "workload": "payment-db", "namespace": "shop",
+	 "start_time": "2026-10-18T12:00:00.035Z", "duration_ms": 50, "is_error": true},
+	{"trace_id": "t1", "span_id": "mail", "parent_span_id": "root", "span_name": "Send", "workload": "email", "namespace": "shop",
+	 "start_time": "2026-10-18T12:00:00.092Z", "duration_ms": 5},
+	{"trace_id": "t1", "span_id": "late", "parent_span_id": "gone", "span_name": "Process", "workload": "worker", "namespace": "shop",
+	 "start_time": "2026-10-18T12:00:00.050Z", "duration_ms": 5},
+	{"trace_id": "t1", "span_id": "mail", "parent_span_id": "root", "span_name": "Send", "workload": "email", "namespace": "shop",
+	 "start_time": "2026-10-18T12:00:00.092Z", "duration_ms": 5},
+	{"trace_id": "t0", "span_id": "a", "span_name": "cron", "workload": "jobs",
+	 "start_time": 1792324799000000000, "duration_seconds": 0.5}
+]`
+
+func assemble(t *testing.T) []*Trace {
+	t.Helper()
+	var payload interface{}
+	if err := json.Unmarshal([]byte(checkoutSpans), &payload); err != nil {
+		t.Fatal(err)
+	}
+	traces, err := Assemble(payload)
+	if err != nil {
+		t.Fatalf("Assemble failed: %v", err)
+	}
+	return traces
+}
+
+func TestAssemble(t *testing.T) {
+	traces := assemble(t)
+	if len(traces) != 2 || traces[0].ID != "t0" || traces[1].ID != "t1" {
+		t.Fatalf("Expected traces t0 and t1 by start time, got %d", len(traces))
+	}
+	if d := traces[0].Duration; d != 500*time.Millisecond {
+		t.Errorf("Expected t0 to last 500ms, got %s", d)
+	}
+
+	trace := traces[1]
+	if trace.Root.SpanID != "root" || trace.Duplicates != 1 || trace.Duration != 100*time.Millisecond {
+		t.Errorf("Unexpected trace %s: root %s, %d duplicates, %s", trace.ID, trace.Root.SpanID, trace.Duplicates, trace.Duration)
+	}
+	if len(trace.Roots) != 2 || !trace.Roots[1].Orphan || trace.Roots[1].SpanID != "late" {
+		t.Errorf("Expected the orphan span as a second root, got %v", trace.Roots)
+	}
+
+	redis := trace.Span("redis")
+	if redis.Skew != 7*time.Millisecond || !redis.Start.Equal(trace.Span("cart").Start) {
+		t.Errorf("Expected redis to be shifted 7ms to the start of cart, got skew %s", redis.Skew)
+	}
+	if got := trace.Root.Attributes["http.status_code"]; got != float64(500) {
+		t.Errorf("Expected unknown fields in Attributes, got %v", trace.Root.Attributes)
+	}
+
+	selfTimes := map[string]time.Duration{"root": 15, "cart": 17, "redis": 3, "pay": 10, "db": 50, "mail": 5, "late": 5}
+	var critical []string
+	var total time.Duration
+	for _, span := range trace.Spans {
+		if want := selfTimes[span.SpanID] * time.Millisecond; span.SelfTime != want {
+			t.Errorf("Expected %s self time %s, got %s", span.SpanID, want, span.SelfTime)
+		}
+		total += span.CriticalTime
+	}
+	for _, span := range trace.CriticalPath {
+		critical = append(critical, span.SpanID)
+	}
+	if got := strings.Join(critical, " "); got != "root cart redis pay db mail" {
+		t.Errorf("Unexpected critical path %s", got)
+	}
+	if total != trace.Root.Duration {
+		t.Errorf("Expected critical times to add up to the root duration, got %s", total)
+	}
+
+	var services []string
+	for _, s := range trace.Services {
+		services = append(services, s.Service)
+	}
+	if got := strings.Join(services, " "); got != "payment-db cart frontend payment email worker redis" {
+		t.Errorf("Unexpected service order %s", got)
+	}
+	if s := trace.Services[0]; s.CriticalTime != 50*time.Millisecond || s.Errors != 1 || s.Namespace != "shop" {
+		t.Errorf("Unexpected payment-db breakdown %+v", s)
+	}
+
+	if len(trace.ErrorChains) != 1 || len(trace.ErrorChains[0]) != 2 ||
+		trace.ErrorChains[0][0].SpanID != "db" || trace.ErrorChains[0][1].SpanID != "pay" {
+		t.Errorf("Expected the error to propagate from db to pay, got %v", trace.ErrorChains)
+	}
+	if origins := trace.ErrorOrigins(); len(origins) != 1 || !origins[0].ErrorOrigin || trace.Span("pay").ErrorOrigin {
+		t.Errorf("Expected db to be the only error origin, got %v", origins)
+	}
+}
+
+func TestWaterfall(t *testing.T) {
+	trace := assemble(t)[1]
+	var b strings.Builder
+	if err := trace.WriteWaterfall(&b, 20); err != nil {
+		t.Fatal(err)
+	}
+	want := `trace t1: 7 spans, 7 services, 100ms, 2 errors
+frontend GET /checkout        +0s      100ms |####################|
+  cart GetCart               +5ms       20ms | ####               |
+    redis GET                +5ms        3ms | #                  | skew 7ms
+  payment Charge            +30ms       60ms |      ############  | !
+    payment-db INSERT       +35ms       50ms |       ##########   | !!
+  email Send                +92ms        5ms |                  # |
+worker Process              +50ms        5ms |          =         | orphan
+`
+	if got := b.String(); got != want {
+		t.Errorf("Expected waterfall\n%s\ngot\n%s", want, got)
+	}
+	if trace.String() == "" {
+		t.Error("Expected String to render the waterfall")
+	}
+}
+
+func TestBuildCycles(t *testing.T) {
+	start := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	spans := []*Span{
+		{TraceID: "t", SpanID: "a", ParentSpanID: "b", Start: start, Duration: time.Second},
+		{TraceID: "t", SpanID: "b", ParentSpanID: "a", Start: start.Add(time.Millisecond), Duration: time.Millisecond},
+	}
+	traces := Build(spans)
+	if len(traces) != 1 || len(traces[0].Spans) != 2 || traces[0].Root.SpanID != "a" || !traces[0].Root.Orphan {
+		t.Fatalf("Expected the cycle to be broken at the earliest span, got %+v", traces[0])
+	}
+	if traces[0].Span("b").Parent != traces[0].Root {
+		t.Error("Expected b to stay a child of a")
+	}
+}
+
+func TestParseSpan(t *testing.T) {
+	span, err := ParseSpan(map[string]interface{}{
+		"traceId": "t", "spanId": "s", "parentSpanId": "0000000000000000",
+		"startTime": "1792324800000", "endTime": "1792324800250", "status_code": "OK",
+	}, DefaultFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if span.ParentSpanID != "" || span.Duration != 250*time.Millisecond || span.Error || len(span.Attributes) != 0 {
+		t.Errorf("Unexpected span %+v", span)
+	}
+
+	if _, err := ParseSpan(map[string]interface{}{"span_id": "s"}, DefaultFields); err == nil {
+		t.Error("Expected an error for a span without a trace ID")
+	}
+	if _, err := ParseSpans("nope", DefaultFields); err == nil {
+		t.Error("Expected an error for an unexpected payload")
+	}
+}
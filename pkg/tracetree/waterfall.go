@@ -0,0 +1,124 @@
+package tracetree
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// DefaultWaterfallWidth is the bar width of String.
+const DefaultWaterfallWidth = 40
+
+// String renders the trace as a waterfall; see WriteWaterfall.
+func (t *Trace) String() string {
+	var b strings.Builder
+	t.WriteWaterfall(&b, DefaultWaterfallWidth)
+	return b.String()
+}
+
+// WriteWaterfall writes the trace as a text waterfall: a header line, then a
+// line per span indented by depth, with its service and name, its offset
+// from the trace start and duration, and a bar of width characters placing
+// it on the trace timeline. Bars of critical path spans are drawn with "#",
+// others with "=". Flags follow: "!" for errors, "!!" for error origins,
+// "orphan", and the applied clock skew.
+func (t *Trace) WriteWaterfall(w io.Writer, width int) error {
+	if width < 1 {
+		width = 1
+	}
+
+	errors := 0
+	for _, span := range t.Spans {
+		if span.Error {
+			errors++
+		}
+	}
+	if _, err := fmt.Fprintf(w, "trace %s: %d spans, %d services, %s, %d errors\n",
+		t.ID, len(t.Spans), len(t.Services), t.Duration, errors); err != nil {
+		return err
+	}
+
+	labels := make([]string, len(t.Spans))
+	labelWidth := 0
+	for i, span := range t.Spans {
+		labels[i] = strings.Repeat("  ", span.Depth) + spanLabel(span)
+		labelWidth = max(labelWidth, len(labels[i]))
+	}
+
+	for i, span := range t.Spans {
+		line := fmt.Sprintf("%-*s %10s %10s |%s|", labelWidth, labels[i],
+			"+"+formatDuration(span.Start.Sub(t.Start)), formatDuration(span.Duration), t.bar(span, width))
+		var flags []string
+		switch {
+		case span.ErrorOrigin:
+			flags = append(flags, "!!")
+		case span.Error:
+			flags = append(flags, "!")
+		}
+		if span.Orphan {
+			flags = append(flags, "orphan")
+		}
+		if span.Skew != 0 {
+			flags = append(flags, "skew "+formatDuration(span.Skew))
+		}
+		if len(flags) > 0 {
+			line += " " + strings.Join(flags, " ")
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func spanLabel(span *Span) string {
+	service := span.Service
+	if service == "" {
+		service = "?"
+	}
+	if span.Name == "" {
+		return service
+	}
+	return service + " " + span.Name
+}
+
+// bar draws span on a timeline of width cells; every span gets at least one cell.
+func (t *Trace) bar(span *Span, width int) string {
+	cells := []byte(strings.Repeat(" ", width))
+	if t.Duration <= 0 {
+		for i := range cells {
+			cells[i] = barChar(span)
+		}
+		return string(cells)
+	}
+
+	scale := float64(width) / float64(t.Duration)
+	from := int(float64(span.Start.Sub(t.Start)) * scale)
+	to := int(float64(span.End().Sub(t.Start))*scale + 0.5)
+	from = min(max(from, 0), width-1)
+	to = min(max(to, from+1), width)
+	for i := from; i < to; i++ {
+		cells[i] = barChar(span)
+	}
+	return string(cells)
+}
+
+func barChar(span *Span) byte {
+	if span.Critical {
+		return '#'
+	}
+	return '='
+}
+
+// formatDuration rounds d for display.
+func formatDuration(d time.Duration) string {
+	switch abs := max(d, -d); {
+	case abs >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case abs >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.String()
+	}
+}
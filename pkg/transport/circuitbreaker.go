@@ -0,0 +1,250 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold  = 5
+	defaultOpenTimeout       = 30 * time.Second
+	defaultHalfOpenMaxProbes = 1
+)
+
+// ErrCircuitOpen is matched (via errors.Is) by every error returned while the
+// circuit breaker is rejecting requests.
+var ErrCircuitOpen = errors.New("groundcover: circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+// Possible CircuitStates.
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitOpenError is returned by the CircuitBreaker when a request is
+// rejected without being sent.
+type CircuitOpenError struct {
+	Host       string
+	State      CircuitState
+	RetryAfter time.Duration // time left until the breaker lets a probe through
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("groundcover: circuit breaker is %s for %s, retry after %s", e.State, e.Host, e.RetryAfter)
+}
+
+// Is reports whether target is ErrCircuitOpen.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker. Zero values fall back to package defaults.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int
+	// SlowCallThreshold makes calls slower than this count as failures. Zero disables latency tracking.
+	SlowCallThreshold time.Duration
+	// OpenTimeout is how long the breaker stays open before letting probes through.
+	OpenTimeout time.Duration
+	// HalfOpenMaxProbes is the number of concurrent probe requests allowed while half-open.
+	HalfOpenMaxProbes int
+	// FailureStatuses are the response statuses counted as failures. Defaults to 5xx and 429.
+	FailureStatuses []int
+	// OnStateChange is called on every state transition while the breaker's lock is held,
+	// so it must not call back into the breaker.
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitBreaker is an http.RoundTripper that stops sending requests after
+// consecutive failures and fails fast with a *CircuitOpenError until
+// OpenTimeout has passed, after which a limited number of probe requests
+// decide whether to close the breaker again.
+type CircuitBreaker struct {
+	next   http.RoundTripper
+	config CircuitBreakerConfig
+	now    func() time.Time
+
+	mu             sync.Mutex
+	state          CircuitState
+	failures       int
+	openedAt       time.Time
+	inflightProbes int
+}
+
+// NewCircuitBreaker wraps next with a circuit breaker.
+func NewCircuitBreaker(next http.RoundTripper, config CircuitBreakerConfig) *CircuitBreaker {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaultFailureThreshold
+	}
+
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = defaultOpenTimeout
+	}
+
+	if config.HalfOpenMaxProbes <= 0 {
+		config.HalfOpenMaxProbes = defaultHalfOpenMaxProbes
+	}
+
+	return &CircuitBreaker{
+		next:   next,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// State returns the current state of the breaker.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refreshLocked()
+	return cb.state
+}
+
+// RoundTrip sends the request unless the breaker is open.
+func (cb *CircuitBreaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	probe, err := cb.acquire(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := cb.now()
+	resp, err := cb.next.RoundTrip(req)
+	if err != nil && req.Context().Err() != nil {
+		// The caller gave up; that says nothing about the health of the API.
+		cb.release(probe)
+		return resp, err
+	}
+	cb.record(probe, cb.isFailure(resp, err, cb.now().Sub(start)))
+
+	return resp, err
+}
+
+// acquire decides whether a request may be sent and whether it is a half-open probe.
+func (cb *CircuitBreaker) acquire(req *http.Request) (bool, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refreshLocked()
+
+	switch cb.state {
+	case CircuitOpen:
+		return false, &CircuitOpenError{
+			Host:       req.URL.Host,
+			State:      CircuitOpen,
+			RetryAfter: cb.openedAt.Add(cb.config.OpenTimeout).Sub(cb.now()),
+		}
+	case CircuitHalfOpen:
+		if cb.inflightProbes >= cb.config.HalfOpenMaxProbes {
+			return false, &CircuitOpenError{Host: req.URL.Host, State: CircuitHalfOpen}
+		}
+		cb.inflightProbes++
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// release gives back a probe slot without recording an outcome.
+func (cb *CircuitBreaker) release(probe bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if probe && cb.inflightProbes > 0 {
+		cb.inflightProbes--
+	}
+}
+
+func (cb *CircuitBreaker) record(probe bool, failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if probe && cb.inflightProbes > 0 {
+		cb.inflightProbes--
+	}
+
+	if !failed {
+		cb.failures = 0
+		if cb.state == CircuitHalfOpen {
+			cb.setStateLocked(CircuitClosed)
+		}
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || (cb.state == CircuitClosed && cb.failures >= cb.config.FailureThreshold) {
+		cb.openedAt = cb.now()
+		cb.setStateLocked(CircuitOpen)
+	}
+}
+
+// refreshLocked moves an open breaker to half-open once OpenTimeout has elapsed.
+func (cb *CircuitBreaker) refreshLocked() {
+	if cb.state == CircuitOpen && !cb.now().Before(cb.openedAt.Add(cb.config.OpenTimeout)) {
+		cb.setStateLocked(CircuitHalfOpen)
+	}
+}
+
+func (cb *CircuitBreaker) setStateLocked(state CircuitState) {
+	if cb.state == state {
+		return
+	}
+
+	from := cb.state
+	cb.state = state
+	if state != CircuitHalfOpen {
+		cb.inflightProbes = 0
+	}
+	if state == CircuitClosed {
+		cb.failures = 0
+	}
+
+	if cb.config.OnStateChange != nil {
+		cb.config.OnStateChange(from, state)
+	}
+}
+
+func (cb *CircuitBreaker) isFailure(resp *http.Response, err error, elapsed time.Duration) bool {
+	if err != nil {
+		return true
+	}
+
+	if cb.config.SlowCallThreshold > 0 && elapsed > cb.config.SlowCallThreshold {
+		return true
+	}
+
+	if len(cb.config.FailureStatuses) == 0 {
+		return resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+	}
+
+	for _, status := range cb.config.FailureStatuses {
+		if resp.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
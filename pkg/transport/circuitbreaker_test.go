@@ -0,0 +1,138 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func statusResponder(status *int) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(*status)
+		return rec.Result(), nil
+	})
+}
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	cb := NewCircuitBreaker(statusResponder(&status), CircuitBreakerConfig{FailureThreshold: 3, OpenTimeout: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "https://api.example.com/api/monitors/1", nil)
+		if _, err := cb.RoundTrip(req); err != nil {
+			t.Fatalf("attempt %d: unexpected error %v", i, err)
+		}
+	}
+
+	if cb.State() != CircuitOpen {
+		t.Fatalf("expected breaker to be open, got %s", cb.State())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com/api/monitors/1", nil)
+	_, err := cb.RoundTrip(req)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("expected *CircuitOpenError, got %T", err)
+	}
+	if openErr.Host != "api.example.com" {
+		t.Errorf("Host: expected api.example.com, got %s", openErr.Host)
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailureCount(t *testing.T) {
+	status := http.StatusBadGateway
+	cb := NewCircuitBreaker(statusResponder(&status), CircuitBreakerConfig{FailureThreshold: 2})
+
+	send := func() {
+		req := httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+		cb.RoundTrip(req)
+	}
+
+	send()
+	status = http.StatusOK
+	send()
+	status = http.StatusBadGateway
+	send()
+
+	if cb.State() != CircuitClosed {
+		t.Fatalf("expected breaker to stay closed, got %s", cb.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	status := http.StatusInternalServerError
+	now := time.Now()
+
+	var transitions []CircuitState
+	cb := NewCircuitBreaker(statusResponder(&status), CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Second,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, to)
+		},
+	})
+	cb.now = func() time.Time { return now }
+
+	cb.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil))
+	if cb.State() != CircuitOpen {
+		t.Fatalf("expected open, got %s", cb.State())
+	}
+
+	// A failed probe re-opens the breaker.
+	now = now.Add(11 * time.Second)
+	cb.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil))
+	if cb.State() != CircuitOpen {
+		t.Fatalf("expected open after failed probe, got %s", cb.State())
+	}
+
+	// A successful probe closes it.
+	now = now.Add(11 * time.Second)
+	status = http.StatusOK
+	if _, err := cb.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil)); err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	if cb.State() != CircuitClosed {
+		t.Fatalf("expected closed after successful probe, got %s", cb.State())
+	}
+
+	expected := []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitOpen, CircuitHalfOpen, CircuitClosed}
+	if len(transitions) != len(expected) {
+		t.Fatalf("expected transitions %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("transition %d: expected %s, got %s", i, expected[i], transitions[i])
+		}
+	}
+}
+
+func TestCircuitBreaker_SlowCallsCountAsFailures(t *testing.T) {
+	now := time.Now()
+	var cb *CircuitBreaker
+	cb = NewCircuitBreaker(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		now = now.Add(2 * time.Second)
+		return httptest.NewRecorder().Result(), nil
+	}), CircuitBreakerConfig{FailureThreshold: 2, SlowCallThreshold: time.Second})
+	cb.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		cb.RoundTrip(httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil))
+	}
+
+	if cb.State() != CircuitOpen {
+		t.Fatalf("expected slow calls to open the breaker, got %s", cb.State())
+	}
+}
@@ -0,0 +1,204 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"gopkg.in/yaml.v2"
+)
+
+// YAMLConsumer decodes YAML responses. Raw targets (*[]byte, *strfmt.Base64,
+// *string) receive the document unchanged; anything else, including models
+// structs, is decoded through its JSON representation so that the json tags
+// and custom unmarshalers of the generated models apply.
+func YAMLConsumer() runtime.Consumer {
+	return runtime.ConsumerFunc(func(reader io.Reader, data interface{}) error {
+		buf, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		if consumeRaw(buf, data) {
+			return nil
+		}
+
+		jsonBody, err := YAMLToJSON(buf)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(jsonBody, data)
+	})
+}
+
+// NewYamlByteConsumer returns a consumer of YAML responses.
+//
+// Deprecated: Use YAMLConsumer, which ConfigureRuntimeTransport registers.
+// Besides raw targets, it also decodes YAML into models structs.
+func NewYamlByteConsumer() runtime.Consumer {
+	return YAMLConsumer()
+}
+
+// YAMLProducer encodes request bodies as YAML. Raw bodies ([]byte, string,
+// io.Reader) are sent unchanged; anything else is encoded through its JSON
+// representation, preserving the field names and order of the generated models.
+func YAMLProducer() runtime.Producer {
+	return runtime.ProducerFunc(func(writer io.Writer, data interface{}) error {
+		if written, err := produceRaw(writer, data); written || err != nil {
+			return err
+		}
+
+		jsonBody, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		yamlBody, err := JSONToYAML(jsonBody)
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(yamlBody)
+		return err
+	})
+}
+
+// JSONConsumer decodes JSON responses like runtime.JSONConsumer, except that
+// raw targets receive the document unchanged instead of being base64-decoded.
+func JSONConsumer() runtime.Consumer {
+	jsonConsumer := runtime.JSONConsumer()
+	return runtime.ConsumerFunc(func(reader io.Reader, data interface{}) error {
+		switch data.(type) {
+		case *[]byte, *strfmt.Base64, *string:
+			buf, err := io.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+			consumeRaw(buf, data)
+			return nil
+		default:
+			return jsonConsumer.Consume(reader, data)
+		}
+	})
+}
+
+// YAMLToJSON converts a YAML document to JSON.
+func YAMLToJSON(body []byte) ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing YAML: %w", err)
+	}
+
+	return json.Marshal(jsonCompatible(doc))
+}
+
+// JSONToYAML converts a JSON document to YAML, keeping the order of object keys.
+func JSONToYAML(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	doc, err := decodeOrdered(decoder)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("error parsing JSON: unexpected data after the document at offset %d", decoder.InputOffset())
+	}
+	return yaml.Marshal(doc)
+}
+
+// decodeOrdered decodes the next JSON value, with objects as yaml.MapSlice.
+func decodeOrdered(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '[' {
+			items := []interface{}{}
+			for decoder.More() {
+				item, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			_, err := decoder.Token()
+			return items, err
+		}
+
+		object := yaml.MapSlice{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, yaml.MapItem{Key: key, Value: value})
+		}
+		_, err := decoder.Token()
+		return object, err
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i, nil
+		}
+		return t.Float64()
+	default:
+		return t, nil
+	}
+}
+
+// jsonCompatible converts the map[interface{}]interface{} values produced by
+// the YAML decoder to map[string]interface{}.
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonCompatible(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func consumeRaw(buf []byte, data interface{}) bool {
+	switch target := data.(type) {
+	case *[]byte:
+		*target = buf
+	case *strfmt.Base64:
+		*target = buf
+	case *string:
+		*target = string(buf)
+	default:
+		return false
+	}
+	return true
+}
+
+func produceRaw(writer io.Writer, data interface{}) (bool, error) {
+	switch body := data.(type) {
+	case []byte:
+		_, err := writer.Write(body)
+		return true, err
+	case string:
+		_, err := io.WriteString(writer, body)
+		return true, err
+	case io.Reader:
+		_, err := io.Copy(writer, body)
+		return true, err
+	default:
+		return false, nil
+	}
+}
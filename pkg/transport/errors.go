@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/apierrors"
+)
+
+// requestIDHeaders are checked, in order, for the ID of a failed request.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amzn-Requestid", "X-Amzn-Trace-Id"}
+
+// WithUnifiedErrors makes every API call return *apierrors.APIError for
+// non-2xx responses instead of the per-operation generated error types.
+// The generated error stays reachable through errors.As, and the APIError
+// additionally carries the request ID and raw body of the response.
+func WithUnifiedErrors() ClientOption {
+	return func(c *clientConfig) {
+		c.unifiedErrors = true
+	}
+}
+
+// capturedResponse holds the details of an error response the generated reader does not keep.
+type capturedResponse struct {
+	requestID string
+	body      []byte
+}
+
+// unifiedErrorsMiddleware captures error responses and adapts the returned error.
+func unifiedErrorsMiddleware(next submitFunc) submitFunc {
+	return func(op *runtime.ClientOperation) (interface{}, error) {
+		var captured *capturedResponse
+
+		reader := op.Reader
+		op.Reader = runtime.ClientResponseReaderFunc(func(response runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+			if response.Code() < http.StatusBadRequest {
+				return reader.ReadResponse(response, consumer)
+			}
+
+			body, err := io.ReadAll(response.Body())
+			if err != nil {
+				return nil, err
+			}
+
+			captured = &capturedResponse{body: body}
+			for _, header := range requestIDHeaders {
+				if id := response.GetHeader(header); id != "" {
+					captured.requestID = id
+					break
+				}
+			}
+			return reader.ReadResponse(&bufferedResponse{ClientResponse: response, body: body}, consumer)
+		})
+
+		result, err := next(op)
+		if err == nil {
+			return result, nil
+		}
+
+		apiErr, ok := apierrors.From(err).(*apierrors.APIError)
+		if !ok {
+			return result, err
+		}
+
+		if described, ok := OperationFromContext(op.Context); ok {
+			apiErr.Operation = described.Name
+			apiErr.Method = described.Method
+			apiErr.Path = described.Path
+		}
+		if captured != nil {
+			apiErr.WithResponse(captured.requestID, captured.body)
+		}
+		return result, apiErr
+	}
+}
+
+// bufferedResponse replays an already consumed response body to the generated reader.
+type bufferedResponse struct {
+	runtime.ClientResponse
+	body []byte
+}
+
+func (r *bufferedResponse) Body() io.ReadCloser {
+	return io.NopCloser(bytes.NewReader(r.body))
+}
@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/apierrors"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/monitors"
+)
+
+func TestWithUnifiedErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"silence not found"}`))
+	}))
+	defer server.Close()
+
+	sdkClient, err := NewSDKClient("token", "backend-1", server.URL, WithUnifiedErrors())
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	_, err = sdkClient.Monitors.GetSilence(monitors.NewGetSilenceParamsWithContext(context.Background()).WithID("s-1"), nil)
+
+	var apiErr *apierrors.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *apierrors.APIError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, apierrors.ErrNotFound) {
+		t.Error("expected errors.Is(err, ErrNotFound)")
+	}
+
+	if apiErr.Operation != "monitors.GetSilence" {
+		t.Errorf("Operation: expected monitors.GetSilence, got %s", apiErr.Operation)
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("RequestID: expected req-123, got %s", apiErr.RequestID)
+	}
+	if apiErr.Message != "silence not found" {
+		t.Errorf("Message: expected %q, got %q", "silence not found", apiErr.Message)
+	}
+	if string(apiErr.RawBody) != `{"message":"silence not found"}` {
+		t.Errorf("RawBody: got %s", apiErr.RawBody)
+	}
+
+	var notFound *monitors.GetSilenceNotFound
+	if !errors.As(err, &notFound) || notFound.Payload.Message != "silence not found" {
+		t.Error("expected the generated error to be decoded and reachable with errors.As")
+	}
+}
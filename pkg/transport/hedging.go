@@ -0,0 +1,333 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHedgePercentile   = 0.95
+	defaultHedgeInitialDelay = 500 * time.Millisecond
+	defaultHedgeMinDelay     = 50 * time.Millisecond
+	defaultHedgeMaxDelay     = 5 * time.Second
+	defaultHedgeMinSamples   = 20
+	defaultHedgeWindowSize   = 200
+)
+
+// DefaultHedgedPaths are the idempotent search endpoints hedged when
+// HedgingConfig.Paths is empty.
+var DefaultHedgedPaths = []string{
+	"/api/logs/v2/search",
+	"/api/traces/v2/search",
+	"/api/k8s/v2/events/search",
+	"/api/metrics/query",
+	"/api/search/keys",
+	"/api/search/values",
+	"/api/search/discovery",
+}
+
+// HedgingConfig configures hedged requests. Zero values fall back to package defaults.
+type HedgingConfig struct {
+	// Percentile of recently observed latencies after which the hedge request is sent.
+	Percentile float64
+	// InitialDelay is used until MinSamples latencies have been observed for a path.
+	InitialDelay time.Duration
+	// MinDelay and MaxDelay clamp the computed hedge delay.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// MinSamples is the number of observations required before the percentile is trusted.
+	MinSamples int
+	// WindowSize is the number of recent latencies kept per path.
+	WindowSize int
+	// Paths lists the request paths (matched as suffixes) that may be hedged.
+	// GET requests are always considered idempotent and hedged.
+	Paths []string
+}
+
+// hedgingTransport sends a second copy of slow idempotent requests and
+// returns whichever response arrives first.
+type hedgingTransport struct {
+	next   http.RoundTripper
+	config HedgingConfig
+
+	mu        sync.Mutex
+	latencies map[string]*latencyWindow
+}
+
+// NewHedgingTransport wraps next with request hedging.
+func NewHedgingTransport(next http.RoundTripper, config HedgingConfig) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if config.Percentile <= 0 || config.Percentile >= 1 {
+		config.Percentile = defaultHedgePercentile
+	}
+
+	if config.InitialDelay <= 0 {
+		config.InitialDelay = defaultHedgeInitialDelay
+	}
+
+	if config.MinDelay <= 0 {
+		config.MinDelay = defaultHedgeMinDelay
+	}
+
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = defaultHedgeMaxDelay
+	}
+
+	if config.MinSamples <= 0 {
+		config.MinSamples = defaultHedgeMinSamples
+	}
+
+	if config.WindowSize <= 0 {
+		config.WindowSize = defaultHedgeWindowSize
+	}
+
+	if len(config.Paths) == 0 {
+		config.Paths = DefaultHedgedPaths
+	}
+
+	return &hedgingTransport{
+		next:      next,
+		config:    config,
+		latencies: make(map[string]*latencyWindow),
+	}
+}
+
+type hedgeResult struct {
+	resp    *http.Response
+	err     error
+	attempt int
+	elapsed time.Duration
+}
+
+// RoundTrip sends the request and, if no response arrived within the hedge
+// delay, a second identical request. The first response wins and the other
+// attempt is cancelled.
+func (h *hedgingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !h.hedgeable(req) {
+		return h.next.RoundTrip(req)
+	}
+
+	getBody, err := rewindableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both attempts get their own context up front, so the loser can be
+	// cancelled as soon as the other attempt wins.
+	ctx := req.Context()
+	var attemptCtxs [2]context.Context
+	var cancels [2]context.CancelFunc
+	for i := range attemptCtxs {
+		attemptCtxs[i], cancels[i] = context.WithCancel(ctx)
+	}
+	attemptCtxs[1] = context.WithValue(attemptCtxs[1], hedgeKey, &hedgeMarker{})
+	cancelOthers := func(winner int) {
+		for i, cancel := range cancels {
+			if i != winner {
+				cancel()
+			}
+		}
+	}
+
+	results := make(chan hedgeResult, 2)
+	launch := func(i int) {
+		attempt := req.Clone(attemptCtxs[i])
+		if getBody != nil {
+			body, err := getBody()
+			if err != nil {
+				results <- hedgeResult{err: err, attempt: i}
+				return
+			}
+			attempt.Body = body
+		}
+
+		start := time.Now()
+		resp, err := h.next.RoundTrip(attempt)
+		results <- hedgeResult{resp: resp, err: err, attempt: i, elapsed: time.Since(start)}
+	}
+
+	go launch(0)
+	inflight, hedged := 1, false
+
+	timer := time.NewTimer(h.delay(req.URL.Path))
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case <-timer.C:
+			if !hedged {
+				hedged = true
+				inflight++
+				go launch(1)
+			}
+		case res := <-results:
+			inflight--
+			if res.err != nil {
+				cancels[res.attempt]()
+				lastErr = res.err
+				if inflight > 0 {
+					continue
+				}
+				cancelOthers(-1)
+				return nil, lastErr
+			}
+
+			h.observe(req.URL.Path, res.elapsed)
+			cancelOthers(res.attempt)
+			go discardResults(results, inflight)
+			res.resp.Body = &cancelOnClose{ReadCloser: res.resp.Body, cancel: cancels[res.attempt]}
+			return res.resp, nil
+		case <-ctx.Done():
+			cancelOthers(-1)
+			go discardResults(results, inflight)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (h *hedgingTransport) hedgeable(req *http.Request) bool {
+	if req.Method == http.MethodGet {
+		return true
+	}
+
+	if req.Method != http.MethodPost {
+		return false
+	}
+
+	for _, path := range h.config.Paths {
+		if strings.HasSuffix(req.URL.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the configured percentile of recent latencies for path, clamped to the configured bounds.
+func (h *hedgingTransport) delay(path string) time.Duration {
+	h.mu.Lock()
+	window, ok := h.latencies[path]
+	h.mu.Unlock()
+
+	if !ok || window.len() < h.config.MinSamples {
+		return h.config.InitialDelay
+	}
+
+	d := window.percentile(h.config.Percentile)
+	if d < h.config.MinDelay {
+		return h.config.MinDelay
+	}
+	if d > h.config.MaxDelay {
+		return h.config.MaxDelay
+	}
+	return d
+}
+
+func (h *hedgingTransport) observe(path string, elapsed time.Duration) {
+	h.mu.Lock()
+	window, ok := h.latencies[path]
+	if !ok {
+		window = &latencyWindow{samples: make([]time.Duration, 0, h.config.WindowSize)}
+		h.latencies[path] = window
+	}
+	h.mu.Unlock()
+
+	window.add(elapsed)
+}
+
+// rewindableBody makes sure the request body can be replayed for the hedge attempt.
+func rewindableBody(req *http.Request) (func() (io.ReadCloser, error), error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf)), nil
+	}, nil
+}
+
+// discardResults closes the responses of attempts that lost the race. Their
+// contexts are already cancelled.
+func discardResults(results <-chan hedgeResult, pending int) {
+	for ; pending > 0; pending-- {
+		res := <-results
+		if res.resp != nil {
+			res.resp.Body.Close()
+		}
+	}
+}
+
+// cancelOnClose releases the winning attempt's context once its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// latencyWindow is a fixed-size ring buffer of recent latencies.
+type latencyWindow struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+func (w *latencyWindow) add(d time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.samples) < cap(w.samples) {
+		w.samples = append(w.samples, d)
+		return
+	}
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % len(w.samples)
+}
+
+func (w *latencyWindow) len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.samples)
+}
+
+func (w *latencyWindow) percentile(p float64) time.Duration {
+	w.mu.Lock()
+	sorted := append([]time.Duration(nil), w.samples...)
+	w.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,118 @@
+package transport
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedgingTransport_SecondRequestWins(t *testing.T) {
+	var calls int32
+	var bodies []string
+	bodyCh := make(chan string, 2)
+	loserCancelled := make(chan bool, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		body, _ := io.ReadAll(r.Body)
+		bodyCh <- string(body)
+		if n == 1 {
+			select {
+			case <-time.After(2 * time.Second):
+				loserCancelled <- false
+			case <-r.Context().Done():
+				loserCancelled <- true
+				return
+			}
+		}
+		w.Write([]byte("attempt"))
+	}))
+	defer server.Close()
+
+	rt := NewHedgingTransport(http.DefaultTransport, HedgingConfig{InitialDelay: 20 * time.Millisecond})
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/logs/v2/search", strings.NewReader(`{"Query":"*"}`))
+	start := time.Now()
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the hedge to answer quickly, took %s", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+
+	if !<-loserCancelled {
+		t.Error("expected the slow attempt to be cancelled once the hedge won")
+	}
+
+	bodies = append(bodies, <-bodyCh, <-bodyCh)
+	for _, body := range bodies {
+		if body != `{"Query":"*"}` {
+			t.Errorf("expected both attempts to carry the request body, got %q", body)
+		}
+	}
+}
+
+func TestHedgingTransport_BodyError(t *testing.T) {
+	rt := NewHedgingTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		t.Error("expected no request without a body")
+		return nil, nil
+	}), HedgingConfig{InitialDelay: time.Hour})
+
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com/api/logs/v2/search", strings.NewReader(`{}`))
+	req.GetBody = func() (io.ReadCloser, error) { return nil, errors.New("body gone") }
+	if _, err := rt.RoundTrip(req); err == nil || err.Error() != "body gone" {
+		t.Errorf("expected the body error, got %v", err)
+	}
+}
+
+func TestHedgingTransport_SkipsNonIdempotentRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	rt := NewHedgingTransport(http.DefaultTransport, HedgingConfig{InitialDelay: 10 * time.Millisecond})
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/monitors", strings.NewReader("title: x"))
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("expected a single request for a non-hedged path, got %d", got)
+	}
+}
+
+func TestLatencyWindow_Percentile(t *testing.T) {
+	w := &latencyWindow{samples: make([]time.Duration, 0, 100)}
+	for i := 1; i <= 100; i++ {
+		w.add(time.Duration(i) * time.Millisecond)
+	}
+
+	if got := w.percentile(0.95); got != 95*time.Millisecond {
+		t.Errorf("expected p95 of 95ms, got %s", got)
+	}
+
+	// The window keeps only the most recent samples.
+	for i := 0; i < 100; i++ {
+		w.add(time.Second)
+	}
+	if got := w.percentile(0.5); got != time.Second {
+		t.Errorf("expected old samples to be evicted, got p50 %s", got)
+	}
+}
@@ -0,0 +1,162 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/redact"
+)
+
+const (
+	defaultLogSuccessLevel = slog.LevelDebug
+	defaultLogFailureLevel = slog.LevelWarn
+	logMessage             = "groundcover API request"
+)
+
+// LogOption customizes the request logging enabled by WithLogger.
+type LogOption func(*logConfig)
+
+type logConfig struct {
+	successLevel    slog.Level
+	failureLevel    slog.Level
+	logBodies       bool
+	maxBodyBytes    int
+	redactedHeaders []string
+	redactedPaths   []string
+}
+
+// WithLogLevels sets the level of successful requests and of failed requests
+// (transport errors and 4xx/5xx responses). Defaults to Debug and Warn.
+func WithLogLevels(success, failure slog.Level) LogOption {
+	return func(c *logConfig) {
+		c.successLevel = success
+		c.failureLevel = failure
+	}
+}
+
+// WithLogBodies logs request and response bodies, truncated to maxBytes.
+// A non-positive maxBytes logs bodies in full.
+func WithLogBodies(maxBytes int) LogOption {
+	return func(c *logConfig) {
+		c.logBodies = true
+		c.maxBodyBytes = maxBytes
+	}
+}
+
+// WithRedactedHeaders redacts additional headers. Authorization is always redacted.
+func WithRedactedHeaders(names ...string) LogOption {
+	return func(c *logConfig) {
+		c.redactedHeaders = append(c.redactedHeaders, names...)
+	}
+}
+
+// WithRedactedJSONPaths redacts additional JSON body paths. Paths are
+// dot-separated keys where "*" matches any key or array index, e.g.
+// "items.*.token". The API keys returned by CreateAPIKey, CreateIngestionKey
+// and ListIngestionKeys are always redacted.
+func WithRedactedJSONPaths(paths ...string) LogOption {
+	return func(c *logConfig) {
+		c.redactedPaths = append(c.redactedPaths, paths...)
+	}
+}
+
+// WithLogger logs every request attempt with its operation, method, path,
+// status, latency and attempt number. Secrets are redacted before logging.
+func WithLogger(logger *slog.Logger, options ...LogOption) ClientOption {
+	return func(c *clientConfig) {
+		config := &logConfig{
+			successLevel:    defaultLogSuccessLevel,
+			failureLevel:    defaultLogFailureLevel,
+			redactedHeaders: append([]string{}, redact.DefaultHeaders...),
+			redactedPaths:   append([]string{}, redact.DefaultJSONPaths...),
+		}
+		for _, option := range options {
+			option(config)
+		}
+		c.logger = logger
+		c.logConfig = config
+	}
+}
+
+// loggingTransport sits below the retry layer so every attempt is logged.
+type loggingTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+	config *logConfig
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	var reqBody []byte
+	if t.config.logBodies && req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	latency := time.Since(start)
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Duration("latency", latency),
+		slog.Int("attempt", AttemptFromContext(ctx)),
+	}
+	if op, ok := OperationFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("operation", op.Name))
+	}
+
+	level := t.config.successLevel
+	if err != nil {
+		level = t.config.failureLevel
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			level = t.config.failureLevel
+		}
+	}
+
+	if !t.logger.Enabled(ctx, level) {
+		return resp, err
+	}
+
+	if t.config.logBodies {
+		attrs = append(attrs,
+			slog.Any("request_headers", redact.Headers(req.Header, t.config.redactedHeaders)),
+			slog.String("request_body", t.formatBody(req.URL.Path, reqBody)),
+		)
+
+		if resp != nil && resp.Body != nil {
+			respBody, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if readErr != nil {
+				return nil, readErr
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(respBody))
+			attrs = append(attrs, slog.String("response_body", t.formatBody(req.URL.Path, respBody)))
+		}
+	}
+
+	t.logger.LogAttrs(context.WithoutCancel(ctx), level, logMessage, attrs...)
+	return resp, err
+}
+
+// formatBody redacts and truncates a body of a request to path for logging.
+func (t *loggingTransport) formatBody(path string, body []byte) string {
+	body = redact.JSON(body, redact.PathsFor(path, t.config.redactedPaths, redact.DefaultEndpointJSONPaths))
+	if t.config.maxBodyBytes > 0 && len(body) > t.config.maxBodyBytes {
+		return string(body[:t.config.maxBodyBytes]) + "...(truncated)"
+	}
+	return string(body)
+}
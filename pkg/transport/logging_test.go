@@ -0,0 +1,136 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/groundcover-com/groundcover-sdk-go/internal/redact"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/apikeys"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+func TestWithLogger_RedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"apiKey":"gc-secret-key","id":"key-1"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	sdkClient, err := NewSDKClient("super-secret-token", "backend-1", server.URL,
+		WithLogger(logger, WithLogBodies(1024), WithRedactedJSONPaths("description")),
+	)
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	body := &models.CreateAPIKeyRequest{Name: strPtr("ci"), Description: "private notes"}
+	resp, err := sdkClient.Apikeys.CreateAPIKey(apikeys.NewCreateAPIKeyParamsWithContext(context.Background()).WithBody(body), nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+	if resp.Payload.APIKey != "gc-secret-key" {
+		t.Errorf("expected the caller to receive the unredacted key, got %q", resp.Payload.APIKey)
+	}
+
+	output := buf.String()
+	for _, secret := range []string{"super-secret-token", "gc-secret-key", "private notes"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("expected %q to be redacted from the log output: %s", secret, output)
+		}
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("expected a single JSON log line: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"operation": "apikeys.CreateAPIKey",
+		"method":    "POST",
+		"path":      "/api/rbac/apikey/create",
+		"status":    float64(200),
+		"attempt":   float64(1),
+		"level":     "DEBUG",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, entry[key])
+		}
+	}
+}
+
+func TestWithLogger_RedactsIngestionKeysOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/ingestion-keys/list") {
+			w.Write([]byte(`[{"key":"gc-ingestion-key","name":"ci"}]`))
+			return
+		}
+		w.Write([]byte(`{"key":"namespace","values":["shop"]}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	config := &logConfig{logBodies: true, redactedPaths: redact.DefaultJSONPaths}
+	client := &http.Client{Transport: &loggingTransport{next: http.DefaultTransport, logger: slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), config: config}}
+	for _, path := range []string{"/api/rbac/ingestion-keys/list", "/api/search/values"} {
+		resp, err := client.Post(server.URL+path, "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatalf("POST %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "gc-ingestion-key") {
+		t.Errorf("expected the ingestion key to be redacted: %s", output)
+	}
+	if !strings.Contains(output, `\"key\":\"namespace\"`) {
+		t.Errorf("expected the search key to be kept: %s", output)
+	}
+}
+
+func TestWithLogger_FailureLevelAndTruncation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	sdkClient, err := NewSDKClient("token", "backend-1", server.URL,
+		WithLogger(logger, WithLogLevels(slog.LevelDebug, slog.LevelError), WithLogBodies(20)),
+	)
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	sdkClient.Apikeys.DeleteAPIKey(apikeys.NewDeleteAPIKeyParamsWithContext(context.Background()).WithID("key-1"), nil)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("expected a single JSON log line: %v (%s)", err, buf.String())
+	}
+	if entry["level"] != "ERROR" {
+		t.Errorf("expected failures to be logged at ERROR, got %v", entry["level"])
+	}
+	if got, _ := entry["response_body"].(string); !strings.HasSuffix(got, "...(truncated)") || len(got) != 20+len("...(truncated)") {
+		t.Errorf("expected a truncated response body, got %q", got)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
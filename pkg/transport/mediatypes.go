@@ -0,0 +1,206 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/go-openapi/runtime"
+	httptransport "github.com/go-openapi/runtime/client"
+)
+
+// Media types understood by the SDK.
+const (
+	MediaTypeJSON = runtime.JSONMime
+	MediaTypeYAML = "application/x-yaml"
+	MediaTypeText = runtime.TextMime
+)
+
+const mediaTypesOverrideKey contextKey = iota + 200
+
+// MediaTypes declares the media types of an operation. Empty fields keep the
+// media types of the generated client.
+type MediaTypes struct {
+	// ContentType is the media type of the request body.
+	ContentType string
+	// Accept is the media type requested for the response.
+	Accept string
+	// ResponseContentType decodes successful responses with this media type
+	// regardless of their Content-Type header, for endpoints that mislabel
+	// their responses.
+	ResponseContentType string
+}
+
+// merge returns m with the non-empty fields of override applied.
+func (m MediaTypes) merge(override MediaTypes) MediaTypes {
+	if override.ContentType != "" {
+		m.ContentType = override.ContentType
+	}
+	if override.Accept != "" {
+		m.Accept = override.Accept
+	}
+	if override.ResponseContentType != "" {
+		m.ResponseContentType = override.ResponseContentType
+	}
+	return m
+}
+
+// defaultMediaTypes lists the operations whose server-side media types
+// differ from the JSON default, keyed by operation name.
+var defaultMediaTypes = map[string]MediaTypes{
+	"monitors.CreateMonitor":   {ContentType: MediaTypeYAML, Accept: MediaTypeJSON},
+	"monitors.UpdateMonitor":   {ContentType: MediaTypeYAML, Accept: MediaTypeJSON},
+	"monitors.GetMonitor":      {Accept: MediaTypeYAML, ResponseContentType: MediaTypeYAML},
+	"workflows.CreateWorkflow": {ContentType: MediaTypeText, Accept: MediaTypeJSON},
+}
+
+// generatedMediaTypes lists the operations whose generated client declares
+// media types other than JSON, keyed by operation name.
+var generatedMediaTypes = map[string]MediaTypes{
+	"monitors.CreateMonitor":   {ContentType: MediaTypeYAML},
+	"monitors.UpdateMonitor":   {ContentType: MediaTypeYAML},
+	"monitors.GetMonitor":      {Accept: MediaTypeYAML},
+	"workflows.CreateWorkflow": {ContentType: MediaTypeText},
+}
+
+// generatedTypes returns the media types the generated client declares for
+// an operation.
+func generatedTypes(operation string) MediaTypes {
+	return MediaTypes{ContentType: MediaTypeJSON, Accept: MediaTypeJSON}.merge(generatedMediaTypes[operation])
+}
+
+// isMediaType reports whether mediaTypes is exactly mediaType.
+func isMediaType(mediaTypes []string, mediaType string) bool {
+	return len(mediaTypes) == 1 && mediaTypes[0] == mediaType
+}
+
+// MediaTypeRegistry maps operation names (e.g. "monitors.CreateMonitor") to
+// the media types used to call them.
+type MediaTypeRegistry struct {
+	mu         sync.RWMutex
+	operations map[string]MediaTypes
+}
+
+// NewMediaTypeRegistry returns a registry holding the SDK defaults.
+func NewMediaTypeRegistry() *MediaTypeRegistry {
+	operations := make(map[string]MediaTypes, len(defaultMediaTypes))
+	for name, types := range defaultMediaTypes {
+		operations[name] = types
+	}
+	return &MediaTypeRegistry{operations: operations}
+}
+
+// Register sets the media types of an operation, replacing any previous entry.
+func (r *MediaTypeRegistry) Register(operation string, types MediaTypes) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.operations[operation] = types
+}
+
+// Lookup returns the media types registered for an operation.
+func (r *MediaTypeRegistry) Lookup(operation string) (MediaTypes, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types, ok := r.operations[operation]
+	return types, ok
+}
+
+// WithMediaTypes registers the media types of an operation for every call
+// made by the client, on top of the SDK defaults.
+func WithMediaTypes(operation string, types MediaTypes) ClientOption {
+	return func(c *clientConfig) {
+		if c.mediaTypes == nil {
+			c.mediaTypes = NewMediaTypeRegistry()
+		}
+		c.mediaTypes.Register(operation, types)
+	}
+}
+
+// WithRequestMediaTypes returns a context that overrides the media types of
+// a single call, e.g. to send a monitor as JSON instead of YAML:
+//
+//	ctx = transport.WithRequestMediaTypes(ctx, transport.MediaTypes{ContentType: transport.MediaTypeJSON})
+func WithRequestMediaTypes(ctx context.Context, types MediaTypes) context.Context {
+	return context.WithValue(ctx, mediaTypesOverrideKey, types)
+}
+
+// ConfigureRuntimeTransport registers the SDK's producers and consumers on the
+// provided runtime transport: YAML in both directions, for models structs as
+// well as raw documents, and JSON that leaves raw targets undecoded.
+func ConfigureRuntimeTransport(rt *httptransport.Runtime) {
+	for _, mediaType := range []string{MediaTypeYAML, "application/yaml", "text/yaml"} {
+		rt.Consumers[mediaType] = YAMLConsumer()
+		rt.Producers[mediaType] = YAMLProducer()
+	}
+	rt.Consumers[MediaTypeJSON] = JSONConsumer()
+}
+
+// NewConfiguredRuntimeTransport creates a new runtime transport with
+// the SDK's standard configuration applied
+func NewConfiguredRuntimeTransport(host, basePath string, schemes []string) *httptransport.Runtime {
+	rt := httptransport.New(host, basePath, schemes)
+	ConfigureRuntimeTransport(rt)
+	return rt
+}
+
+// NewNegotiatingTransport wraps a runtime transport configured with
+// ConfigureRuntimeTransport so that every operation is sent with the media
+// types of registry. A nil registry uses the SDK defaults. NewSDKClient
+// applies it automatically.
+func NewNegotiatingTransport(rt *httptransport.Runtime, registry *MediaTypeRegistry) runtime.ClientTransport {
+	if registry == nil {
+		registry = NewMediaTypeRegistry()
+	}
+	return newOperationTransport(rt, mediaTypesMiddleware(registry, rt.Consumers))
+}
+
+// mediaTypesMiddleware applies the registered and per-call media types to
+// each operation. Registered media types apply only where the caller kept the
+// generated ones.
+func mediaTypesMiddleware(registry *MediaTypeRegistry, consumers map[string]runtime.Consumer) operationMiddleware {
+	return func(next submitFunc) submitFunc {
+		return func(op *runtime.ClientOperation) (interface{}, error) {
+			described, _ := OperationFromContext(op.Context)
+			types, _ := registry.Lookup(described.Name)
+
+			// Media types set through the generated WithContentType... and
+			// WithAccept... options take precedence over the registry.
+			generated := generatedTypes(described.Name)
+			if !isMediaType(op.ConsumesMediaTypes, generated.ContentType) {
+				types.ContentType = ""
+			}
+			if !isMediaType(op.ProducesMediaTypes, generated.Accept) {
+				types.Accept = ""
+				types.ResponseContentType = ""
+			}
+
+			override, overridden := op.Context.Value(mediaTypesOverrideKey).(MediaTypes)
+			if overridden {
+				// An explicit Accept means the caller expects the server to honour it.
+				if override.Accept != "" && override.ResponseContentType == "" {
+					types.ResponseContentType = ""
+				}
+				types = types.merge(override)
+			}
+
+			if types.ContentType != "" {
+				op.ConsumesMediaTypes = []string{types.ContentType}
+			}
+			if types.Accept != "" {
+				op.ProducesMediaTypes = []string{types.Accept}
+			}
+
+			if consumer, ok := consumers[types.ResponseContentType]; ok && types.ResponseContentType != "" {
+				reader := op.Reader
+				op.Reader = runtime.ClientResponseReaderFunc(func(response runtime.ClientResponse, defaultConsumer runtime.Consumer) (interface{}, error) {
+					if response.Code() >= http.StatusOK && response.Code() < http.StatusMultipleChoices {
+						return reader.ReadResponse(response, consumer)
+					}
+					return reader.ReadResponse(response, defaultConsumer)
+				})
+			}
+
+			return next(op)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/monitors"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/workflows"
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/models"
+)
+
+type capturedRequest struct {
+	contentType string
+	accept      string
+	body        string
+}
+
+func newMediaTypesServer(t *testing.T, responseType, responseBody string) (*httptest.Server, *capturedRequest) {
+	t.Helper()
+
+	captured := &capturedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		captured.contentType = r.Header.Get("Content-Type")
+		captured.accept = r.Header.Get("Accept")
+		captured.body = string(body)
+
+		w.Header().Set("Content-Type", responseType)
+		if r.URL.Path == "/api/workflows/create" {
+			w.WriteHeader(http.StatusAccepted)
+		}
+		w.Write([]byte(responseBody))
+	}))
+	t.Cleanup(server.Close)
+	return server, captured
+}
+
+func TestMediaTypesCreateMonitorDefaultsToYAML(t *testing.T) {
+	server, captured := newMediaTypesServer(t, MediaTypeJSON, `{"monitorId":"m-1"}`)
+
+	sdkClient, err := NewSDKClient("token", "backend-1", server.URL)
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	title := "High CPU"
+	params := monitors.NewCreateMonitorParamsWithContext(context.Background()).
+		WithBody(&models.CreateMonitorRequest{Title: &title, Severity: "S1"})
+	resp, err := sdkClient.Monitors.CreateMonitor(params, nil)
+	if err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+
+	if captured.contentType != MediaTypeYAML || captured.accept != MediaTypeJSON {
+		t.Errorf("expected YAML request and JSON response, got Content-Type %q, Accept %q", captured.contentType, captured.accept)
+	}
+	if !strings.Contains(captured.body, "title: High CPU") || !strings.Contains(captured.body, "severity: S1") {
+		t.Errorf("unexpected YAML body:\n%s", captured.body)
+	}
+	if resp.Payload.MonitorID != "m-1" {
+		t.Errorf("expected monitor ID m-1, got %s", resp.Payload.MonitorID)
+	}
+}
+
+func TestMediaTypesPerCallJSON(t *testing.T) {
+	server, captured := newMediaTypesServer(t, MediaTypeJSON, `{"monitorId":"m-1"}`)
+
+	sdkClient, err := NewSDKClient("token", "backend-1", server.URL)
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	ctx := WithRequestMediaTypes(context.Background(), MediaTypes{ContentType: MediaTypeJSON})
+	title := "High CPU"
+	params := monitors.NewCreateMonitorParamsWithContext(ctx).WithBody(&models.CreateMonitorRequest{Title: &title})
+	if _, err := sdkClient.Monitors.CreateMonitor(params, nil); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+
+	if captured.contentType != MediaTypeJSON {
+		t.Errorf("expected JSON request, got %q", captured.contentType)
+	}
+	if !strings.Contains(captured.body, `"title":"High CPU"`) {
+		t.Errorf("unexpected JSON body: %s", captured.body)
+	}
+}
+
+func TestMediaTypesMislabelledGetMonitor(t *testing.T) {
+	// The monitor endpoint returns YAML labelled as JSON.
+	server, captured := newMediaTypesServer(t, MediaTypeJSON, "title: High CPU\n")
+
+	sdkClient, err := NewSDKClient("token", "backend-1", server.URL)
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	resp, err := sdkClient.Monitors.GetMonitor(monitors.NewGetMonitorParamsWithContext(context.Background()).WithID("m-1"), nil)
+	if err != nil {
+		t.Fatalf("GetMonitor failed: %v", err)
+	}
+	if captured.accept != MediaTypeYAML {
+		t.Errorf("expected Accept %q, got %q", MediaTypeYAML, captured.accept)
+	}
+	if string(resp.Payload) != "title: High CPU\n" {
+		t.Errorf("unexpected payload: %q", resp.Payload)
+	}
+}
+
+func TestMediaTypesCreateWorkflow(t *testing.T) {
+	server, captured := newMediaTypesServer(t, MediaTypeJSON, `{"workflow_id":"w-1"}`)
+
+	sdkClient, err := NewSDKClient("token", "backend-1", server.URL)
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	definition := "workflow:\n  id: restart\n"
+	params := workflows.NewCreateWorkflowParamsWithContext(context.Background()).WithBody(definition)
+	resp, err := sdkClient.Workflows.CreateWorkflow(params, nil)
+	if err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+
+	if captured.contentType != MediaTypeText {
+		t.Errorf("expected Content-Type %q, got %q", MediaTypeText, captured.contentType)
+	}
+	if captured.body != definition {
+		t.Errorf("expected the definition to be sent unchanged, got %q", captured.body)
+	}
+	if resp.Payload.WorkflowID != "w-1" {
+		t.Errorf("expected workflow ID w-1, got %s", resp.Payload.WorkflowID)
+	}
+}
+
+func TestMediaTypesRuntimeTransportWithoutRegistry(t *testing.T) {
+	// Clients assembled by hand skip the registry but keep the endpoint fixes,
+	// such as decoding monitors served as plain text.
+	newClient := func(server *httptest.Server) *client.GroundcoverAPI {
+		rt := NewConfiguredRuntimeTransport(strings.TrimPrefix(server.URL, "http://"), client.DefaultBasePath, []string{"http"})
+		rt.Transport = NewTransport("token", "backend-1", http.DefaultTransport, 0, 0, 0, nil)
+		return client.New(rt, strfmt.Default)
+	}
+
+	server, _ := newMediaTypesServer(t, "text/plain; charset=utf-8", "title: High CPU\n")
+	resp, err := newClient(server).Monitors.GetMonitor(monitors.NewGetMonitorParamsWithContext(context.Background()).WithID("m-1"), nil)
+	if err != nil {
+		t.Fatalf("GetMonitor failed: %v", err)
+	}
+	if string(resp.Payload) != "title: High CPU\n" {
+		t.Errorf("unexpected payload: %q", resp.Payload)
+	}
+
+	server, captured := newMediaTypesServer(t, MediaTypeJSON, `{"workflow_id":"w-1"}`)
+	params := workflows.NewCreateWorkflowParamsWithContext(context.Background()).WithBody("workflow:\n  id: restart\n")
+	if _, err := newClient(server).Workflows.CreateWorkflow(params, nil); err != nil {
+		t.Fatalf("CreateWorkflow failed: %v", err)
+	}
+	if captured.contentType != MediaTypeText {
+		t.Errorf("expected Content-Type %q, got %q", MediaTypeText, captured.contentType)
+	}
+}
+
+func TestMediaTypesCallOptionsTakePrecedence(t *testing.T) {
+	server, captured := newMediaTypesServer(t, MediaTypeJSON, `{"monitorId":"m-1"}`)
+
+	sdkClient, err := NewSDKClient("token", "backend-1", server.URL)
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	title := "High CPU"
+	params := monitors.NewCreateMonitorParamsWithContext(context.Background()).
+		WithBody(&models.CreateMonitorRequest{Title: &title, Severity: "S1"})
+	if _, err := sdkClient.Monitors.CreateMonitor(params, nil, monitors.WithContentTypeApplicationJSON); err != nil {
+		t.Fatalf("CreateMonitor failed: %v", err)
+	}
+	if captured.contentType != MediaTypeJSON || !strings.HasPrefix(captured.body, `{"`) {
+		t.Errorf("expected the JSON call option to be kept, got Content-Type %q, body %q", captured.contentType, captured.body)
+	}
+
+	// A generated Accept option also drops the YAML decoding of GetMonitor.
+	server, captured = newMediaTypesServer(t, MediaTypeJSON, `"m-1"`)
+	if sdkClient, err = NewSDKClient("token", "backend-1", server.URL); err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+	getParams := monitors.NewGetMonitorParamsWithContext(context.Background()).WithID("m-1")
+	if _, err := sdkClient.Monitors.GetMonitor(getParams, nil, monitors.WithAcceptApplicationJSON); err != nil {
+		t.Fatalf("GetMonitor failed: %v", err)
+	}
+	if captured.accept != MediaTypeJSON {
+		t.Errorf("expected the JSON call option to be kept, got Accept %q", captured.accept)
+	}
+}
+
+func TestWithMediaTypes(t *testing.T) {
+	server, captured := newMediaTypesServer(t, MediaTypeYAML, "- id: 2b6d1d8e-3f43-4d6a-9d57-0a5e2d4a6f0e\n  comment: maintenance\n")
+
+	sdkClient, err := NewSDKClient("token", "backend-1", server.URL,
+		WithMediaTypes("monitors.GetAllSilences", MediaTypes{Accept: MediaTypeYAML}))
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	resp, err := sdkClient.Monitors.GetAllSilences(monitors.NewGetAllSilencesParamsWithContext(context.Background()), nil)
+	if err != nil {
+		t.Fatalf("GetAllSilences failed: %v", err)
+	}
+	if captured.accept != MediaTypeYAML {
+		t.Errorf("expected Accept %q, got %q", MediaTypeYAML, captured.accept)
+	}
+	if len(resp.Payload) != 1 || resp.Payload[0].Comment != "maintenance" || resp.Payload[0].UUID != "2b6d1d8e-3f43-4d6a-9d57-0a5e2d4a6f0e" {
+		t.Errorf("unexpected silences: %+v", resp.Payload)
+	}
+}
+
+func TestNewYamlByteConsumer(t *testing.T) {
+	var raw []byte
+	if err := NewYamlByteConsumer().Consume(strings.NewReader("title: High CPU\n"), &raw); err != nil || string(raw) != "title: High CPU\n" {
+		t.Errorf("expected the raw document, got %q: %v", raw, err)
+	}
+}
+
+func TestJSONToYAML(t *testing.T) {
+	got, err := JSONToYAML([]byte(`[{"name":"b","count":2,"ratio":0.5,"id":"007","tags":[]},{"name":"a"}]`))
+	if err != nil {
+		t.Fatalf("JSONToYAML failed: %v", err)
+	}
+
+	want := "- name: b\n  count: 2\n  ratio: 0.5\n  id: \"007\"\n  tags: []\n- name: a\n"
+	if string(got) != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	if got, err := JSONToYAML([]byte("null")); err != nil || string(got) != "null\n" {
+		t.Errorf("expected null, got %q: %v", got, err)
+	}
+	if _, err := JSONToYAML([]byte(`{"a":1} {"b":2}`)); err == nil {
+		t.Error("expected an error for data after the document")
+	}
+}
+
+func TestYAMLCodecRoundTrip(t *testing.T) {
+	startsAt := strfmt.DateTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	endsAt := strfmt.DateTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	silence := &models.CreateSilenceRequest{
+		Comment:  "deploy",
+		StartsAt: &startsAt,
+		EndsAt:   &endsAt,
+		Matchers: models.Matchers{{Name: "workload", Value: "api"}},
+	}
+
+	var buf bytes.Buffer
+	if err := YAMLProducer().Produce(&buf, silence); err != nil {
+		t.Fatalf("Produce failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "startsAt: \"2024-05-01T10:00:00.000Z\"") {
+		t.Errorf("expected json field names and quoted timestamps, got:\n%s", buf.String())
+	}
+
+	decoded := &models.CreateSilenceRequest{}
+	if err := YAMLConsumer().Consume(&buf, decoded); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+	if decoded.Comment != "deploy" || decoded.StartsAt.String() != startsAt.String() || decoded.EndsAt.String() != endsAt.String() {
+		t.Errorf("round trip mismatch: %+v", decoded)
+	}
+	if len(decoded.Matchers) != 1 || decoded.Matchers[0].Name != "workload" {
+		t.Errorf("unexpected matchers: %+v", decoded.Matchers)
+	}
+}
@@ -0,0 +1,169 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/runtime"
+)
+
+const (
+	operationKey contextKey = iota + 100
+	requestStatsKey
+	hedgeKey
+)
+
+// Operation describes the generated API operation an HTTP request belongs to.
+type Operation struct {
+	ID     string // swagger operation ID, e.g. "createMonitor"
+	Name   string // client package and method, e.g. "monitors.CreateMonitor"
+	Method string
+	Path   string // path pattern, e.g. "/api/monitors/{id}"
+}
+
+// OperationFromContext returns the operation the SDK attached to a request context.
+// It is available to every http.RoundTripper in the client's transport chain.
+func OperationFromContext(ctx context.Context) (Operation, bool) {
+	op, ok := ctx.Value(operationKey).(Operation)
+	return op, ok
+}
+
+// requestStats is shared by all attempts of a single operation.
+type requestStats struct {
+	mu         sync.Mutex
+	attempts   int
+	hedges     int
+	lastStatus int
+}
+
+func (s *requestStats) snapshot() (attempts, hedges, lastStatus int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts, s.hedges, s.lastStatus
+}
+
+// retries returns the number of attempts that retried a failed one. The
+// first attempt of each hedged duplicate is not a retry.
+func (s *requestStats) retries() int {
+	attempts, hedges, _ := s.snapshot()
+	return max(attempts-1-hedges, 0)
+}
+
+// hedgeMarker marks the context of a hedged duplicate request so that its
+// first attempt is counted as a hedge rather than a retry.
+type hedgeMarker struct {
+	counted bool
+}
+
+func requestStatsFromContext(ctx context.Context) *requestStats {
+	stats, _ := ctx.Value(requestStatsKey).(*requestStats)
+	return stats
+}
+
+// AttemptFromContext returns the 1-based attempt number of the request
+// currently in flight, or 0 if the context does not belong to an SDK operation.
+func AttemptFromContext(ctx context.Context) int {
+	stats := requestStatsFromContext(ctx)
+	if stats == nil {
+		return 0
+	}
+	attempts, _, _ := stats.snapshot()
+	return attempts
+}
+
+// attemptTransport sits below the retry layer and counts the attempts of each operation.
+type attemptTransport struct {
+	next http.RoundTripper
+}
+
+func (t *attemptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	stats := requestStatsFromContext(req.Context())
+	if stats != nil {
+		marker, _ := req.Context().Value(hedgeKey).(*hedgeMarker)
+		stats.mu.Lock()
+		stats.attempts++
+		if marker != nil && !marker.counted {
+			marker.counted = true
+			stats.hedges++
+		}
+		stats.mu.Unlock()
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if stats != nil && resp != nil {
+		stats.mu.Lock()
+		stats.lastStatus = resp.StatusCode
+		stats.mu.Unlock()
+	}
+	return resp, err
+}
+
+type submitFunc func(op *runtime.ClientOperation) (interface{}, error)
+
+// operationMiddleware decorates the submission of a generated client operation.
+type operationMiddleware func(next submitFunc) submitFunc
+
+// operationTransport is a runtime.ClientTransport that annotates every
+// operation's context with its Operation description before handing it to
+// the go-openapi runtime, and runs the configured operation middlewares.
+type operationTransport struct {
+	submit submitFunc
+}
+
+func newOperationTransport(next runtime.ClientTransport, middlewares ...operationMiddleware) *operationTransport {
+	submit := next.Submit
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		submit = middlewares[i](submit)
+	}
+
+	return &operationTransport{
+		submit: func(op *runtime.ClientOperation) (interface{}, error) {
+			ctx := op.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			ctx = context.WithValue(ctx, operationKey, describeOperation(op))
+			ctx = context.WithValue(ctx, requestStatsKey, &requestStats{})
+			op.Context = ctx
+
+			return submit(op)
+		},
+	}
+}
+
+// Submit implements runtime.ClientTransport.
+func (t *operationTransport) Submit(op *runtime.ClientOperation) (interface{}, error) {
+	return t.submit(op)
+}
+
+// describeOperation derives the operation name from the generated reader
+// type, e.g. *monitors.CreateMonitorReader becomes "monitors.CreateMonitor".
+func describeOperation(op *runtime.ClientOperation) Operation {
+	described := Operation{
+		ID:     op.ID,
+		Name:   op.ID,
+		Method: op.Method,
+		Path:   op.PathPattern,
+	}
+
+	if op.Reader == nil {
+		return described
+	}
+
+	readerType := reflect.TypeOf(op.Reader)
+	for readerType.Kind() == reflect.Ptr {
+		readerType = readerType.Elem()
+	}
+
+	pkgPath, typeName := readerType.PkgPath(), readerType.Name()
+	if pkgPath == "" || !strings.HasSuffix(typeName, "Reader") {
+		return described
+	}
+
+	pkg := pkgPath[strings.LastIndex(pkgPath, "/")+1:]
+	described.Name = pkg + "." + strings.TrimSuffix(typeName, "Reader")
+	return described
+}
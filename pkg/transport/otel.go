@@ -0,0 +1,196 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/groundcover-com/groundcover-sdk-go"
+
+// Attribute keys set on SDK spans and metrics.
+const (
+	AttributeOperation  = attribute.Key("groundcover.operation")
+	AttributeBackendID  = attribute.Key("groundcover.backend_id")
+	AttributeRetries    = attribute.Key("groundcover.retries")
+	AttributeHedges     = attribute.Key("groundcover.hedges")
+	AttributeStatusCode = attribute.Key("http.response.status_code")
+	AttributeMethod     = attribute.Key("http.request.method")
+	AttributeRoute      = attribute.Key("url.template")
+	AttributeError      = attribute.Key("error")
+)
+
+// OTelOption customizes the OpenTelemetry instrumentation.
+type OTelOption func(*otelConfig)
+
+type otelConfig struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the tracer provider. Defaults to the global provider.
+func WithTracerProvider(provider trace.TracerProvider) OTelOption {
+	return func(c *otelConfig) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider. Defaults to the global provider.
+func WithMeterProvider(provider metric.MeterProvider) OTelOption {
+	return func(c *otelConfig) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPropagators sets the propagator used to inject trace context into
+// requests. Defaults to the global propagator.
+func WithPropagators(propagator propagation.TextMapPropagator) OTelOption {
+	return func(c *otelConfig) {
+		c.propagator = propagator
+	}
+}
+
+// WithOpenTelemetry instruments every API call with a client span named after
+// the operation (e.g. "monitors.CreateMonitor"), injects the W3C trace context
+// of the span into the request headers and records per-operation request,
+// error, retry, hedge and latency metrics. Hedged duplicates sent by
+// WithHedging are counted as hedges, not retries.
+//
+// An explicit traceparent set with WithRequestTraceparent takes precedence
+// over the injected one.
+func WithOpenTelemetry(options ...OTelOption) ClientOption {
+	return func(c *clientConfig) {
+		config := &otelConfig{}
+		for _, option := range options {
+			option(config)
+		}
+		c.otel = config
+	}
+}
+
+type otelInstruments struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	retries  metric.Int64Counter
+	hedges   metric.Int64Counter
+}
+
+func newOTelInstruments(config *otelConfig) (*otelInstruments, error) {
+	if config.tracerProvider == nil {
+		config.tracerProvider = otel.GetTracerProvider()
+	}
+
+	if config.meterProvider == nil {
+		config.meterProvider = otel.GetMeterProvider()
+	}
+
+	if config.propagator == nil {
+		config.propagator = otel.GetTextMapPropagator()
+	}
+
+	meter := config.meterProvider.Meter(instrumentationName)
+	instruments := &otelInstruments{
+		tracer: config.tracerProvider.Tracer(instrumentationName),
+	}
+
+	var err error
+	if instruments.duration, err = meter.Float64Histogram("groundcover.sdk.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of groundcover API operations, including retries")); err != nil {
+		return nil, err
+	}
+
+	if instruments.requests, err = meter.Int64Counter("groundcover.sdk.requests",
+		metric.WithDescription("Number of groundcover API operations")); err != nil {
+		return nil, err
+	}
+
+	if instruments.errors, err = meter.Int64Counter("groundcover.sdk.request.errors",
+		metric.WithDescription("Number of failed groundcover API operations")); err != nil {
+		return nil, err
+	}
+
+	if instruments.retries, err = meter.Int64Counter("groundcover.sdk.request.retries",
+		metric.WithDescription("Number of retried attempts of groundcover API operations")); err != nil {
+		return nil, err
+	}
+
+	if instruments.hedges, err = meter.Int64Counter("groundcover.sdk.request.hedges",
+		metric.WithDescription("Number of hedged duplicate requests of groundcover API operations")); err != nil {
+		return nil, err
+	}
+
+	return instruments, nil
+}
+
+// otelMiddleware wraps each operation in a client span and records its metrics.
+func otelMiddleware(instruments *otelInstruments, backendID string) operationMiddleware {
+	return func(next submitFunc) submitFunc {
+		return func(op *runtime.ClientOperation) (interface{}, error) {
+			described, _ := OperationFromContext(op.Context)
+
+			ctx, span := instruments.tracer.Start(op.Context, described.Name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					AttributeOperation.String(described.Name),
+					AttributeMethod.String(described.Method),
+					AttributeRoute.String(described.Path),
+					AttributeBackendID.String(backendID),
+				),
+			)
+			defer span.End()
+			op.Context = ctx
+
+			start := time.Now()
+			result, err := next(op)
+			elapsed := time.Since(start)
+
+			var retries, hedges, status int
+			if stats := requestStatsFromContext(ctx); stats != nil {
+				_, hedges, status = stats.snapshot()
+				retries = stats.retries()
+			}
+
+			span.SetAttributes(AttributeRetries.Int(retries))
+			if hedges > 0 {
+				span.SetAttributes(AttributeHedges.Int(hedges))
+			}
+			if status != 0 {
+				span.SetAttributes(AttributeStatusCode.Int(status))
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			attrs := []attribute.KeyValue{
+				AttributeOperation.String(described.Name),
+				AttributeStatusCode.Int(status),
+				AttributeError.Bool(err != nil),
+			}
+			measurement := metric.WithAttributes(attrs...)
+			instruments.duration.Record(ctx, elapsed.Seconds(), measurement)
+			instruments.requests.Add(ctx, 1, measurement)
+			if err != nil {
+				instruments.errors.Add(ctx, 1, measurement)
+			}
+			if retries > 0 {
+				instruments.retries.Add(ctx, int64(retries), measurement)
+			}
+			if hedges > 0 {
+				instruments.hedges.Add(ctx, int64(hedges), measurement)
+			}
+
+			return result, err
+		}
+	}
+}
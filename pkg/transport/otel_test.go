@@ -0,0 +1,192 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/groundcover-com/groundcover-sdk-go/pkg/client/policies"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithOpenTelemetry_SpansAndPropagation(t *testing.T) {
+	var calls int32
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"uuid":"policy-1","name":"test"}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	sdkClient, err := NewSDKClient("api-key", "backend-1", server.URL,
+		WithRetryConfig(2, time.Millisecond, time.Millisecond, []int{http.StatusServiceUnavailable}),
+		WithOpenTelemetry(
+			WithTracerProvider(tracerProvider),
+			WithMeterProvider(meterProvider),
+			WithPropagators(propagation.TraceContext{}),
+		),
+	)
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	params := policies.NewGetPolicyParamsWithContext(context.Background()).WithID("policy-1")
+	if _, err := sdkClient.Policies.GetPolicy(params, nil); err != nil {
+		t.Fatalf("GetPolicy failed: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "policies.GetPolicy" {
+		t.Errorf("span name: expected policies.GetPolicy, got %s", span.Name())
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs[AttributeRetries].AsInt64(); got != 1 {
+		t.Errorf("retries: expected 1, got %d", got)
+	}
+	if got := attrs[AttributeStatusCode].AsInt64(); got != http.StatusOK {
+		t.Errorf("status: expected 200, got %d", got)
+	}
+	if got := attrs[AttributeBackendID].AsString(); got != "backend-1" {
+		t.Errorf("backend ID: expected backend-1, got %s", got)
+	}
+
+	traceID := span.SpanContext().TraceID().String()
+	for i, traceparent := range traceparents {
+		if !strings.Contains(traceparent, traceID) {
+			t.Errorf("attempt %d: expected traceparent with trace ID %s, got %q", i, traceID, traceparent)
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	found := map[string]bool{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			found[m.Name] = true
+		}
+	}
+	for _, name := range []string{"groundcover.sdk.request.duration", "groundcover.sdk.requests", "groundcover.sdk.request.retries"} {
+		if !found[name] {
+			t.Errorf("expected metric %s to be recorded", name)
+		}
+	}
+}
+
+func TestWithOpenTelemetry_HedgesAreNotRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			select {
+			case <-time.After(2 * time.Second):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"uuid":"policy-1","name":"test"}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	sdkClient, err := NewSDKClient("api-key", "backend-1", server.URL,
+		WithHedging(HedgingConfig{InitialDelay: 20 * time.Millisecond}),
+		WithOpenTelemetry(WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))),
+	)
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	params := policies.NewGetPolicyParamsWithContext(context.Background()).WithID("policy-1")
+	if _, err := sdkClient.Policies.GetPolicy(params, nil); err != nil {
+		t.Fatalf("GetPolicy failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected a hedged request, got %d requests", got)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs[AttributeRetries].AsInt64(); got != 0 {
+		t.Errorf("retries: expected 0, got %d", got)
+	}
+	if got := attrs[AttributeHedges].AsInt64(); got != 1 {
+		t.Errorf("hedges: expected 1, got %d", got)
+	}
+}
+
+func TestWithOpenTelemetry_ErrorsAndTraceparentOverride(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"policy not found"}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	sdkClient, err := NewSDKClient("api-key", "backend-1", server.URL, WithOpenTelemetry(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		WithPropagators(propagation.TraceContext{}),
+	))
+	if err != nil {
+		t.Fatalf("NewSDKClient failed: %v", err)
+	}
+
+	override := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	ctx := WithRequestTraceparent(context.Background(), override)
+	_, err = sdkClient.Policies.GetPolicy(policies.NewGetPolicyParamsWithContext(ctx).WithID("missing"), nil)
+
+	var notFound *policies.GetPolicyNotFound
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected GetPolicyNotFound, got %v", err)
+	}
+
+	if traceparent != override {
+		t.Errorf("expected traceparent override %q, got %q", override, traceparent)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+		t.Fatalf("expected one errored span, got %d spans", len(spans))
+	}
+}
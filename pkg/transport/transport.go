@@ -3,7 +3,7 @@ package transport
 import (
 	"context"
 	"fmt"
-	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -11,9 +11,9 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/rehttp"
-	httptransport "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
 	client "github.com/groundcover-com/groundcover-sdk-go/pkg/client"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 type contextKey int
@@ -28,7 +28,6 @@ const (
 	headerUserAgent     = "User-Agent"
 	headerTraceparent   = "traceparent"
 	userAgent           = "groundcover-go-sdk"
-	yamlContentType     = "application/x-yaml"
 )
 
 const (
@@ -37,61 +36,8 @@ const (
 	maxRetryWait      = 30 * time.Second
 )
 
-var getMonitorPathRegex = regexp.MustCompile(`^/api/monitors/[^/]+/?$`) // Matches /api/monitors/{id} but not /api/monitors/silences
-
-// yamlByteConsumer consumes application/x-yaml as raw bytes
-type yamlByteConsumer struct{}
-
-// Consume reads the response body directly into data without YAML parsing.
-// It expects 'data' to be a pointer to a []byte or similar slice type.
-func (c *yamlByteConsumer) Consume(reader io.Reader, data interface{}) error {
-	buf, err := io.ReadAll(reader)
-	if err != nil {
-		return err
-	}
-
-	// Check if data is a pointer to []byte or *[]byte
-	byteSlicePtr, ok := data.(*[]byte)
-	if !ok {
-		// Also handle *strfmt.Base64, which is essentially *[]byte
-		base64Ptr, ok := data.(*strfmt.Base64)
-		if !ok {
-			// Fallback: Try assigning to []uint8 if that's the underlying type
-			uint8SlicePtr, ok := data.(*[]uint8)
-			if !ok {
-				return fmt.Errorf("yamlByteConsumer requires data to be *[]byte, *[]uint8, or *strfmt.Base64, got %T for content type %s", data, yamlContentType)
-			}
-			*uint8SlicePtr = buf
-			return nil
-		}
-		*base64Ptr = buf
-		return nil
-	}
-
-	*byteSlicePtr = buf
-	return nil
-}
-
-// NewYamlByteConsumer returns a new instance of the YAML byte consumer
-// for use with go-openapi runtime transport consumers
-func NewYamlByteConsumer() *yamlByteConsumer {
-	return &yamlByteConsumer{}
-}
-
-// ConfigureRuntimeTransport configures the provided runtime transport with
-// the SDK's standard consumers and settings
-func ConfigureRuntimeTransport(rt *httptransport.Runtime) {
-	// Register the YAML byte consumer for application/x-yaml content type
-	rt.Consumers[yamlContentType] = NewYamlByteConsumer()
-}
-
-// NewConfiguredRuntimeTransport creates a new runtime transport with
-// the SDK's standard configuration applied
-func NewConfiguredRuntimeTransport(host, basePath string, schemes []string) *httptransport.Runtime {
-	rt := httptransport.New(host, basePath, schemes)
-	ConfigureRuntimeTransport(rt)
-	return rt
-}
+// getMonitorPathRegex matches /api/monitors/{id} but not /api/monitors/silences.
+var getMonitorPathRegex = regexp.MustCompile(`^/api/monitors/[^/]+/?$`)
 
 // ClientOption allows customization of the SDK client
 type ClientOption func(*clientConfig)
@@ -103,6 +49,13 @@ type clientConfig struct {
 	maxWait          time.Duration
 	retryStatuses    []int
 	transportWrapper func(http.RoundTripper) http.RoundTripper
+	circuitBreaker   *CircuitBreakerConfig
+	hedging          *HedgingConfig
+	otel             *otelConfig
+	logger           *slog.Logger
+	logConfig        *logConfig
+	unifiedErrors    bool
+	mediaTypes       *MediaTypeRegistry
 }
 
 // WithHTTPTransport sets a custom HTTP transport
@@ -129,6 +82,24 @@ func WithTransportWrapper(wrapper func(http.RoundTripper) http.RoundTripper) Cli
 	}
 }
 
+// WithCircuitBreaker enables a circuit breaker below the retry layer, so that
+// requests fail fast with a *CircuitOpenError while the API is unhealthy
+// instead of waiting on retries.
+func WithCircuitBreaker(config CircuitBreakerConfig) ClientOption {
+	return func(c *clientConfig) {
+		c.circuitBreaker = &config
+	}
+}
+
+// WithHedging enables hedged requests for idempotent calls: when a response
+// takes longer than the observed latency percentile, a second identical
+// request is sent and the first response to arrive is used.
+func WithHedging(config HedgingConfig) ClientOption {
+	return func(c *clientConfig) {
+		c.hedging = &config
+	}
+}
+
 // NewSDKClient creates a fully configured groundcover SDK client with all
 // standard configurations applied automatically. Use options to customize behavior.
 func NewSDKClient(apiKey, backendID, baseURL string, options ...ClientOption) (*client.GroundcoverAPI, error) {
@@ -168,29 +139,63 @@ func NewSDKClient(apiKey, backendID, baseURL string, options ...ClientOption) (*
 		schemes = client.DefaultSchemes
 	}
 
+	// The circuit breaker sits below the retry layer so that an open breaker
+	// short-circuits every attempt instead of being retried.
+	baseTransport := config.httpTransport
+	if config.circuitBreaker != nil {
+		baseTransport = NewCircuitBreaker(baseTransport, *config.circuitBreaker)
+	}
+	if config.logger != nil {
+		baseTransport = &loggingTransport{next: baseTransport, logger: config.logger, config: config.logConfig}
+	}
+	baseTransport = &attemptTransport{next: baseTransport}
+
 	// Create transport with SDK functionality
 	sdkTransport := NewTransport(
 		apiKey,
 		backendID,
-		config.httpTransport,
+		baseTransport,
 		config.retryCount,
 		config.minWait,
 		config.maxWait,
 		config.retryStatuses,
 	)
 
-	// Apply custom transport wrapper if provided
-	finalTransport := http.RoundTripper(sdkTransport)
-	if config.transportWrapper != nil {
-		finalTransport = config.transportWrapper(sdkTransport)
+	if config.mediaTypes == nil {
+		config.mediaTypes = NewMediaTypeRegistry()
 	}
 
 	// Create runtime transport with SDK configurations
 	runtimeTransport := NewConfiguredRuntimeTransport(host, basePath, schemes)
+
+	middlewares := []operationMiddleware{mediaTypesMiddleware(config.mediaTypes, runtimeTransport.Consumers)}
+	if config.otel != nil {
+		instruments, err := newOTelInstruments(config.otel)
+		if err != nil {
+			return nil, fmt.Errorf("error creating OpenTelemetry instruments: %v", err)
+		}
+		sdkTransport.propagator = config.otel.propagator
+		middlewares = append(middlewares, otelMiddleware(instruments, backendID))
+	}
+	if config.unifiedErrors {
+		middlewares = append(middlewares, unifiedErrorsMiddleware)
+	}
+
+	// Hedging wraps the retrying transport, so each hedged attempt retries on its own
+	finalTransport := http.RoundTripper(sdkTransport)
+	if config.hedging != nil {
+		finalTransport = NewHedgingTransport(finalTransport, *config.hedging)
+	}
+
+	// Apply custom transport wrapper if provided
+	if config.transportWrapper != nil {
+		finalTransport = config.transportWrapper(finalTransport)
+	}
+
 	runtimeTransport.Transport = finalTransport
 
 	// Create and return client
-	return client.New(runtimeTransport, strfmt.Default), nil
+	return client.New(newOperationTransport(runtimeTransport, middlewares...), strfmt.Default), nil
 }
 
 // WithRequestTraceparent returns a new context with the Traceparent override.
@@ -203,6 +208,7 @@ type transport struct {
 	apiKey         string
 	backendID      string
 	retryTransport http.RoundTripper
+	propagator     propagation.TextMapPropagator // injects the trace context when no traceparent override is set
 }
 
 // NewTransport creates a new transport.
@@ -274,12 +280,18 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 
 	if effectiveTraceparent != "" {
 		newReq.Header.Set(headerTraceparent, effectiveTraceparent)
+	} else if t.propagator != nil {
+		t.propagator.Inject(ctx, propagation.HeaderCarrier(newReq.Header))
 	}
 
-	// --- Fix Content-Type for specific endpoints ---
+	// Clients built from NewConfiguredRuntimeTransport without
+	// NewNegotiatingTransport do not apply the media type registry, so fix
+	// the media types of the endpoints that need it here.
+	_, negotiated := OperationFromContext(ctx)
+
 	// Fix request Content-Type for workflow create endpoint
-	if newReq.Method == http.MethodPost && newReq.URL.Path == "/api/workflows/create" {
-		newReq.Header.Set("Content-Type", "text/plain")
+	if !negotiated && newReq.Method == http.MethodPost && newReq.URL.Path == "/api/workflows/create" {
+		newReq.Header.Set("Content-Type", MediaTypeText)
 	}
 
 	// Execute the request
@@ -289,12 +301,11 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 
 	// Fix response Content-Type for monitor GET endpoints
-	if newReq.Method == http.MethodGet && resp.StatusCode == http.StatusOK &&
+	if !negotiated && newReq.Method == http.MethodGet && resp.StatusCode == http.StatusOK &&
 		getMonitorPathRegex.MatchString(newReq.URL.Path) &&
 		!strings.Contains(newReq.URL.Path, "silences") {
-		contentType := resp.Header.Get("Content-Type")
-		if contentType == "" || !strings.HasPrefix(contentType, yamlContentType) {
-			resp.Header.Set("Content-Type", yamlContentType)
+		if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, MediaTypeYAML) {
+			resp.Header.Set("Content-Type", MediaTypeYAML)
 		}
 	}
 